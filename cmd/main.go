@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"io"
 	"net"
@@ -12,6 +13,7 @@ import (
 	"syscall"
 
 	"github.com/alan-b-lima/almodon/internal/api/v1"
+	"github.com/alan-b-lima/almodon/internal/auth"
 	"github.com/alan-b-lima/almodon/internal/middleware"
 
 	"github.com/alan-b-lima/ansi-escape-sequences"
@@ -23,7 +25,15 @@ func main() {
 	log := middleware.NewLogger(StdOut, "")
 	style := Styles()
 
-	ln, err := net.Listen("tcp", ":4545")
+	addr, cfg := Flags()
+
+	handler, err := api.New(cfg)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	ln, err := net.Listen("tcp", addr)
 	if err != nil {
 		log.Println(err)
 		return
@@ -32,7 +42,7 @@ func main() {
 	url := "http://" + strings.Replace(ln.Addr().String(), "[::]", "localhost", 1)
 	log.Printf("Server listening at %s\n", style.HyperLink(url))
 
-	srv := http.Server{Handler: TrafficLogMiddleware(log, style, api.New())}
+	srv := http.Server{Handler: TrafficLogMiddleware(log, style, handler)}
 	done := EnableGracefulShutdown(func() {
 		log.Println("Closing...")
 		srv.Shutdown(context.Background())
@@ -45,6 +55,76 @@ func main() {
 	<-done
 }
 
+// Flags parses the command line, the SMTP and LDAP passwords and the
+// OIDC client secret are only read from the ALMODON_SMTP_PASSWORD,
+// ALMODON_LDAP_PASSWORD and ALMODON_OIDC_SECRET environment variables,
+// so they do not show up in the process list.
+func Flags() (string, api.Config) {
+	cfg := api.DefaultConfig()
+
+	addr := flag.String("addr", ":4545", "address to listen at")
+	flag.DurationVar(&cfg.Session.Idle, "session-idle", cfg.Session.Idle, "time a session survives without activity")
+	flag.DurationVar(&cfg.Session.Absolute, "session-absolute", cfg.Session.Absolute, "time a session survives since login, regardless of activity")
+	flag.Func("2fa-roles", "comma-separated roles that must log in with a second factor (default \"chief,promoted-admin\")", func(s string) error {
+		roles, err := Roles(s)
+		cfg.TwoFactor.Roles = roles
+		return err
+	})
+	flag.IntVar(&cfg.Password.MinLength, "password-min-length", cfg.Password.MinLength, "minimum length of passwords, in characters")
+	flag.IntVar(&cfg.Password.MaxLength, "password-max-length", cfg.Password.MaxLength, "maximum length of passwords, in characters")
+	flag.IntVar(&cfg.Password.Classes, "password-classes", cfg.Password.Classes, "how many of lowercase, uppercase, digits and symbols passwords must mix")
+	flag.BoolVar(&cfg.Password.Personal, "password-personal", cfg.Password.Personal, "forbid passwords containing the identities, name or email of the user")
+	flag.StringVar(&cfg.BreachedFile, "breached-passwords", cfg.BreachedFile, "file of SHA-1 hashes of breached passwords, ordered, as the Pwned Passwords downloads")
+	flag.StringVar(&cfg.Audit.KeyFile, "audit-key", cfg.Audit.KeyFile, "Ed25519 private key, in PKCS #8 PEM, audit checkpoints are signed with, generated at startup if empty")
+	flag.IntVar(&cfg.Audit.Checkpoint, "audit-checkpoint", cfg.Audit.Checkpoint, "records between signed checkpoints of the audit log")
+	flag.StringVar(&cfg.PolicyFile, "policy", cfg.PolicyFile, "JSON file overriding the roles allowed to perform each action")
+	flag.StringVar(&cfg.Mail.From, "mail-from", cfg.Mail.From, "sender address of notifications")
+	flag.StringVar(&cfg.Mail.AlertTo, "alert-to", cfg.Mail.AlertTo, "address alerts, as of low stock, are sent to, none are sent if empty")
+	flag.StringVar(&cfg.Mail.SMTPAddr, "smtp-addr", cfg.Mail.SMTPAddr, "SMTP server to deliver notifications through, as host:port")
+	flag.StringVar(&cfg.Mail.SMTPUser, "smtp-user", cfg.Mail.SMTPUser, "SMTP user, authentication is skipped if empty")
+	flag.StringVar(&cfg.Mail.Maildir, "maildir", cfg.Mail.Maildir, "maildir to write notifications to, if no SMTP server is given")
+	flag.StringVar(&cfg.LDAP.URL, "ldap-url", cfg.LDAP.URL, "LDAP directory to authenticate users against, as ldap://host or ldaps://host")
+	flag.BoolVar(&cfg.LDAP.StartTLS, "ldap-starttls", cfg.LDAP.StartTLS, "upgrade ldap:// connections to the directory with StartTLS")
+	flag.BoolVar(&cfg.LDAP.Insecure, "ldap-insecure", cfg.LDAP.Insecure, "allow ldap:// without StartTLS, sending passwords in the clear, for development only")
+	flag.StringVar(&cfg.LDAP.BindDN, "ldap-bind-dn", cfg.LDAP.BindDN, "LDAP service account entries are looked up with, anonymous if empty")
+	flag.StringVar(&cfg.LDAP.BaseDN, "ldap-base-dn", cfg.LDAP.BaseDN, "LDAP entry users are looked up below")
+	flag.StringVar(&cfg.LDAP.SIAPEAttr, "ldap-siape-attr", cfg.LDAP.SIAPEAttr, "LDAP attribute holding the SIAPE, not looked up if empty")
+	flag.StringVar(&cfg.LDAP.MatriculaAttr, "ldap-matricula-attr", cfg.LDAP.MatriculaAttr, "LDAP attribute holding the matrícula, not looked up if empty")
+	flag.StringVar(&cfg.LDAP.CPFAttr, "ldap-cpf-attr", cfg.LDAP.CPFAttr, "LDAP attribute holding the CPF, not looked up if empty")
+	flag.StringVar(&cfg.OIDC.Issuer, "oidc-issuer", cfg.OIDC.Issuer, "OpenID provider users may log in through, single sign-on is disabled if empty")
+	flag.StringVar(&cfg.OIDC.ClientID, "oidc-client-id", cfg.OIDC.ClientID, "client ID registered with the OpenID provider")
+	flag.StringVar(&cfg.OIDC.RedirectURL, "oidc-redirect-url", cfg.OIDC.RedirectURL, "callback registered with the OpenID provider, as https://host/api/v1/users/auth/oidc/callback")
+	flag.StringVar(&cfg.OIDC.SIAPEClaim, "oidc-siape-claim", cfg.OIDC.SIAPEClaim, "ID token claim holding the SIAPE, users are matched by email alone if empty")
+	flag.Parse()
+
+	cfg.Mail.SMTPPassword = os.Getenv("ALMODON_SMTP_PASSWORD")
+	cfg.LDAP.BindPassword = os.Getenv("ALMODON_LDAP_PASSWORD")
+	cfg.OIDC.ClientSecret = os.Getenv("ALMODON_OIDC_SECRET")
+
+	return *addr, cfg
+}
+
+// Roles parses a comma-separated list of roles, the empty string being
+// no roles at all.
+func Roles(s string) ([]auth.Role, error) {
+	var roles []auth.Role
+	for name := range strings.SplitSeq(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		role, ok := auth.FromString(name)
+		if !ok || !role.IsValid() {
+			return nil, fmt.Errorf("unknown role %q", name)
+		}
+
+		roles = append(roles, role)
+	}
+
+	return roles, nil
+}
+
 func TrafficLogMiddleware(log *middleware.Logger, s Style, handler http.Handler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		rw := middleware.NewW(w)
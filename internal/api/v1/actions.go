@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/domain/user"
+	"github.com/alan-b-lima/almodon/internal/support/resource"
+)
+
+type ActionsResponse struct {
+	Actions []auth.Action `json:"actions"`
+}
+
+// Actions lists the actions the actor of the request may perform, so
+// clients can hide what would be refused anyway. Actions on oneself,
+// which are always allowed, are listed only if allowed on others too.
+func Actions(policy *auth.Policy, users user.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		act, err := resource.Session(users, r)
+		if err != nil {
+			resource.WriteJsonError(w, err)
+			return
+		}
+
+		res := ActionsResponse{Actions: policy.Allowed(act.Role())}
+		if res.Actions == nil {
+			res.Actions = []auth.Action{}
+		}
+
+		if err := resource.EncodeJSON(&res, http.StatusOK, w, r); err != nil {
+			resource.WriteJsonError(w, err)
+			return
+		}
+	}
+}
@@ -1,44 +1,128 @@
 package api
 
 import (
+	"crypto/ed25519"
+	"log"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/alan-b-lima/almodon/internal/auth"
+	apitokenrepo "github.com/alan-b-lima/almodon/internal/domain/apitoken/repository"
+	"github.com/alan-b-lima/almodon/internal/domain/audit"
+	auditrepo "github.com/alan-b-lima/almodon/internal/domain/audit/repository"
+	audits "github.com/alan-b-lima/almodon/internal/domain/audit/resource"
+	auditserve "github.com/alan-b-lima/almodon/internal/domain/audit/service"
+	catmatrepo "github.com/alan-b-lima/almodon/internal/domain/catmat/repository"
+	catmats "github.com/alan-b-lima/almodon/internal/domain/catmat/resource"
+	catmatserve "github.com/alan-b-lima/almodon/internal/domain/catmat/service"
+	itemrepo "github.com/alan-b-lima/almodon/internal/domain/item/repository"
+	items "github.com/alan-b-lima/almodon/internal/domain/item/resource"
+	itemserve "github.com/alan-b-lima/almodon/internal/domain/item/service"
+	movementrepo "github.com/alan-b-lima/almodon/internal/domain/movement/repository"
+	movements "github.com/alan-b-lima/almodon/internal/domain/movement/resource"
+	movementserve "github.com/alan-b-lima/almodon/internal/domain/movement/service"
+	outboxrepo "github.com/alan-b-lima/almodon/internal/domain/outbox/repository"
+	recoveryrepo "github.com/alan-b-lima/almodon/internal/domain/recovery/repository"
 	sessionrepo "github.com/alan-b-lima/almodon/internal/domain/session/repository"
+	twofactorrepo "github.com/alan-b-lima/almodon/internal/domain/twofactor/repository"
+	"github.com/alan-b-lima/almodon/internal/domain/user"
 	userrepo "github.com/alan-b-lima/almodon/internal/domain/user/repository"
 	users "github.com/alan-b-lima/almodon/internal/domain/user/resource"
 	userserve "github.com/alan-b-lima/almodon/internal/domain/user/service"
+	"github.com/alan-b-lima/almodon/internal/middleware"
+	"github.com/alan-b-lima/almodon/internal/notify"
 )
 
 type router struct{ http.ServeMux }
 
-func New() http.Handler {
+func New(cfg Config) (http.Handler, error) {
 	var r router
 
+	if err := cfg.Session.Validate(); err != nil {
+		return nil, err
+	}
+
 	var (
-		repoSessions = sessionrepo.NewMap()
-		repoUsers    = userrepo.NewMap()
+		repoSessions  = sessionrepo.NewMap()
+		repoUsers     = userrepo.NewMap()
+		repoTokens    = recoveryrepo.NewMap()
+		repoAPITokens = apitokenrepo.NewMap()
+		repoFactors   = twofactorrepo.NewMap()
+		repoOutbox    = outboxrepo.NewMap()
+		repoCatmat    = catmatrepo.NewMap()
+		repoItems     = itemrepo.NewMap()
+		repoMovements = movementrepo.NewMap()
+		repoAudit     = auditrepo.NewMap()
 	)
 
-	serveUsers := userserve.New(userserve.NewService(repoUsers, repoSessions))
+	transport, err := cfg.Mail.transport()
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := cfg.policy(userserve.Actions(), catmatserve.Actions(), itemserve.Actions(), movementserve.Actions(), auditserve.Actions())
+	if err != nil {
+		return nil, err
+	}
+
+	for action, pred := range userserve.Grants() {
+		policy.Grant(action, pred)
+	}
+
+	passwords, err := cfg.passwords()
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := cfg.provider()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := cfg.Audit.key()
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := audit.NewChain(repoAudit, key, cfg.Audit.Checkpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	notifier := notify.NewOutbox(repoOutbox, transport, log.New(os.Stdout, "notify> ", log.Ldate|log.Ltime))
+
+	serveUsers := userserve.New(userserve.NewAudited(userserve.NewService(repoUsers, repoSessions, cfg.Session, repoTokens, repoAPITokens, repoFactors, cfg.TwoFactor, provider, cfg.sso(), notifier, user.NewThrottle(), passwords), chain), policy)
+	serveCatmat := catmatserve.New(catmatserve.NewAudited(catmatserve.NewService(repoCatmat), chain), policy)
+	serveItems := itemserve.New(itemserve.NewAudited(itemserve.NewService(repoItems, repoCatmat), chain), policy)
+	serveMovements := movementserve.New(movementserve.NewAudited(movementserve.NewService(repoMovements, repoItems, repoUsers, repoCatmat, notifier, cfg.Mail.AlertTo, time.Local), chain), policy)
+	serveAudit := auditserve.New(auditserve.NewService(repoAudit, key.Public().(ed25519.PublicKey)), policy)
 
 	resources := map[string]http.Handler{
-		"users": users.New(serveUsers),
+		"users":     users.New(serveUsers),
+		"catmat":    catmats.New(serveCatmat, serveUsers),
+		"items":     items.New(serveItems, serveUsers),
+		"movements": movements.New(serveMovements, serveUsers),
+		"audit":     audits.New(serveAudit, serveUsers),
 	}
 
 	for name, handler := range resources {
 		r.Handle("/api/v1/"+name+"/", http.StripPrefix("/api/v1", handler))
 	}
 
+	r.Handle("GET /api/v1/actions", Actions(policy, serveUsers))
+
 	// temp
 	{
-		repoUsers.Create(1, "Alan Barbosa Lima", "alan-lima.al@ufvjm.edu.br", "12345678", auth.Chief)
-		repoUsers.Create(2, "Breno Augusto Braga Oliveira", "b@ufvjm.edu.br", "12345678", auth.Admin)
-		repoUsers.Create(3, "Lucas Rocha Oliveira", "l@ufvjm.edu.br", "12345678", auth.User)
-		repoUsers.Create(4, "Luiz Felipe Melo Oliveira", "l@ufvjm.edu.br", "12345678", auth.Admin)
-		repoUsers.Create(5, "Otávio Gomes Calazans", "o@ufvjm.edu.br", "12345678", auth.User)
-		repoUsers.Create(6, "Rafael Gomes Silva", "r@ufvjm.edu.br", "12345678", auth.User)
+		repoUsers.Create(user.Identities{SIAPE: 1}, "Alan Barbosa Lima", "alan-lima.al@ufvjm.edu.br", "12345678", auth.Chief)
+		repoUsers.Create(user.Identities{SIAPE: 2}, "Breno Augusto Braga Oliveira", "b@ufvjm.edu.br", "12345678", auth.Admin)
+		repoUsers.Create(user.Identities{SIAPE: 3}, "Lucas Rocha Oliveira", "l@ufvjm.edu.br", "12345678", auth.User)
+		repoUsers.Create(user.Identities{SIAPE: 4}, "Luiz Felipe Melo Oliveira", "l@ufvjm.edu.br", "12345678", auth.Admin)
+		repoUsers.Create(user.Identities{SIAPE: 5}, "Otávio Gomes Calazans", "o@ufvjm.edu.br", "12345678", auth.User)
+		repoUsers.Create(user.Identities{SIAPE: 6}, "Rafael Gomes Silva", "r@ufvjm.edu.br", "12345678", auth.User)
 	}
 
-	return &r
+	csrf := middleware.NewCSRF()
+
+	return middleware.RequestID(csrf.Protect(middleware.RenewSession(repoSessions, cfg.Session, &r))), nil
 }
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"log"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/directory"
+	"github.com/alan-b-lima/almodon/internal/domain/session"
+	"github.com/alan-b-lima/almodon/internal/domain/twofactor"
+	"github.com/alan-b-lima/almodon/internal/domain/user"
+	"github.com/alan-b-lima/almodon/internal/notify"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/breach"
+)
+
+// Config holds the settings of the API.
+type Config struct {
+	Session   session.Policy
+	TwoFactor twofactor.Policy
+	Password  user.PasswordPolicy
+	Mail      MailConfig
+
+	// BreachedFile is the list of breached passwords, as described by
+	// package breach, new passwords are checked against, if given.
+	BreachedFile string
+
+	// LDAP is the directory users authenticate against, before their
+	// local passwords, if its URL is given.
+	LDAP directory.LDAPConfig
+
+	// OIDC is the identity provider users may log in through, if its
+	// issuer is given.
+	OIDC directory.OIDCConfig
+
+	// PolicyFile overrides the permissions of actions, as described by
+	// [auth.Policy.Load], if given.
+	PolicyFile string
+
+	Audit AuditConfig
+}
+
+// AuditConfig sets how checkpoints of the audit log are signed, with
+// the Ed25519 key of KeyFile, in PKCS #8 PEM, as written by "openssl
+// genpkey -algorithm ed25519", or with a key generated at startup if
+// none is given, one every Checkpoint records.
+type AuditConfig struct {
+	KeyFile    string
+	Checkpoint int
+}
+
+// MailConfig selects how notifications reach users. If an SMTP
+// address is given, messages are sent through it, otherwise, if a
+// maildir is given, they are written there, otherwise they are only
+// logged. Alerts, as of low stock, are sent to AlertTo, if given.
+type MailConfig struct {
+	From    string
+	AlertTo string
+
+	SMTPAddr     string
+	SMTPUser     string
+	SMTPPassword string
+
+	Maildir string
+}
+
+// DefaultConfig returns the settings used when none are given.
+func DefaultConfig() Config {
+	return Config{
+		Session:   session.DefaultPolicy(),
+		TwoFactor: twofactor.DefaultPolicy(),
+		Password:  user.DefaultPasswordPolicy(),
+		Mail:      MailConfig{From: "Almodon <almodon@localhost>"},
+		LDAP:      directory.DefaultLDAPConfig(),
+		OIDC:      directory.DefaultOIDCConfig(),
+		Audit:     AuditConfig{Checkpoint: 100},
+	}
+}
+
+// provider returns the provider users authenticate through, nil if
+// none is configured.
+func (c *Config) provider() (user.Provider, error) {
+	if c.LDAP.URL == "" {
+		return nil, nil
+	}
+
+	if err := c.LDAP.Validate(); err != nil {
+		return nil, err
+	}
+
+	return directory.NewLDAP(c.LDAP), nil
+}
+
+// sso returns the identity provider users log in through, nil if none
+// is configured.
+func (c *Config) sso() user.SSO {
+	if c.OIDC.Issuer == "" {
+		return nil
+	}
+
+	return directory.NewOIDC(c.OIDC)
+}
+
+// passwords returns the password policy, checking passwords against
+// the list of breached ones, if given.
+func (c *Config) passwords() (user.PasswordPolicy, error) {
+	policy := c.Password
+	if err := policy.Validate(); err != nil {
+		return user.PasswordPolicy{}, err
+	}
+
+	if c.BreachedFile == "" {
+		return policy, nil
+	}
+
+	list, err := breach.Open(c.BreachedFile)
+	if err != nil {
+		return user.PasswordPolicy{}, err
+	}
+
+	policy.Breached = list
+	return policy, nil
+}
+
+// policy registers the actions of services and applies the policy
+// file on top of their defaults.
+func (c *Config) policy(actions ...map[auth.Action]auth.Permission) (*auth.Policy, error) {
+	policy := auth.NewPolicy()
+	for _, actions := range actions {
+		policy.Register(actions)
+	}
+
+	if c.PolicyFile == "" {
+		return policy, nil
+	}
+
+	f, err := os.Open(c.PolicyFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if err := policy.Load(f); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (c *MailConfig) transport() (notify.Transport, error) {
+	from, err := mail.ParseAddress(c.From)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.SMTPAddr != "" {
+		var auth smtp.Auth
+		if c.SMTPUser != "" {
+			host, _, err := net.SplitHostPort(c.SMTPAddr)
+			if err != nil {
+				return nil, err
+			}
+
+			auth = smtp.PlainAuth("", c.SMTPUser, c.SMTPPassword, host)
+		}
+
+		return notify.NewSMTP(c.SMTPAddr, *from, auth), nil
+	}
+
+	if c.Maildir != "" {
+		return notify.NewMaildir(c.Maildir, *from)
+	}
+
+	return notify.NewLog(log.New(os.Stdout, "notify> ", log.Ldate|log.Ltime)), nil
+}
+
+func (c *AuditConfig) key() (ed25519.PrivateKey, error) {
+	if c.KeyFile == "" {
+		_, key, err := ed25519.GenerateKey(nil)
+		return key, err
+	}
+
+	buf, err := os.ReadFile(c.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(buf)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, xerrors.ErrAuditKey
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, xerrors.ErrAuditKey
+	}
+
+	if key, ok := key.(ed25519.PrivateKey); ok {
+		return key, nil
+	}
+
+	return nil, xerrors.ErrAuditKey
+}
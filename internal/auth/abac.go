@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"slices"
+
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+// Resource holds the attributes of what an action is performed on,
+// those not relevant to a resource are left zero.
+type Resource struct {
+	// Owner is the user the resource belongs to.
+	Owner uuid.UUID
+
+	// Location is where the resource is kept or delivered.
+	Location string
+
+	// Class is the CATMAT class of the material in the resource.
+	Class int
+}
+
+// Predicate decides whether the actor may act upon the resource based
+// on their attributes, as opposed to [Permission], that only looks at
+// the role of the actor.
+type Predicate func(act Actor, res Resource) bool
+
+// Logged holds for actors that are logged in.
+func Logged() Predicate {
+	return func(act Actor, res Resource) bool {
+		return act.role.IsValid()
+	}
+}
+
+// Permitted holds for actors whose role is authorized by perm, so
+// roles can be composed with the other predicates.
+func Permitted(perm Permission) Predicate {
+	return func(act Actor, res Resource) bool {
+		return perm.Authorize(act.role)
+	}
+}
+
+// Owner holds for logged actors that own the resource.
+func Owner() Predicate {
+	return func(act Actor, res Resource) bool {
+		return act.role.IsValid() && act.user == res.Owner
+	}
+}
+
+// Location holds for resources at any of the given locations.
+func Location(locations ...string) Predicate {
+	return func(act Actor, res Resource) bool {
+		return slices.Contains(locations, res.Location)
+	}
+}
+
+// Class holds for resources of any of the given CATMAT classes.
+func Class(classes ...int) Predicate {
+	return func(act Actor, res Resource) bool {
+		return slices.Contains(classes, res.Class)
+	}
+}
+
+// All holds if every one of the predicates holds, and for no
+// predicates at all.
+func All(preds ...Predicate) Predicate {
+	return func(act Actor, res Resource) bool {
+		for _, pred := range preds {
+			if !pred(act, res) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Any holds if at least one of the predicates holds.
+func Any(preds ...Predicate) Predicate {
+	return func(act Actor, res Resource) bool {
+		for _, pred := range preds {
+			if pred(act, res) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Not holds if the predicate does not.
+func Not(pred Predicate) Predicate {
+	return func(act Actor, res Resource) bool {
+		return !pred(act, res)
+	}
+}
@@ -0,0 +1,79 @@
+package auth_test
+
+import (
+	"testing"
+
+	. "github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+func TestPredicates(t *testing.T) {
+	var (
+		owner   = uuid.NewUUIDv7()
+		other   = uuid.NewUUIDv7()
+		res     = Resource{Owner: owner, Location: "lab-1", Class: 7510}
+		mine    = NewLogged(owner, User)
+		theirs  = NewLogged(other, Admin)
+		unknown = NewUnlogged()
+	)
+
+	tests := []struct {
+		name     string
+		pred     Predicate
+		act      Actor
+		res      Resource
+		expected bool
+	}{
+		{"owner", Owner(), mine, res, true},
+		{"not owner", Owner(), theirs, res, false},
+		{"unlogged owns nothing", Owner(), unknown, Resource{}, false},
+		{"logged", Logged(), mine, res, true},
+		{"unlogged", Logged(), unknown, res, false},
+		{"permitted", Permitted(Permit(Admin)), theirs, res, true},
+		{"not permitted", Permitted(Permit(Admin)), mine, res, false},
+		{"location", Location("lab-2", "lab-1"), theirs, res, true},
+		{"other location", Location("lab-2"), theirs, res, false},
+		{"class", Class(7510), theirs, res, true},
+		{"other class", Class(6505), theirs, res, false},
+		{"all", All(Permitted(Permit(Admin)), Location("lab-1")), theirs, res, true},
+		{"not all", All(Owner(), Location("lab-1")), theirs, res, false},
+		{"any", Any(Owner(), Class(7510)), theirs, res, true},
+		{"not any", Any(Owner(), Class(6505)), theirs, res, false},
+		{"not", Not(Owner()), theirs, res, true},
+	}
+
+	for _, test := range tests {
+		if got := test.pred(test.act, test.res); got != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestPolicyCheck(t *testing.T) {
+	p := newPolicy()
+	p.Grant("user.list", Owner())
+	p.Grant("user.list", All(Logged(), Location("lab-1")))
+
+	owner := uuid.NewUUIDv7()
+	act := NewLogged(owner, User)
+
+	if !p.Check("user.list", act, Resource{Owner: owner}) {
+		t.Error("owner should be granted the action")
+	}
+	if !p.Check("user.list", act, Resource{Location: "lab-1"}) {
+		t.Error("grants should be alternatives")
+	}
+	if p.Check("user.list", act, Resource{Location: "lab-2"}) {
+		t.Error("no grant holds, the role should decide")
+	}
+	if !p.Check("user.list", NewLogged(uuid.NewUUIDv7(), Admin), Resource{}) {
+		t.Error("permitted role should not need a grant")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("granting an unknown action should panic")
+		}
+	}()
+	p.Grant("user.unknown", Owner())
+}
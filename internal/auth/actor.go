@@ -6,8 +6,17 @@ import "github.com/alan-b-lima/almodon/pkg/uuid"
 // role. It is related the user entity, as a Actor is a shrinked
 // version of an user.
 type Actor struct {
-	user uuid.UUID
-	role Role
+	user   uuid.UUID
+	role   Role
+	origin Origin
+}
+
+// Origin is where an actor acts from: the address of the client and
+// the ID of the request, as they are recorded along what the actor
+// does.
+type Origin struct {
+	Addr    string
+	Request string
 }
 
 // NewLogged creates a new actor. This function does not check
@@ -34,3 +43,14 @@ func (act *Actor) User() uuid.UUID {
 func (act *Actor) Role() Role {
 	return act.role
 }
+
+// Origin returns where the actor acts from.
+func (act *Actor) Origin() Origin {
+	return act.origin
+}
+
+// From returns a copy of the actor acting from the origin.
+func (act Actor) From(origin Origin) Actor {
+	act.origin = origin
+	return act
+}
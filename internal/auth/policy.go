@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+)
+
+// Action names an operation subject to authorization, namespaced by
+// the domain it belongs to, such as "user.create".
+type Action string
+
+// Policy maps actions to the permissions required to perform them.
+//
+// Services register the actions they check along with their default
+// permissions, which a policy file may then override. Actions missing
+// from the policy are denied to everyone. A policy is meant to be set
+// up at startup, it is not safe to change it while it is checked.
+//
+// Actions may also be granted on the attributes of the actor and of
+// the resource, see [Policy.Grant], regardless of the role of the
+// actor.
+type Policy struct {
+	perms  map[Action]Permission
+	grants map[Action]Predicate
+}
+
+// NewPolicy creates an empty policy.
+func NewPolicy() *Policy {
+	return &Policy{
+		perms:  make(map[Action]Permission),
+		grants: make(map[Action]Predicate),
+	}
+}
+
+// Register adds actions to the policy with their default permissions.
+// It panics if an action is registered twice, as that is a mistake
+// in the wiring of services.
+func (p *Policy) Register(actions map[Action]Permission) {
+	for action, perm := range actions {
+		if _, in := p.perms[action]; in {
+			panic(fmt.Sprintf("auth: action %q registered twice", action))
+		}
+
+		p.perms[action] = perm
+	}
+}
+
+// Grant allows the action whenever the predicate holds, in addition to
+// the roles authorized by its permission. Grants on the same action
+// are alternatives, any of them allows it. Grants are part of the
+// semantics of actions, and are not overridden by [Policy.Load]. It
+// panics if the action is not registered.
+func (p *Policy) Grant(action Action, pred Predicate) {
+	if _, in := p.perms[action]; !in {
+		panic(fmt.Sprintf("auth: action %q granted before registered", action))
+	}
+
+	if grant, in := p.grants[action]; in {
+		pred = Any(grant, pred)
+	}
+
+	p.grants[action] = pred
+}
+
+// Permission returns the permission required to perform the action,
+// the zero [Permission], which authorizes no role, if it is unknown.
+func (p *Policy) Permission(action Action) Permission {
+	return p.perms[action]
+}
+
+// Authorize returns whether the role may perform the action.
+func (p *Policy) Authorize(action Action, role Role) bool {
+	perm := p.perms[action]
+	return perm.Authorize(role)
+}
+
+// Check returns whether the actor may perform the action on the
+// resource, either by role or by any of the grants of the action.
+func (p *Policy) Check(action Action, act Actor, res Resource) bool {
+	if p.Authorize(action, act.role) {
+		return true
+	}
+
+	grant, in := p.grants[action]
+	return in && grant(act, res)
+}
+
+// Allowed returns the actions the role may perform on any resource,
+// sorted, grants are not taken into account.
+func (p *Policy) Allowed(role Role) []Action {
+	var actions []Action
+	for action, perm := range p.perms {
+		if perm.Authorize(role) {
+			actions = append(actions, action)
+		}
+	}
+
+	slices.Sort(actions)
+	return actions
+}
+
+// Actions returns every action in the policy, sorted.
+func (p *Policy) Actions() []Action {
+	return slices.Sorted(maps.Keys(p.perms))
+}
+
+// Load overrides the permissions of actions with the ones read from
+// r, a JSON object mapping each action to the roles authorized to
+// perform it, according to the default hierarchy:
+//
+//	{
+//		"user.create": ["chief"],
+//		"catmat.import": ["promoted-admin"]
+//	}
+//
+// Actions not mentioned keep their permissions. Unknown actions or
+// roles are an error, and leave the policy untouched.
+func (p *Policy) Load(r io.Reader) error {
+	var file map[Action][]string
+
+	dec := json.NewDecoder(r)
+	if err := dec.Decode(&file); err != nil {
+		return fmt.Errorf("auth: policy: %w", err)
+	}
+
+	perms := make(map[Action]Permission, len(file))
+	for action, names := range file {
+		if _, in := p.perms[action]; !in {
+			return fmt.Errorf("auth: policy: unknown action %q", action)
+		}
+
+		roles := make([]Role, len(names))
+		for i, name := range names {
+			role, ok := FromString(name)
+			if !ok {
+				return fmt.Errorf("auth: policy: action %q: unknown role %q", action, name)
+			}
+
+			roles[i] = role
+		}
+
+		perms[action] = Permit(roles...)
+	}
+
+	maps.Copy(p.perms, perms)
+	return nil
+}
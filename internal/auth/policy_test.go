@@ -0,0 +1,70 @@
+package auth_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/alan-b-lima/almodon/internal/auth"
+)
+
+func newPolicy() *Policy {
+	p := NewPolicy()
+	p.Register(map[Action]Permission{
+		"user.create":   Permit(Chief),
+		"user.list":     Permit(Admin),
+		"catmat.import": Permit(Promoted),
+	})
+
+	return p
+}
+
+func TestPolicyAuthorize(t *testing.T) {
+	p := newPolicy()
+
+	if !p.Authorize("user.list", Promoted) {
+		t.Error("promoted should inherit the permissions of admin")
+	}
+	if p.Authorize("user.create", Admin) {
+		t.Error("admin should not create users")
+	}
+	if p.Authorize("user.unknown", Chief) {
+		t.Error("unknown actions should be denied")
+	}
+
+	expected := []Action{"catmat.import", "user.list"}
+	if got := p.Allowed(Promoted); !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestPolicyLoad(t *testing.T) {
+	p := newPolicy()
+
+	err := p.Load(strings.NewReader(`{"user.create": ["promoted-admin"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !p.Authorize("user.create", Promoted) {
+		t.Error("loaded permission should apply")
+	}
+	if p.Authorize("user.list", User) || !p.Authorize("user.list", Admin) {
+		t.Error("actions not mentioned should keep their permissions")
+	}
+
+	bad := []string{
+		`{"user.delete": ["chief"]}`,
+		`{"user.create": ["root"]}`,
+		`["user.create"]`,
+	}
+	for _, file := range bad {
+		if err := p.Load(strings.NewReader(file)); err == nil {
+			t.Errorf("%s: expected an error", file)
+		}
+	}
+
+	if !p.Authorize("user.create", Promoted) {
+		t.Error("failed loads should leave the policy untouched")
+	}
+}
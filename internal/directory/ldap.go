@@ -0,0 +1,237 @@
+// Package directory implements the providers users may authenticate
+// through, instead of their local passwords.
+package directory
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/domain/user"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/ldap"
+)
+
+// LDAPConfig describes how the directory is reached and how its
+// entries map to users.
+type LDAPConfig struct {
+	// URL of the server, of scheme ldap or ldaps. Over ldap, passwords
+	// would be sent in the clear, so the connection must be upgraded
+	// with StartTLS, unless Insecure is set, for development only.
+	URL      string
+	StartTLS bool
+	Insecure bool
+
+	// BindDN and BindPassword are the service account entries are
+	// looked up with, the lookup is anonymous if BindDN is empty.
+	BindDN       string
+	BindPassword string
+
+	// BaseDN is where entries are looked up below.
+	BaseDN string
+
+	// SIAPEAttr, MatriculaAttr and CPFAttr name the attributes holding
+	// each kind of identity. Kinds without an attribute are not looked
+	// up, their users only log in with their local passwords.
+	SIAPEAttr     string
+	MatriculaAttr string
+	CPFAttr       string
+
+	NameAttr string
+	MailAttr string
+
+	Timeout time.Duration
+	TLS     *tls.Config
+}
+
+// DefaultLDAPConfig returns the attributes of the inetOrgPerson
+// schema, where staff are looked up by employeeNumber.
+func DefaultLDAPConfig() LDAPConfig {
+	return LDAPConfig{
+		SIAPEAttr: "employeeNumber",
+		NameAttr:  "cn",
+		MailAttr:  "mail",
+		Timeout:   5 * time.Second,
+	}
+}
+
+// Validate reports whether the directory is reached securely: ldaps
+// URLs always are, ldap ones only if upgraded with StartTLS, unless
+// explicitly allowed not to be.
+func (cfg LDAPConfig) Validate() error {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return xerrors.ErrDirectoryConfig.New(err.Error())
+	}
+
+	switch u.Scheme {
+	case "ldaps":
+		if cfg.StartTLS {
+			return xerrors.ErrDirectoryConfig.New("StartTLS is not used over ldaps")
+		}
+
+	case "ldap":
+		if !cfg.StartTLS && !cfg.Insecure {
+			return xerrors.ErrDirectoryConfig.New("ldap URLs require StartTLS, or to be explicitly allowed as insecure")
+		}
+
+	default:
+		return xerrors.ErrDirectoryConfig.New(fmt.Sprintf("unsupported scheme %q", u.Scheme))
+	}
+
+	return nil
+}
+
+// LDAP is a [user.Provider] that verifies passwords by binding as the
+// entry of the identity. Each login takes a connection of its own.
+type LDAP struct {
+	cfg LDAPConfig
+}
+
+func NewLDAP(cfg LDAPConfig) *LDAP {
+	return &LDAP{cfg: cfg}
+}
+
+// Authenticate implements the [user.Provider] interface.
+func (d *LDAP) Authenticate(id user.Identity, password string) (user.Account, error) {
+	filter, ok := d.filter(id)
+	if !ok {
+		return user.Account{}, xerrors.ErrDirectoryNoAccount
+	}
+
+	conn, err := ldap.Dial(d.cfg.URL, d.cfg.TLS, d.cfg.Timeout)
+	if err != nil {
+		return user.Account{}, xerrors.ErrDirectoryUnavailable.New(err)
+	}
+	defer conn.Close()
+
+	if d.cfg.StartTLS {
+		if err := conn.StartTLS(d.tlsConfig()); err != nil {
+			return user.Account{}, xerrors.ErrDirectoryUnavailable.New(err)
+		}
+	}
+
+	if d.cfg.BindDN != "" {
+		if err := conn.Bind(d.cfg.BindDN, d.cfg.BindPassword); err != nil {
+			return user.Account{}, xerrors.ErrDirectoryUnavailable.New(err)
+		}
+	}
+
+	entries, err := conn.Search(ldap.SearchRequest{
+		BaseDN:     d.cfg.BaseDN,
+		Scope:      ldap.ScopeSub,
+		Filter:     filter,
+		Attributes: d.attributes(),
+		SizeLimit:  2,
+	})
+	if err != nil {
+		return user.Account{}, xerrors.ErrDirectoryUnavailable.New(err)
+	}
+
+	switch len(entries) {
+	case 0:
+		return user.Account{}, xerrors.ErrDirectoryNoAccount
+	case 1:
+	default:
+		return user.Account{}, xerrors.ErrDirectoryUnavailable.New(fmt.Errorf("identity %v matches many entries", id))
+	}
+
+	entry := &entries[0]
+
+	err = conn.Bind(entry.DN, password)
+	if err == ldap.ErrEmptyPassword || ldap.IsCode(err, ldap.ResultInvalidCredentials) {
+		return user.Account{}, xerrors.ErrIncorrectPassword
+	}
+	if err != nil {
+		return user.Account{}, xerrors.ErrDirectoryUnavailable.New(err)
+	}
+
+	return d.account(id, entry), nil
+}
+
+// tlsConfig returns the configuration of StartTLS, verifying the
+// host of the URL by default.
+func (d *LDAP) tlsConfig() *tls.Config {
+	if d.cfg.TLS != nil {
+		return d.cfg.TLS
+	}
+
+	u, _ := url.Parse(d.cfg.URL)
+	return &tls.Config{ServerName: u.Hostname()}
+}
+
+// filter matches the entries of the identity, in the forms directories
+// usually hold it.
+func (d *LDAP) filter(id user.Identity) (ldap.Filter, bool) {
+	switch id.Kind {
+	case user.KindSIAPE:
+		if d.cfg.SIAPEAttr == "" {
+			return ldap.Filter{}, false
+		}
+
+		siape, _ := strconv.Atoi(id.Value)
+		return ldap.Or(
+			ldap.Equal(d.cfg.SIAPEAttr, id.Value),
+			ldap.Equal(d.cfg.SIAPEAttr, fmt.Sprintf("%07d", siape)),
+		), true
+
+	case user.KindMatricula:
+		if d.cfg.MatriculaAttr == "" {
+			return ldap.Filter{}, false
+		}
+
+		return ldap.Equal(d.cfg.MatriculaAttr, id.Value), true
+
+	case user.KindCPF:
+		if d.cfg.CPFAttr == "" {
+			return ldap.Filter{}, false
+		}
+
+		v := id.Value
+		return ldap.Or(
+			ldap.Equal(d.cfg.CPFAttr, v),
+			ldap.Equal(d.cfg.CPFAttr, v[:3]+"."+v[3:6]+"."+v[6:9]+"-"+v[9:]),
+		), true
+	}
+
+	return ldap.Filter{}, false
+}
+
+func (d *LDAP) attributes() []string {
+	var attrs []string
+	for _, attr := range [...]string{d.cfg.SIAPEAttr, d.cfg.MatriculaAttr, d.cfg.CPFAttr, d.cfg.NameAttr, d.cfg.MailAttr} {
+		if attr != "" {
+			attrs = append(attrs, attr)
+		}
+	}
+
+	return attrs
+}
+
+// account maps the entry to an account. Identities the entry holds in
+// a malformed way are left out, rather than failing the login, but
+// the one logged in with is always there.
+func (d *LDAP) account(id user.Identity, entry *ldap.Entry) user.Account {
+	var acc user.Account
+
+	if siape, err := strconv.Atoi(entry.Value(d.cfg.SIAPEAttr)); err == nil {
+		acc.SIAPE, _ = user.ProcessSiape(siape)
+	}
+	acc.Matricula, _ = user.ProcessMatricula(entry.Value(d.cfg.MatriculaAttr))
+	acc.CPF, _ = user.ProcessCPF(entry.Value(d.cfg.CPFAttr))
+
+	switch id.Kind {
+	case user.KindSIAPE:
+		acc.SIAPE, _ = strconv.Atoi(id.Value)
+	case user.KindMatricula:
+		acc.Matricula = id.Value
+	case user.KindCPF:
+		acc.CPF = id.Value
+	}
+
+	acc.Name = entry.Value(d.cfg.NameAttr)
+	acc.Email = entry.Value(d.cfg.MailAttr)
+	return acc
+}
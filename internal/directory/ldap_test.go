@@ -0,0 +1,124 @@
+package directory_test
+
+import (
+	"crypto/tls"
+	"testing"
+	"time"
+
+	. "github.com/alan-b-lima/almodon/internal/directory"
+	"github.com/alan-b-lima/almodon/internal/domain/user"
+	"github.com/alan-b-lima/almodon/pkg/errors"
+	"github.com/alan-b-lima/almodon/pkg/ldap"
+	"github.com/alan-b-lima/almodon/pkg/ldap/ldaptest"
+)
+
+const base = "ou=people,dc=ufvjm,dc=edu,dc=br"
+
+func newLDAP(t *testing.T) (*LDAP, *ldaptest.Server) {
+	srv := ldaptest.NewServer()
+	t.Cleanup(srv.Close)
+
+	srv.Add(ldap.Entry{DN: "cn=almodon,dc=ufvjm,dc=edu,dc=br"}, "service")
+	srv.Add(ldap.Entry{
+		DN: "uid=alan," + base,
+		Attributes: map[string][]string{
+			"cn":             {"Alan Barbosa Lima"},
+			"mail":           {"alan@ufvjm.edu.br"},
+			"employeeNumber": {"0123456"},
+			"brPersonCPF":    {"529.982.247-25"},
+		},
+	}, "secret")
+
+	cfg := DefaultLDAPConfig()
+	cfg.URL = srv.URL
+	cfg.StartTLS = true
+	cfg.TLS = &tls.Config{ServerName: "127.0.0.1", RootCAs: srv.StartTLS()}
+	cfg.BindDN = "cn=almodon,dc=ufvjm,dc=edu,dc=br"
+	cfg.BindPassword = "service"
+	cfg.BaseDN = base
+	cfg.CPFAttr = "brPersonCPF"
+	cfg.Timeout = time.Second
+
+	return NewLDAP(cfg), srv
+}
+
+func kind(err error) errors.Kind {
+	if err, ok := errors.AsType[*errors.Error](err); ok {
+		return err.Kind
+	}
+
+	return 0
+}
+
+func TestAuthenticate(t *testing.T) {
+	d, _ := newLDAP(t)
+
+	for _, login := range []string{"123456", "52998224725"} {
+		id, err := user.ParseIdentity(login)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		acc, err := d.Authenticate(id, "secret")
+		if err != nil {
+			t.Fatalf("%s: %v", login, err)
+		}
+
+		expected := user.Account{
+			Identities: user.Identities{SIAPE: 123456, CPF: "52998224725"},
+			Name:       "Alan Barbosa Lima",
+			Email:      "alan@ufvjm.edu.br",
+		}
+		if acc != expected {
+			t.Errorf("%s: expected %+v, got %+v", login, expected, acc)
+		}
+	}
+}
+
+func TestAuthenticateFailures(t *testing.T) {
+	d, srv := newLDAP(t)
+	siape := user.SIAPEIdentity(123456)
+
+	if _, err := d.Authenticate(siape, "wrong"); kind(err) != errors.Unauthorized {
+		t.Errorf("wrong password: expected unauthorized, got %v", err)
+	}
+
+	if _, err := d.Authenticate(siape, ""); kind(err) != errors.Unauthorized {
+		t.Errorf("empty password: expected unauthorized, got %v", err)
+	}
+
+	if _, err := d.Authenticate(user.SIAPEIdentity(7654321), "secret"); kind(err) != errors.NotFound {
+		t.Errorf("unknown SIAPE: expected not found, got %v", err)
+	}
+
+	matricula := user.Identity{Kind: user.KindMatricula, Value: "2020123457"}
+	if _, err := d.Authenticate(matricula, "secret"); kind(err) != errors.NotFound {
+		t.Errorf("kind not looked up: expected not found, got %v", err)
+	}
+
+	srv.Close()
+	if _, err := d.Authenticate(siape, "secret"); kind(err) != errors.Unavailable {
+		t.Errorf("server down: expected unavailable, got %v", err)
+	}
+}
+
+func TestLDAPConfigValidate(t *testing.T) {
+	tests := []struct {
+		cfg LDAPConfig
+		ok  bool
+	}{
+		{LDAPConfig{URL: "ldaps://ldap.ufvjm.edu.br"}, true},
+		{LDAPConfig{URL: "ldap://ldap.ufvjm.edu.br", StartTLS: true}, true},
+		{LDAPConfig{URL: "ldap://127.0.0.1:3389", Insecure: true}, true},
+
+		{LDAPConfig{URL: "ldap://ldap.ufvjm.edu.br"}, false},
+		{LDAPConfig{URL: "ldaps://ldap.ufvjm.edu.br", StartTLS: true}, false},
+		{LDAPConfig{URL: "http://ldap.ufvjm.edu.br", Insecure: true}, false},
+	}
+
+	for _, test := range tests {
+		if err := test.cfg.Validate(); (err == nil) != test.ok {
+			t.Errorf("%+v: expected ok %v, got error %v", test.cfg, test.ok, err)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package directory
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/domain/user"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/oidc"
+)
+
+// OIDCConfig describes the OpenID provider and how the claims of its
+// ID tokens map to users.
+type OIDCConfig struct {
+	// Issuer of the provider, whose discovery document is fetched on
+	// the first login.
+	Issuer string
+
+	ClientID     string
+	ClientSecret string
+
+	// RedirectURL is the callback the provider sends users back to.
+	RedirectURL string
+
+	// SIAPEClaim names the claim holding the SIAPE, users are mapped
+	// by their verified email alone if it is empty.
+	SIAPEClaim string
+
+	// Timeout of the requests to the provider, and Expiry of logins
+	// left unfinished.
+	Timeout time.Duration
+	Expiry  time.Duration
+}
+
+// DefaultOIDCConfig returns the claims of the institutional provider.
+func DefaultOIDCConfig() OIDCConfig {
+	return OIDCConfig{
+		SIAPEClaim: "siape",
+		Timeout:    5 * time.Second,
+		Expiry:     10 * time.Minute,
+	}
+}
+
+// maxPending bounds the logins in progress, as anyone can start them.
+const maxPending = 10000
+
+// OIDC is a [user.SSO] through the authorization code flow, with PKCE.
+// Logins in progress are kept in memory until finished or expired.
+type OIDC struct {
+	cfg    OIDCConfig
+	client *http.Client
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+	pending  map[string]pending
+}
+
+type pending struct {
+	verifier string
+	nonce    string
+	expires  time.Time
+}
+
+func NewOIDC(cfg OIDCConfig) *OIDC {
+	return &OIDC{
+		cfg:     cfg,
+		client:  &http.Client{Timeout: cfg.Timeout},
+		pending: make(map[string]pending),
+	}
+}
+
+// Begin implements the [user.SSO] interface.
+func (d *OIDC) Begin() (string, string, error) {
+	p, err := d.discover()
+	if err != nil {
+		return "", "", err
+	}
+
+	state, nonce := oidc.NewState(), oidc.NewState()
+	verifier, challenge := oidc.NewPKCE()
+
+	if err := d.hold(state, pending{verifier, nonce, time.Now().Add(d.cfg.Expiry)}); err != nil {
+		return "", "", err
+	}
+
+	return p.AuthCodeURL(d.config(), state, nonce, challenge), state, nil
+}
+
+// Finish implements the [user.SSO] interface.
+func (d *OIDC) Finish(state, code string) (user.Account, error) {
+	login, ok := d.take(state)
+	if !ok {
+		return user.Account{}, xerrors.ErrSSOStateInvalid
+	}
+
+	// providers redirect back without a code when users refuse to log
+	// in, or when they refuse the request
+	if code == "" {
+		return user.Account{}, xerrors.ErrSSODenied
+	}
+
+	p, err := d.discover()
+	if err != nil {
+		return user.Account{}, err
+	}
+
+	token, err := p.Exchange(d.config(), code, login.verifier)
+	if err != nil {
+		return user.Account{}, xerrors.ErrSSOUnavailable.New(err)
+	}
+
+	claims, err := p.Verify(d.config(), token, login.nonce, time.Now())
+	if err != nil {
+		return user.Account{}, xerrors.ErrSSOTokenInvalid.New(err)
+	}
+
+	return d.account(&claims), nil
+}
+
+// discover fetches the discovery document, once it succeeds.
+func (d *OIDC) discover() (*oidc.Provider, error) {
+	defer d.mu.Unlock()
+	d.mu.Lock()
+
+	if d.provider != nil {
+		return d.provider, nil
+	}
+
+	p, err := oidc.Discover(d.client, d.cfg.Issuer)
+	if err != nil {
+		return nil, xerrors.ErrSSOUnavailable.New(err)
+	}
+
+	d.provider = p
+	return p, nil
+}
+
+func (d *OIDC) hold(state string, login pending) error {
+	defer d.mu.Unlock()
+	d.mu.Lock()
+
+	if len(d.pending) >= maxPending {
+		now := time.Now()
+		for state, login := range d.pending {
+			if now.After(login.expires) {
+				delete(d.pending, state)
+			}
+		}
+	}
+
+	if len(d.pending) >= maxPending {
+		return xerrors.ErrSSOBusy
+	}
+
+	d.pending[state] = login
+	return nil
+}
+
+// take removes the login of the state, which is good for a single
+// callback.
+func (d *OIDC) take(state string) (pending, bool) {
+	defer d.mu.Unlock()
+	d.mu.Lock()
+
+	login, in := d.pending[state]
+	delete(d.pending, state)
+
+	return login, in && time.Now().Before(login.expires)
+}
+
+func (d *OIDC) config() oidc.Config {
+	return oidc.Config{
+		ClientID:     d.cfg.ClientID,
+		ClientSecret: d.cfg.ClientSecret,
+		RedirectURL:  d.cfg.RedirectURL,
+		Scopes:       []string{"email", "profile"},
+	}
+}
+
+// account maps the claims to an account. The email is left out if the
+// provider states it is not verified, as anyone could claim it.
+func (d *OIDC) account(claims *oidc.Claims) user.Account {
+	var acc user.Account
+
+	if d.cfg.SIAPEClaim != "" {
+		if siape, err := strconv.Atoi(claims.String(d.cfg.SIAPEClaim)); err == nil {
+			acc.SIAPE, _ = user.ProcessSiape(siape)
+		}
+	}
+
+	if claims.EmailVerified == nil || *claims.EmailVerified {
+		acc.Email = claims.Email
+	}
+
+	acc.Name = claims.Name
+	return acc
+}
@@ -0,0 +1,101 @@
+package directory_test
+
+import (
+	"net/url"
+	"testing"
+
+	. "github.com/alan-b-lima/almodon/internal/directory"
+	"github.com/alan-b-lima/almodon/pkg/errors"
+	"github.com/alan-b-lima/almodon/pkg/oidc/oidctest"
+)
+
+func newOIDC(t *testing.T) (*OIDC, *oidctest.Server) {
+	srv := oidctest.NewServer("almodon", "secret")
+	t.Cleanup(srv.Close)
+
+	cfg := DefaultOIDCConfig()
+	cfg.Issuer = srv.URL
+	cfg.ClientID = "almodon"
+	cfg.ClientSecret = "secret"
+	cfg.RedirectURL = "https://almodon.test/api/v1/users/auth/oidc/callback"
+
+	return NewOIDC(cfg), srv
+}
+
+// visit follows the redirect to the provider, returning the code it
+// sends back with.
+func visit(t *testing.T, srv *oidctest.Server, to string) string {
+	res, err := srv.Client().Get(to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	loc, err := url.Parse(res.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return loc.Query().Get("code")
+}
+
+func TestOIDC(t *testing.T) {
+	d, srv := newOIDC(t)
+	srv.SetClaims(map[string]any{
+		"sub":            "alan",
+		"name":           "Alan Barbosa Lima",
+		"email":          "alan@ufvjm.edu.br",
+		"email_verified": true,
+		"siape":          "0123456",
+	})
+
+	to, state, err := d.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acc, err := d.Finish(state, visit(t, srv, to))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if acc.SIAPE != 123456 || acc.Email != "alan@ufvjm.edu.br" || acc.Name != "Alan Barbosa Lima" {
+		t.Errorf("unexpected account %+v", acc)
+	}
+
+	if _, err := d.Finish(state, "code"); kind(err) != errors.Unauthorized {
+		t.Errorf("state should be good for a single login, got %v", err)
+	}
+}
+
+func TestOIDCUnverifiedEmail(t *testing.T) {
+	d, srv := newOIDC(t)
+	srv.SetClaims(map[string]any{
+		"sub":            "alan",
+		"email":          "alan@ufvjm.edu.br",
+		"email_verified": false,
+	})
+
+	to, state, err := d.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acc, err := d.Finish(state, visit(t, srv, to))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if acc.Email != "" {
+		t.Errorf("unverified email should be left out, got %q", acc.Email)
+	}
+}
+
+func TestOIDCUnavailable(t *testing.T) {
+	d, srv := newOIDC(t)
+	srv.Close()
+
+	if _, _, err := d.Begin(); kind(err) != errors.Unavailable {
+		t.Errorf("expected unavailable, got %v", err)
+	}
+}
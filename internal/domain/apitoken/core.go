@@ -0,0 +1,35 @@
+package apitoken
+
+import (
+	"time"
+
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+// ListByUser lists the tokens of the user, expired ones included, so
+// the user can tell why a script stopped working.
+func ListByUser(repo ListerByUser, user uuid.UUID) ([]Entity, error) {
+	return repo.ListByUser(user)
+}
+
+// Create creates a token for the user, the secret is only ever
+// available in the returned entity.
+func Create(repo Creater, user uuid.UUID, name string, scope Scope, expires time.Time) (CreateEntity, error) {
+	return repo.Create(user, name, scope, expires)
+}
+
+// Resolve returns the token of the given secret, recording it was
+// used, as long as it exists and has not expired.
+func Resolve(repo Resolver, secret string) (Entity, error) {
+	return repo.Resolve(secret)
+}
+
+// Revoke revokes a token, given it belongs to the user.
+func Revoke(repo Revoker, user, token uuid.UUID) error {
+	return repo.Revoke(user, token)
+}
+
+// DeleteByUser revokes every token of the user.
+func DeleteByUser(repo DeleterByUser, user uuid.UUID) error {
+	return repo.DeleteByUser(user)
+}
@@ -0,0 +1,125 @@
+package apitoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/errors"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+const (
+	_MaxMaxAge = 365 * 24 * time.Hour
+
+	_MaxNameLength = 64
+
+	// SecretPrefix starts every secret, so leaked tokens are easy to
+	// recognize by secret scanners.
+	SecretPrefix = "almodon_"
+)
+
+// Token is a long-lived credential a user hands to scripts, acting on
+// their behalf within its scope. Only the hash of the secret is kept,
+// the secret itself is handed to the user once.
+type Token struct {
+	uuid     uuid.UUID
+	user     uuid.UUID
+	name     string
+	scope    Scope
+	hash     [32]byte
+	created  time.Time
+	expires  time.Time
+	lastUsed time.Time
+}
+
+// New creates a new token for the user, it returns the token and its
+// secret.
+func New(user uuid.UUID, name string, scope Scope, expires time.Time) (Token, string, error) {
+	var t Token
+
+	err := errors.Join(
+		t.setUser(user),
+		t.SetName(name),
+		t.setScope(scope),
+		t.setExpires(expires),
+	)
+	if err != nil {
+		return Token{}, "", xerrors.ErrTokenCreation.New(err)
+	}
+
+	var secret [32]byte
+	rand.Read(secret[:])
+
+	str := SecretPrefix + base64.RawURLEncoding.EncodeToString(secret[:])
+	t.hash = Hash(str)
+
+	t.uuid = uuid.NewUUIDv7()
+	t.created = time.Now()
+	return t, str, nil
+}
+
+// Hash returns the hash under which the token of the given secret is
+// stored.
+func Hash(secret string) [32]byte {
+	return sha256.Sum256([]byte(secret))
+}
+
+func (t *Token) UUID() uuid.UUID     { return t.uuid }
+func (t *Token) User() uuid.UUID     { return t.user }
+func (t *Token) Name() string        { return t.name }
+func (t *Token) Scope() Scope        { return t.scope }
+func (t *Token) Hash() [32]byte      { return t.hash }
+func (t *Token) Created() time.Time  { return t.created }
+func (t *Token) Expires() time.Time  { return t.expires }
+func (t *Token) LastUsed() time.Time { return t.lastUsed }
+
+// IsExpired returns whether the token is expired at the given time.
+func (t *Token) IsExpired(now time.Time) bool {
+	return !now.Before(t.expires)
+}
+
+// Use records that the token was just used.
+func (t *Token) Use() {
+	t.lastUsed = time.Now()
+}
+
+func (t *Token) setUser(uuid uuid.UUID) error {
+	t.user = uuid
+	return nil
+}
+
+func (t *Token) SetName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return xerrors.ErrTokenNameEmpty
+	}
+	if utf8.RuneCountInString(name) > _MaxNameLength {
+		return xerrors.ErrTokenNameTooLong.New(_MaxNameLength)
+	}
+
+	t.name = name
+	return nil
+}
+
+func (t *Token) setScope(scope Scope) error {
+	if !scope.IsValid() {
+		return xerrors.ErrTokenScopeEmpty
+	}
+
+	t.scope = scope
+	return nil
+}
+
+func (t *Token) setExpires(expires time.Time) error {
+	if maxAge := time.Until(expires); maxAge <= 0 || maxAge > _MaxMaxAge {
+		return xerrors.ErrTokenLifetime.New(_MaxMaxAge)
+	}
+
+	t.expires = expires
+	return nil
+}
@@ -0,0 +1,54 @@
+package apitoken
+
+import (
+	"time"
+
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+type Repository interface {
+	ListerByUser
+	Creater
+	Resolver
+	Revoker
+	DeleterByUser
+}
+
+type (
+	ListerByUser interface {
+		ListByUser(user uuid.UUID) ([]Entity, error)
+	}
+
+	Creater interface {
+		Create(user uuid.UUID, name string, scope Scope, expires time.Time) (CreateEntity, error)
+	}
+
+	Resolver interface {
+		Resolve(secret string) (Entity, error)
+	}
+
+	Revoker interface {
+		Revoke(user, token uuid.UUID) error
+	}
+
+	DeleterByUser interface {
+		DeleteByUser(user uuid.UUID) error
+	}
+)
+
+type (
+	Entity struct {
+		UUID     uuid.UUID
+		User     uuid.UUID
+		Name     string
+		Scope    Scope
+		Created  time.Time
+		Expires  time.Time
+		LastUsed time.Time
+	}
+
+	CreateEntity struct {
+		Secret string
+		Entity
+	}
+)
@@ -0,0 +1,144 @@
+package apitokenrepo
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/domain/apitoken"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+// expired tokens are kept for a while, so their owners can still see
+// them listed
+const _Retention = 30 * 24 * time.Hour
+
+type Map struct {
+	repo      map[uuid.UUID]apitoken.Token
+	hashIndex map[[32]byte]uuid.UUID
+
+	mu sync.Mutex
+}
+
+func NewMap() apitoken.Repository {
+	repo := Map{
+		repo:      make(map[uuid.UUID]apitoken.Token),
+		hashIndex: make(map[[32]byte]uuid.UUID),
+	}
+
+	return &repo
+}
+
+func (m *Map) ListByUser(user uuid.UUID) ([]apitoken.Entity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	var res []apitoken.Entity
+	for _, t := range m.repo {
+		if t.User() == user {
+			res = append(res, transform(&t))
+		}
+	}
+
+	slices.SortFunc(res, func(a, b apitoken.Entity) int {
+		return a.Created.Compare(b.Created)
+	})
+
+	return res, nil
+}
+
+func (m *Map) Create(user uuid.UUID, name string, scope apitoken.Scope, expires time.Time) (apitoken.CreateEntity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	t, secret, err := apitoken.New(user, name, scope, expires)
+	if err != nil {
+		return apitoken.CreateEntity{}, err
+	}
+
+	m.purge()
+
+	m.repo[t.UUID()] = t
+	m.hashIndex[t.Hash()] = t.UUID()
+
+	res := apitoken.CreateEntity{
+		Secret: secret,
+		Entity: transform(&t),
+	}
+	return res, nil
+}
+
+func (m *Map) Resolve(secret string) (apitoken.Entity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	uuid, in := m.hashIndex[apitoken.Hash(secret)]
+	if !in {
+		return apitoken.Entity{}, xerrors.ErrTokenInvalid
+	}
+
+	t := m.repo[uuid]
+	if t.IsExpired(time.Now()) {
+		return apitoken.Entity{}, xerrors.ErrTokenInvalid
+	}
+
+	t.Use()
+	m.repo[uuid] = t
+
+	return transform(&t), nil
+}
+
+func (m *Map) Revoke(user, token uuid.UUID) error {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	t, in := m.repo[token]
+	if !in || t.User() != user {
+		return xerrors.ErrTokenNotFound
+	}
+
+	m.delete(&t)
+	return nil
+}
+
+func (m *Map) DeleteByUser(user uuid.UUID) error {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	for _, t := range m.repo {
+		if t.User() == user {
+			m.delete(&t)
+		}
+	}
+
+	return nil
+}
+
+func (m *Map) delete(t *apitoken.Token) {
+	delete(m.hashIndex, t.Hash())
+	delete(m.repo, t.UUID())
+}
+
+// purge removes tokens long expired, tokens are few and created
+// seldom, so a linear sweep on creation suffices.
+func (m *Map) purge() {
+	now := time.Now()
+	for _, t := range m.repo {
+		if t.IsExpired(now.Add(-_Retention)) {
+			m.delete(&t)
+		}
+	}
+}
+
+func transform(t *apitoken.Token) apitoken.Entity {
+	return apitoken.Entity{
+		UUID:     t.UUID(),
+		User:     t.User(),
+		Name:     t.Name(),
+		Scope:    t.Scope(),
+		Created:  t.Created(),
+		Expires:  t.Expires(),
+		LastUsed: t.LastUsed(),
+	}
+}
@@ -0,0 +1,63 @@
+package apitoken
+
+import "github.com/alan-b-lima/almodon/internal/xerrors"
+
+// Scope is the set of operations a token may be used for.
+type Scope uint8
+
+const (
+	// Read allows requests that do not change anything, such as GET.
+	Read Scope = 1 << iota
+
+	// Write allows requests that change something, and implies Read.
+	Write = 1<<iota | Read
+)
+
+var scopeStrings = [...]struct {
+	scope Scope
+	name  string
+}{
+	{Read, "read"},
+	{Write, "write"},
+}
+
+// ParseScope parses a list of scope names into a scope.
+func ParseScope(names []string) (Scope, error) {
+	var scope Scope
+
+Names:
+	for _, name := range names {
+		for _, s := range scopeStrings {
+			if s.name == name {
+				scope |= s.scope
+				continue Names
+			}
+		}
+
+		return 0, xerrors.ErrTokenScopeInvalid.New(name)
+	}
+
+	return scope, nil
+}
+
+// IsValid returns whether the scope allows anything at all.
+func (s Scope) IsValid() bool {
+	return s != 0 && s&^Write == 0
+}
+
+// Allows returns whether every operation of o is allowed by s.
+func (s Scope) Allows(o Scope) bool {
+	return s&o == o
+}
+
+// Strings returns the names of the scopes s is made of.
+func (s Scope) Strings() []string {
+	var names []string
+	for _, scope := range scopeStrings {
+		if s.Allows(scope.scope) {
+			names = append(names, scope.name)
+		}
+	}
+
+	return names
+}
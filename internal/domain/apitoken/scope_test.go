@@ -0,0 +1,47 @@
+package apitoken_test
+
+import (
+	"slices"
+	"testing"
+
+	. "github.com/alan-b-lima/almodon/internal/domain/apitoken"
+)
+
+func TestParseScope(t *testing.T) {
+	tests := []struct {
+		names  []string
+		scope  Scope
+		valid  bool
+		output []string
+	}{
+		{[]string{"read"}, Read, true, []string{"read"}},
+		{[]string{"write"}, Write, true, []string{"read", "write"}},
+		{[]string{"read", "write"}, Write, true, []string{"read", "write"}},
+		{[]string{}, 0, false, nil},
+		{[]string{"admin"}, 0, false, nil},
+	}
+
+	for _, test := range tests {
+		scope, err := ParseScope(test.names)
+		if valid := err == nil && scope.IsValid(); valid != test.valid {
+			t.Errorf("%v: expected valid to be %v, got %v (%v)", test.names, test.valid, valid, err)
+			continue
+		}
+
+		if scope != test.scope {
+			t.Errorf("%v: expected scope %d, got %d", test.names, test.scope, scope)
+		}
+		if !slices.Equal(scope.Strings(), test.output) {
+			t.Errorf("%v: expected %v, got %v", test.names, test.output, scope.Strings())
+		}
+	}
+}
+
+func TestScopeAllows(t *testing.T) {
+	if !Write.Allows(Read) {
+		t.Error("write should allow reading")
+	}
+	if Read.Allows(Write) {
+		t.Error("read should not allow writing")
+	}
+}
@@ -0,0 +1,269 @@
+package audit
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+// Hash is the SHA-256 hash of a record, which covers the hash of the
+// record before it, so changing, removing or reordering any record
+// breaks every link after it. The first record links to the zero
+// hash.
+type Hash [sha256.Size]byte
+
+func (h Hash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+func (h Hash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// Digest returns the hash of the entity, computed over every field but
+// the hash itself.
+func Digest(e *Entity) Hash {
+	h := sha256.New()
+
+	writeUint(h, e.Seq)
+	h.Write(e.Prev[:])
+	h.Write(e.UUID[:])
+	writeUint(h, uint64(e.Time.UnixNano()))
+	h.Write(e.Actor[:])
+	writeString(h, string(e.Action))
+	writeString(h, e.Target.Kind)
+	writeString(h, e.Target.ID)
+
+	writeUint(h, uint64(len(e.Changes)))
+	for _, c := range e.Changes {
+		writeString(h, c.Field)
+		writeString(h, string(c.Before))
+		writeString(h, string(c.After))
+	}
+
+	writeString(h, e.Addr)
+	writeString(h, e.Request)
+
+	var sum Hash
+	h.Sum(sum[:0])
+	return sum
+}
+
+// writeString writes the string prefixed by its length, so fields
+// cannot bleed into one another.
+func writeString(h hash.Hash, s string) {
+	writeUint(h, uint64(len(s)))
+	h.Write([]byte(s))
+}
+
+func writeUint(h hash.Hash, n uint64) {
+	h.Write(binary.BigEndian.AppendUint64(nil, n))
+}
+
+// Checkpoint is a signature over the hash of a record, and so over the
+// whole log up to it. Unlike the chain, which anyone able to write to
+// the log could recompute, a checkpoint cannot be forged without the
+// key, so records before it cannot be rewritten unnoticed, nor can the
+// log be cut short of it.
+type Checkpoint struct {
+	Seq       uint64
+	Hash      Hash
+	Time      time.Time
+	Signature []byte
+}
+
+// checkpointContext sets checkpoint signatures apart from anything
+// else signed with the same key.
+const checkpointContext = "almodon audit checkpoint\x00"
+
+// NewCheckpoint signs the hash of the entity with the key.
+func NewCheckpoint(key ed25519.PrivateKey, e *Entity) Checkpoint {
+	c := Checkpoint{Seq: e.Seq, Hash: e.Hash, Time: time.Now()}
+	c.Signature = ed25519.Sign(key, c.message())
+	return c
+}
+
+// Valid returns whether the checkpoint was signed by the owner of the
+// key.
+func (c *Checkpoint) Valid(key ed25519.PublicKey) bool {
+	return ed25519.Verify(key, c.message(), c.Signature)
+}
+
+func (c *Checkpoint) message() []byte {
+	msg := []byte(checkpointContext)
+	msg = binary.BigEndian.AppendUint64(msg, c.Seq)
+	msg = append(msg, c.Hash[:]...)
+	msg = binary.BigEndian.AppendUint64(msg, uint64(c.Time.UnixNano()))
+	return msg
+}
+
+// Chain appends records to the log, signing a checkpoint of it every
+// so many records.
+type Chain struct {
+	repo interface {
+		Appender
+		Checkpointer
+	}
+	key   ed25519.PrivateKey
+	every uint64
+}
+
+// NewChain creates a chain signing a checkpoint with the key after
+// every so many records, which must be positive.
+func NewChain(repo interface {
+	Appender
+	Checkpointer
+}, key ed25519.PrivateKey, every int) (*Chain, error) {
+	if every <= 0 {
+		return nil, xerrors.ErrAuditCheckpointInterval
+	}
+
+	return &Chain{repo: repo, key: key, every: uint64(every)}, nil
+}
+
+// Append appends the record, signing a checkpoint if it is due. The
+// record is kept even if the checkpoint fails, as the next one covers
+// it anyway.
+func (c *Chain) Append(r Record) (Entity, error) {
+	res, err := c.repo.Append(r)
+	if err != nil {
+		return Entity{}, err
+	}
+
+	if res.Seq%c.every != 0 {
+		return res, nil
+	}
+
+	if err := c.repo.AddCheckpoint(NewCheckpoint(c.key, &res)); err != nil {
+		return res, xerrors.ErrAuditCheckpoint.New(err)
+	}
+
+	return res, nil
+}
+
+// Reasons a link of the chain is broken.
+const (
+	// BrokenSeq is a record missing, duplicated or out of order.
+	BrokenSeq = "seq"
+
+	// BrokenLink is a record not linking to the one before it, as if
+	// records were removed or inserted.
+	BrokenLink = "link"
+
+	// BrokenHash is a record whose fields do not match its hash, as if
+	// it was edited.
+	BrokenHash = "hash"
+
+	// BrokenSignature is a checkpoint not signed by the key.
+	BrokenSignature = "signature"
+
+	// BrokenCheckpoint is a checkpoint not matching the record it was
+	// signed over, as if the log was rewritten or cut short.
+	BrokenCheckpoint = "checkpoint"
+)
+
+// Report is the result of verifying the log, Head being the hash of
+// the last record found intact, Last the checkpoint of the highest
+// sequence number, zero if there is none, and Broken nil if the log is
+// intact.
+type Report struct {
+	Records     int
+	Checkpoints int
+	Head        Hash
+	Last        Checkpoint
+	Broken      *Break
+}
+
+// Break is the first broken link of the chain, at the sequence number,
+// along with the UUID of the record found there, nil if there is none.
+type Break struct {
+	Seq    uint64
+	UUID   uuid.UUID
+	Reason string
+}
+
+// Verify walks the whole log, recomputing the hash of every record and
+// checking its link to the previous one, then checks the checkpoints
+// against the key and the records they were signed over. The first
+// broken link, the one of the lowest sequence number, is reported.
+//
+// Records appended after the last checkpoint are only protected by the
+// chain, so a rewrite of them all, up to the last record, goes
+// unnoticed until the next checkpoint.
+func Verify(repo interface {
+	Walker
+	Checkpointer
+}, key ed25519.PublicKey) (Report, error) {
+	records, err := repo.All()
+	if err != nil {
+		return Report{}, err
+	}
+
+	checkpoints, err := repo.Checkpoints()
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{Records: len(records), Checkpoints: len(checkpoints)}
+	breaks := func(b Break) {
+		if report.Broken == nil || b.Seq < report.Broken.Seq {
+			report.Broken = &b
+		}
+	}
+
+	var prev Hash
+	for i := range records {
+		e := &records[i]
+
+		switch seq := uint64(i) + 1; {
+		case e.Seq != seq:
+			breaks(Break{Seq: seq, UUID: e.UUID, Reason: BrokenSeq})
+		case e.Prev != prev:
+			breaks(Break{Seq: seq, UUID: e.UUID, Reason: BrokenLink})
+		case Digest(e) != e.Hash:
+			breaks(Break{Seq: seq, UUID: e.UUID, Reason: BrokenHash})
+		}
+
+		if report.Broken != nil {
+			break
+		}
+
+		prev = e.Hash
+	}
+	report.Head = prev
+
+	for _, c := range checkpoints {
+		if c.Seq > report.Last.Seq {
+			report.Last = c
+		}
+
+		var at *Entity
+		if 0 < c.Seq && c.Seq <= uint64(len(records)) {
+			at = &records[c.Seq-1]
+		}
+
+		b := Break{Seq: c.Seq}
+		if at != nil {
+			b.UUID = at.UUID
+		}
+
+		switch {
+		case !c.Valid(key):
+			b.Reason = BrokenSignature
+		case at == nil || at.Hash != c.Hash:
+			b.Reason = BrokenCheckpoint
+		default:
+			continue
+		}
+
+		breaks(b)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,154 @@
+package audit_test
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/alan-b-lima/almodon/internal/auth"
+	. "github.com/alan-b-lima/almodon/internal/domain/audit"
+	auditrepo "github.com/alan-b-lima/almodon/internal/domain/audit/repository"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+// snapshot is a copy of a log, to be tampered with.
+type snapshot struct {
+	records     []Entity
+	checkpoints []Checkpoint
+}
+
+func (s *snapshot) All() ([]Entity, error)             { return s.records, nil }
+func (s *snapshot) Checkpoints() ([]Checkpoint, error) { return s.checkpoints, nil }
+func (s *snapshot) AddCheckpoint(c Checkpoint) error {
+	s.checkpoints = append(s.checkpoints, c)
+	return nil
+}
+
+// chain appends n records, signing a checkpoint every 3 of them, and
+// returns a copy of the log.
+func chain(t *testing.T, key ed25519.PrivateKey, n int) *snapshot {
+	t.Helper()
+
+	repo := auditrepo.NewMap()
+	c, err := NewChain(repo, key, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	act := auth.NewLogged(uuid.NewUUIDv7(), auth.Chief)
+	for i := range n {
+		changes := []Change{{Field: "n", Before: json.RawMessage("null"), After: json.RawMessage{'0' + byte(i)}}}
+
+		r, err := New(act, "user.create", Target{Kind: "user", ID: uuid.NewUUIDv7().String()}, changes)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := c.Append(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	records, _ := repo.All()
+	checkpoints, _ := repo.Checkpoints()
+	return &snapshot{records: records, checkpoints: checkpoints}
+}
+
+func TestChainLinks(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(nil)
+	log := chain(t, key, 7)
+
+	var prev Hash
+	for i, e := range log.records {
+		if e.Seq != uint64(i)+1 || e.Prev != prev || e.Hash != Digest(&e) {
+			t.Fatalf("record %d is not linked: seq %d, prev %v, hash %v", i, e.Seq, e.Prev, e.Hash)
+		}
+		prev = e.Hash
+	}
+
+	if len(log.checkpoints) != 2 {
+		t.Fatalf("got %d checkpoints, expected 2", len(log.checkpoints))
+	}
+
+	for _, c := range log.checkpoints {
+		if c.Seq%3 != 0 || !c.Valid(key.Public().(ed25519.PublicKey)) {
+			t.Errorf("checkpoint at %d is not valid", c.Seq)
+		}
+	}
+
+	if _, err := NewChain(auditrepo.NewMap(), key, 0); err == nil {
+		t.Error("NewChain() with no interval should fail")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(nil)
+	pub := key.Public().(ed25519.PublicKey)
+
+	_, other, _ := ed25519.GenerateKey(nil)
+
+	tests := map[string]struct {
+		tamper func(s *snapshot)
+		broken *Break
+	}{
+		"intact": {
+			tamper: func(s *snapshot) {},
+		},
+		"edited": {
+			tamper: func(s *snapshot) { s.records[4].Addr = "10.0.0.1" },
+			broken: &Break{Seq: 5, Reason: BrokenHash},
+		},
+		"edited and rehashed": {
+			tamper: func(s *snapshot) {
+				s.records[4].Addr = "10.0.0.1"
+				s.records[4].Hash = Digest(&s.records[4])
+			},
+			broken: &Break{Seq: 6, Reason: BrokenLink},
+		},
+		"rewritten to the end": {
+			tamper: func(s *snapshot) {
+				prev := s.records[3].Hash
+				for i := 4; i < len(s.records); i++ {
+					s.records[i].Prev = prev
+					s.records[i].Actor = uuid.UUID{}
+					s.records[i].Hash = Digest(&s.records[i])
+					prev = s.records[i].Hash
+				}
+			},
+			broken: &Break{Seq: 6, Reason: BrokenCheckpoint},
+		},
+		"removed": {
+			tamper: func(s *snapshot) { s.records = slices.Delete(s.records, 1, 2) },
+			broken: &Break{Seq: 2, Reason: BrokenSeq},
+		},
+		"cut short": {
+			tamper: func(s *snapshot) { s.records = s.records[:4] },
+			broken: &Break{Seq: 6, Reason: BrokenCheckpoint},
+		},
+		"forged checkpoint": {
+			tamper: func(s *snapshot) { s.checkpoints[1] = NewCheckpoint(other, &s.records[5]) },
+			broken: &Break{Seq: 6, Reason: BrokenSignature},
+		},
+	}
+
+	for name, test := range tests {
+		log := chain(t, key, 7)
+		test.tamper(log)
+
+		report, err := Verify(log, pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := report.Broken
+		switch {
+		case test.broken == nil && got != nil:
+			t.Errorf("%s: broken at %d (%s), expected intact", name, got.Seq, got.Reason)
+		case test.broken != nil && got == nil:
+			t.Errorf("%s: intact, expected broken at %d (%s)", name, test.broken.Seq, test.broken.Reason)
+		case test.broken != nil && (got.Seq != test.broken.Seq || got.Reason != test.broken.Reason):
+			t.Errorf("%s: broken at %d (%s), expected at %d (%s)", name, got.Seq, got.Reason, test.broken.Seq, test.broken.Reason)
+		}
+	}
+}
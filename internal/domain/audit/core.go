@@ -0,0 +1,25 @@
+package audit
+
+import (
+	"github.com/alan-b-lima/almodon/internal/auth"
+)
+
+// Log records the action the actor took on the target, along with the
+// fields it changed, as compared by [Diff].
+func Log(repo Appender, act auth.Actor, action auth.Action, target Target, before, after any) (Entity, error) {
+	changes, err := Diff(before, after)
+	if err != nil {
+		return Entity{}, err
+	}
+
+	r, err := New(act, action, target, changes)
+	if err != nil {
+		return Entity{}, err
+	}
+
+	return repo.Append(r)
+}
+
+func List(repo Lister, filter Filter, offset, limit int) (Entities, error) {
+	return repo.List(filter, offset, limit)
+}
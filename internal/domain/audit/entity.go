@@ -0,0 +1,162 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+// Target is what a record is about, the kind of the entity, as "user"
+// or "session", and its ID.
+type Target struct {
+	Kind string
+	ID   string
+}
+
+// Change is a field of the target that changed, with its values
+// before and after, encoded in JSON, null where the field did not
+// exist, as for targets created or removed.
+type Change struct {
+	Field  string
+	Before json.RawMessage
+	After  json.RawMessage
+}
+
+// Record tells who did what to which entity, from where and when.
+// Records are only ever appended, never changed, each linked to the
+// one before it by its hash, see [Hash].
+type Record struct {
+	seq     uint64
+	prev    Hash
+	hash    Hash
+	uuid    uuid.UUID
+	time    time.Time
+	actor   uuid.UUID
+	action  auth.Action
+	target  Target
+	changes []Change
+	addr    string
+	request string
+}
+
+// New creates the record of the action the actor took on the target,
+// from where the actor acts. Unlogged actors, as users logging in, are
+// recorded with the nil UUID.
+func New(act auth.Actor, action auth.Action, target Target, changes []Change) (Record, error) {
+	if action == "" || target.Kind == "" {
+		return Record{}, xerrors.ErrAuditRecordCreation
+	}
+
+	origin := act.Origin()
+	return Record{
+		uuid:    uuid.NewUUIDv7(),
+		time:    time.Now(),
+		actor:   act.User(),
+		action:  action,
+		target:  target,
+		changes: changes,
+		addr:    origin.Addr,
+		request: origin.Request,
+	}, nil
+}
+
+// Link returns the record as the one of the sequence number, following
+// the record of the given hash, and hashes it. Sequence numbers start
+// at 1, the first record following the zero hash.
+func (r Record) Link(seq uint64, prev Hash) Record {
+	r.seq, r.prev = seq, prev
+	r.hash = Digest(&Entity{
+		Seq:     r.seq,
+		Prev:    r.prev,
+		UUID:    r.uuid,
+		Time:    r.time,
+		Actor:   r.actor,
+		Action:  r.action,
+		Target:  r.target,
+		Changes: r.changes,
+		Addr:    r.addr,
+		Request: r.request,
+	})
+
+	return r
+}
+
+func (r *Record) Seq() uint64         { return r.seq }
+func (r *Record) Prev() Hash          { return r.prev }
+func (r *Record) Hash() Hash          { return r.hash }
+func (r *Record) UUID() uuid.UUID     { return r.uuid }
+func (r *Record) Time() time.Time     { return r.time }
+func (r *Record) Actor() uuid.UUID    { return r.actor }
+func (r *Record) Action() auth.Action { return r.action }
+func (r *Record) Target() Target      { return r.target }
+func (r *Record) Changes() []Change   { return slices.Clone(r.changes) }
+func (r *Record) Addr() string        { return r.addr }
+func (r *Record) Request() string     { return r.request }
+
+// Diff compares the JSON encodings of the target before and after the
+// action, field by field, in the order of their names. Either may be
+// nil, as for targets created or removed. Only what is encoded is
+// compared, so secrets must be left out of what is given.
+func Diff(before, after any) ([]Change, error) {
+	b, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	names := slices.Sorted(maps.Keys(b))
+	for name := range a {
+		if _, in := b[name]; !in {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var changes []Change
+	for _, name := range names {
+		bv, av := b[name], a[name]
+		if bytes.Equal(bv, av) {
+			continue
+		}
+
+		changes = append(changes, Change{Field: name, Before: orNull(bv), After: orNull(av)})
+	}
+
+	return changes, nil
+}
+
+func fields(v any) (map[string]json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil, xerrors.ErrAuditDiff.New(err)
+	}
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(buf, &m); err != nil {
+		return nil, xerrors.ErrAuditDiff.New(err)
+	}
+
+	return m, nil
+}
+
+func orNull(v json.RawMessage) json.RawMessage {
+	if v == nil {
+		return json.RawMessage("null")
+	}
+
+	return v
+}
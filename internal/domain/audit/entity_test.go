@@ -0,0 +1,118 @@
+package audit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/auth"
+	. "github.com/alan-b-lima/almodon/internal/domain/audit"
+	"github.com/alan-b-lima/almodon/pkg/opt"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+type profile struct {
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+	Role  string `json:"role"`
+}
+
+func TestDiff(t *testing.T) {
+	before := profile{Name: "Lucas", Email: "l@ufvjm.edu.br", Role: "user"}
+	after := profile{Name: "Lucas Rocha", Role: "user"}
+
+	changes, err := Diff(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct{ field, before, after string }{
+		{"email", `"l@ufvjm.edu.br"`, "null"},
+		{"name", `"Lucas"`, `"Lucas Rocha"`},
+	}
+
+	if len(changes) != len(want) {
+		t.Fatalf("Diff() = %v, expected %d changes", changes, len(want))
+	}
+
+	for i, w := range want {
+		c := changes[i]
+		if c.Field != w.field || string(c.Before) != w.before || string(c.After) != w.after {
+			t.Errorf("change %d = %s: %s -> %s, expected %s: %s -> %s", i, c.Field, c.Before, c.After, w.field, w.before, w.after)
+		}
+	}
+}
+
+func TestDiffCreated(t *testing.T) {
+	changes, err := Diff(nil, profile{Name: "Lucas", Role: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(changes) != 2 {
+		t.Fatalf("Diff() = %v, expected 2 changes", changes)
+	}
+
+	for _, c := range changes {
+		if string(c.Before) != "null" {
+			t.Errorf("field %s was %s before creation, expected null", c.Field, c.Before)
+		}
+	}
+
+	if changes, _ := Diff(nil, nil); changes != nil {
+		t.Errorf("Diff(nil, nil) = %v, expected no changes", changes)
+	}
+}
+
+func TestNew(t *testing.T) {
+	act := auth.NewLogged(uuid.NewUUIDv7(), auth.Chief)
+
+	if _, err := New(act, "", Target{Kind: "user"}, nil); err == nil {
+		t.Error("New() without an action should fail")
+	}
+
+	if _, err := New(act, "user.create", Target{}, nil); err == nil {
+		t.Error("New() without a target should fail")
+	}
+
+	origin := auth.Origin{Addr: "10.0.0.1", Request: "abc"}
+	r, err := New(act.From(origin), "user.create", Target{Kind: "user", ID: "1"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Actor() != act.User() || r.Addr() != origin.Addr || r.Request() != origin.Request {
+		t.Errorf("record of %v from %v, expected of %v from %v", r.Actor(), r.Addr(), act.User(), origin)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	act := auth.NewLogged(uuid.NewUUIDv7(), auth.Chief)
+	r, err := New(act, "user.create", Target{Kind: "user", ID: "1"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := r.Time()
+	tests := []struct {
+		filter Filter
+		match  bool
+	}{
+		{Filter{}, true},
+		{Filter{Actor: opt.Some(act.User())}, true},
+		{Filter{Actor: opt.Some(uuid.NewUUIDv7())}, false},
+		{Filter{Kind: "user"}, true},
+		{Filter{Kind: "session"}, false},
+		{Filter{Kind: "user", ID: "1"}, true},
+		{Filter{Kind: "user", ID: "2"}, false},
+		{Filter{From: now}, true},
+		{Filter{From: now.Add(time.Second)}, false},
+		{Filter{To: now}, false},
+		{Filter{From: now.Add(-time.Hour), To: now.Add(time.Hour)}, true},
+	}
+
+	for i, test := range tests {
+		if got := test.filter.Match(&r); got != test.match {
+			t.Errorf("test %d: Match() = %v, expected %v", i, got, test.match)
+		}
+	}
+}
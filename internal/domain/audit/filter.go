@@ -0,0 +1,44 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/alan-b-lima/almodon/pkg/opt"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+// Filter selects records by actor, by target, of a kind and, if given,
+// of an ID, and by time, within [From, To), either bound left out if
+// zero.
+type Filter struct {
+	Actor opt.Opt[uuid.UUID]
+	Kind  string
+	ID    string
+	From  time.Time
+	To    time.Time
+}
+
+// Match returns whether the record passes the filter.
+func (f *Filter) Match(r *Record) bool {
+	if actor, ok := f.Actor.Unwrap(); ok && r.actor != actor {
+		return false
+	}
+
+	if f.Kind != "" && r.target.Kind != f.Kind {
+		return false
+	}
+
+	if f.ID != "" && r.target.ID != f.ID {
+		return false
+	}
+
+	if !f.From.IsZero() && r.time.Before(f.From) {
+		return false
+	}
+
+	if !f.To.IsZero() && !r.time.Before(f.To) {
+		return false
+	}
+
+	return true
+}
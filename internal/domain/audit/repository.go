@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+type Repository interface {
+	Appender
+	Lister
+	Walker
+	Checkpointer
+}
+
+type (
+	// Appender keeps records, which are never changed nor removed
+	// afterwards. Records are linked, see [Record.Link], to the last
+	// one kept as they are appended.
+	Appender interface {
+		Append(r Record) (Entity, error)
+	}
+
+	// Lister lists the records passing the filter, the latest first.
+	Lister interface {
+		List(filter Filter, offset, limit int) (Entities, error)
+	}
+
+	// Walker returns every record, in the order they were appended.
+	Walker interface {
+		All() ([]Entity, error)
+	}
+
+	// Checkpointer keeps the checkpoints signed over the log.
+	Checkpointer interface {
+		AddCheckpoint(c Checkpoint) error
+		Checkpoints() ([]Checkpoint, error)
+	}
+)
+
+type (
+	Entities struct {
+		Offset       int
+		Length       int
+		Records      []Entity
+		TotalRecords int
+	}
+
+	Entity struct {
+		Seq     uint64
+		Prev    Hash
+		Hash    Hash
+		UUID    uuid.UUID
+		Time    time.Time
+		Actor   uuid.UUID
+		Action  auth.Action
+		Target  Target
+		Changes []Change
+		Addr    string
+		Request string
+	}
+)
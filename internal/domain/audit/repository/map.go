@@ -0,0 +1,117 @@
+package auditrepo
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+
+	"github.com/alan-b-lima/almodon/internal/domain/audit"
+)
+
+// Map is an in-memory audit log, kept in the order records were
+// appended.
+type Map struct {
+	repo        []audit.Record
+	checkpoints []audit.Checkpoint
+	mu          sync.RWMutex
+}
+
+func NewMap() audit.Repository {
+	return &Map{}
+}
+
+func (m *Map) Append(r audit.Record) (audit.Entity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	var prev audit.Hash
+	if n := len(m.repo); n > 0 {
+		prev = m.repo[n-1].Hash()
+	}
+
+	r = r.Link(uint64(len(m.repo))+1, prev)
+	m.repo = append(m.repo, r)
+
+	var res audit.Entity
+	transform(&res, &r)
+	return res, nil
+}
+
+func (m *Map) List(filter audit.Filter, offset, limit int) (audit.Entities, error) {
+	defer m.mu.RUnlock()
+	m.mu.RLock()
+
+	var matches []*audit.Record
+	for i := len(m.repo) - 1; i >= 0; i-- {
+		if filter.Match(&m.repo[i]) {
+			matches = append(matches, &m.repo[i])
+		}
+	}
+
+	lo := clamp(0, offset, len(matches))
+	hi := clamp(0, offset+limit, len(matches))
+
+	if lo >= hi {
+		return audit.Entities{
+			Records:      []audit.Entity{},
+			TotalRecords: len(matches),
+		}, nil
+	}
+
+	res := make([]audit.Entity, hi-lo)
+	for i, r := range matches[lo:hi] {
+		transform(&res[i], r)
+	}
+
+	return audit.Entities{
+		Offset:       lo,
+		Length:       len(res),
+		Records:      res,
+		TotalRecords: len(matches),
+	}, nil
+}
+
+func (m *Map) All() ([]audit.Entity, error) {
+	defer m.mu.RUnlock()
+	m.mu.RLock()
+
+	res := make([]audit.Entity, len(m.repo))
+	for i := range m.repo {
+		transform(&res[i], &m.repo[i])
+	}
+
+	return res, nil
+}
+
+func (m *Map) AddCheckpoint(c audit.Checkpoint) error {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	m.checkpoints = append(m.checkpoints, c)
+	return nil
+}
+
+func (m *Map) Checkpoints() ([]audit.Checkpoint, error) {
+	defer m.mu.RUnlock()
+	m.mu.RLock()
+
+	return slices.Clone(m.checkpoints), nil
+}
+
+func transform(e *audit.Entity, r *audit.Record) {
+	e.Seq = r.Seq()
+	e.Prev = r.Prev()
+	e.Hash = r.Hash()
+	e.UUID = r.UUID()
+	e.Time = r.Time()
+	e.Actor = r.Actor()
+	e.Action = r.Action()
+	e.Target = r.Target()
+	e.Changes = r.Changes()
+	e.Addr = r.Addr()
+	e.Request = r.Request()
+}
+
+func clamp[T cmp.Ordered](mn, val, mx T) T {
+	return min(max(mn, val), mx)
+}
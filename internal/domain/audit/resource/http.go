@@ -0,0 +1,74 @@
+package audits
+
+import (
+	"net/http"
+
+	"github.com/alan-b-lima/almodon/internal/domain/audit"
+	"github.com/alan-b-lima/almodon/internal/domain/user"
+	"github.com/alan-b-lima/almodon/internal/support/resource"
+)
+
+type Resource struct {
+	http.ServeMux
+	Records audit.Service
+	Users   user.Service
+}
+
+func New(records audit.Service, users user.Service) *Resource {
+	rc := Resource{Records: records, Users: users}
+
+	routes := map[string]http.HandlerFunc{
+		"GET /audit/":       rc.List,
+		"GET /audit/verify": rc.Verify,
+	}
+
+	for route, handler := range routes {
+		rc.Handle(route, handler)
+	}
+
+	return &rc
+}
+
+func (rc *Resource) List(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.Session(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	req := audit.ListRequest{Offset: 0, Limit: 10}
+	if err := resource.QueryParams(r.URL.Query(), &req); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	res, err := rc.Records.List(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusOK, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
+
+func (rc *Resource) Verify(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.Session(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	res, err := rc.Records.Verify(act, audit.VerifyRequest{})
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusOK, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
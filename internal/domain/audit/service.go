@@ -0,0 +1,10 @@
+package audit
+
+import (
+	"github.com/alan-b-lima/almodon/internal/auth"
+)
+
+type Service interface {
+	List(act auth.Actor, req ListRequest) (ListResponse, error)
+	Verify(act auth.Actor, req VerifyRequest) (VerifyResponse, error)
+}
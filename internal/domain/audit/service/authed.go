@@ -0,0 +1,46 @@
+package auditserve
+
+import (
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/domain/audit"
+	"github.com/alan-b-lima/almodon/internal/support/service"
+)
+
+type AuthService struct {
+	service audit.Service
+	policy  *auth.Policy
+}
+
+func New(service audit.Service, policy *auth.Policy) audit.Service {
+	return &AuthService{service: service, policy: policy}
+}
+
+const (
+	actList   auth.Action = "audit.list"
+	actVerify auth.Action = "audit.verify"
+)
+
+// Actions returns the actions checked by the service, along with their
+// default permissions.
+func Actions() map[auth.Action]auth.Permission {
+	return map[auth.Action]auth.Permission{
+		actList:   auth.Permit(auth.Chief),
+		actVerify: auth.Permit(auth.Chief),
+	}
+}
+
+func (s *AuthService) List(act auth.Actor, req audit.ListRequest) (audit.ListResponse, error) {
+	if err := service.Allow(s.policy, actList, act); err != nil {
+		return audit.ListResponse{}, err
+	}
+
+	return s.service.List(act, req)
+}
+
+func (s *AuthService) Verify(act auth.Actor, req audit.VerifyRequest) (audit.VerifyResponse, error) {
+	if err := service.Allow(s.policy, actVerify, act); err != nil {
+		return audit.VerifyResponse{}, err
+	}
+
+	return s.service.Verify(act, req)
+}
@@ -0,0 +1,135 @@
+package auditserve
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/domain/audit"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/opt"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+type Service struct {
+	Repo audit.Repository
+	Key  ed25519.PublicKey
+}
+
+func NewService(records audit.Repository, key ed25519.PublicKey) audit.Service {
+	return &Service{Repo: records, Key: key}
+}
+
+func (s *Service) List(act auth.Actor, req audit.ListRequest) (audit.ListResponse, error) {
+	filter := audit.Filter{Kind: req.Entity, ID: req.ID}
+
+	if req.Actor != "" {
+		actor, err := uuid.FromString(req.Actor)
+		if err != nil {
+			return audit.ListResponse{}, xerrors.ErrAuditActorInvalid
+		}
+
+		filter.Actor = opt.Some(actor)
+	}
+
+	var err error
+	if filter.From, err = parseTime(req.From, false); err != nil {
+		return audit.ListResponse{}, err
+	}
+	if filter.To, err = parseTime(req.To, true); err != nil {
+		return audit.ListResponse{}, err
+	}
+
+	res, err := audit.List(s.Repo, filter, req.Offset, req.Limit)
+	if err != nil {
+		return audit.ListResponse{}, err
+	}
+
+	lres := audit.ListResponse{
+		Offset:       res.Offset,
+		Length:       res.Length,
+		Records:      make([]audit.Response, res.Length),
+		TotalRecords: res.TotalRecords,
+	}
+	for i := range res.Records {
+		lres.Records[i] = transform(&res.Records[i])
+	}
+
+	return lres, nil
+}
+
+func (s *Service) Verify(act auth.Actor, req audit.VerifyRequest) (audit.VerifyResponse, error) {
+	report, err := audit.Verify(s.Repo, s.Key)
+	if err != nil {
+		return audit.VerifyResponse{}, err
+	}
+
+	res := audit.VerifyResponse{
+		Intact:      report.Broken == nil,
+		Records:     report.Records,
+		Checkpoints: report.Checkpoints,
+		Head:        report.Head.String(),
+		PublicKey:   hex.EncodeToString(s.Key),
+	}
+
+	if c := report.Last; c.Seq != 0 {
+		res.LastCheckpoint = &audit.CheckpointResponse{
+			Seq:       c.Seq,
+			Hash:      c.Hash.String(),
+			Time:      c.Time,
+			Signature: hex.EncodeToString(c.Signature),
+		}
+	}
+
+	if b := report.Broken; b != nil {
+		res.Broken = &audit.BreakResponse{Seq: b.Seq, UUID: b.UUID, Reason: b.Reason}
+	}
+
+	return res, nil
+}
+
+// parseTime parses a bound of the time filter, the empty string being
+// no bound. Dates are taken in the local time, and a date given as
+// the upper bound includes the whole day.
+func parseTime(s string, upper bool) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation(time.DateOnly, s, time.Local)
+	if err != nil {
+		return time.Time{}, xerrors.ErrAuditTimeInvalid
+	}
+
+	if upper {
+		t = t.AddDate(0, 0, 1)
+	}
+
+	return t, nil
+}
+
+func transform(e *audit.Entity) audit.Response {
+	res := audit.Response{
+		Seq:     e.Seq,
+		Prev:    e.Prev.String(),
+		Hash:    e.Hash.String(),
+		UUID:    e.UUID,
+		Time:    e.Time,
+		Actor:   e.Actor,
+		Action:  string(e.Action),
+		Target:  audit.TargetResponse{Kind: e.Target.Kind, ID: e.Target.ID},
+		Address: e.Addr,
+		Request: e.Request,
+	}
+
+	for _, c := range e.Changes {
+		res.Changes = append(res.Changes, audit.ChangeResponse{Field: c.Field, Before: c.Before, After: c.After})
+	}
+
+	return res
+}
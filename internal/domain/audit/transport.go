@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+type (
+	// ListRequest filters by the UUID of the actor, by the kind of the
+	// entity and its ID, and by time. From and To are either dates, To
+	// included, or times in RFC 3339, To excluded.
+	ListRequest struct {
+		Actor  string `query:"actor"`
+		Entity string `query:"entity"`
+		ID     string `query:"id"`
+		From   string `query:"from"`
+		To     string `query:"to"`
+		Offset int    `query:"offset"`
+		Limit  int    `query:"limit"`
+	}
+
+	VerifyRequest struct{}
+)
+
+type (
+	ListResponse struct {
+		Offset       int        `json:"offset"`
+		Length       int        `json:"length"`
+		Records      []Response `json:"records"`
+		TotalRecords int        `json:"total_records"`
+	}
+
+	Response struct {
+		Seq     uint64           `json:"seq"`
+		Prev    string           `json:"prev"`
+		Hash    string           `json:"hash"`
+		UUID    uuid.UUID        `json:"uuid"`
+		Time    time.Time        `json:"time"`
+		Actor   uuid.UUID        `json:"actor,omitzero"`
+		Action  string           `json:"action"`
+		Target  TargetResponse   `json:"target"`
+		Changes []ChangeResponse `json:"changes,omitempty"`
+		Address string           `json:"address,omitempty"`
+		Request string           `json:"request,omitempty"`
+	}
+
+	TargetResponse struct {
+		Kind string `json:"kind"`
+		ID   string `json:"id,omitempty"`
+	}
+
+	// VerifyResponse reports whether the log is intact and, if not, its
+	// first broken link. The public key, in hexadecimal, is the one
+	// checkpoints are verified against, so they can be checked apart
+	// from the server.
+	VerifyResponse struct {
+		Intact         bool                `json:"intact"`
+		Records        int                 `json:"records"`
+		Checkpoints    int                 `json:"checkpoints"`
+		Head           string              `json:"head"`
+		LastCheckpoint *CheckpointResponse `json:"last_checkpoint,omitempty"`
+		Broken         *BreakResponse      `json:"broken,omitempty"`
+		PublicKey      string              `json:"public_key"`
+	}
+
+	CheckpointResponse struct {
+		Seq       uint64    `json:"seq"`
+		Hash      string    `json:"hash"`
+		Time      time.Time `json:"time"`
+		Signature string    `json:"signature"`
+	}
+
+	BreakResponse struct {
+		Seq    uint64    `json:"seq"`
+		UUID   uuid.UUID `json:"uuid,omitzero"`
+		Reason string    `json:"reason"`
+	}
+
+	ChangeResponse struct {
+		Field  string          `json:"field"`
+		Before json.RawMessage `json:"before"`
+		After  json.RawMessage `json:"after"`
+	}
+)
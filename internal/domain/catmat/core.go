@@ -0,0 +1,176 @@
+package catmat
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/errors"
+)
+
+func List(materials Lister, class, offset, limit int) (Entities, error) {
+	return materials.List(class, offset, limit)
+}
+
+func Get(materials Getter, code int) (Entity, error) {
+	return materials.Get(code)
+}
+
+// Validate checks whether the code is present in the local CATMAT
+// reference table, allowing codes to be validated offline.
+func Validate(materials Getter, code int) error {
+	if _, err := ProcessCode(code); err != nil {
+		return err
+	}
+
+	if _, err := materials.Get(code); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Import reads a CATMAT reference table from a CSV file and upserts
+// its entries into the repository. The file must have a header, in
+// which the columns code, description, class and, optionally,
+// class_description are identified (their portuguese names are also
+// accepted). Both comma and semicolon are accepted as separators.
+//
+// The import is all-or-nothing: if any row is invalid, none is
+// imported and every row error is reported.
+func Import(materials Importer, r io.Reader) (ImportEntity, error) {
+	br := bufio.NewReader(r)
+
+	cr := csv.NewReader(br)
+	cr.Comma = sniffComma(br)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return ImportEntity{}, xerrors.ErrCatmatEmpty
+	}
+	if err != nil {
+		return ImportEntity{}, xerrors.ErrCatmatMalformed.New(err)
+	}
+
+	cols, err := columns(header)
+	if err != nil {
+		return ImportEntity{}, err
+	}
+
+	var (
+		records []Material
+		errs    []error
+	)
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return ImportEntity{}, xerrors.ErrCatmatMalformed.New(err)
+		}
+
+		line, _ := cr.FieldPos(0)
+
+		m, err := parseRecord(record, cols)
+		if err != nil {
+			errs = append(errs, xerrors.ErrCatmatRow.New(line, err))
+			continue
+		}
+
+		records = append(records, m)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return ImportEntity{}, xerrors.ErrCatmatImport.New(err)
+	}
+
+	return materials.Import(records)
+}
+
+const (
+	colCode = iota
+	colDescription
+	colClass
+	colClassDescription
+
+	colCount
+)
+
+var headerAliases = map[string]int{
+	"code":              colCode,
+	"codigo":            colCode,
+	"código":            colCode,
+	"codigo_item":       colCode,
+	"description":       colDescription,
+	"descricao":         colDescription,
+	"descrição":         colDescription,
+	"descricao_item":    colDescription,
+	"class":             colClass,
+	"classe":            colClass,
+	"codigo_classe":     colClass,
+	"class_description": colClassDescription,
+	"descricao_classe":  colClassDescription,
+	"nome_classe":       colClassDescription,
+}
+
+func columns(header []string) ([colCount]int, error) {
+	var cols [colCount]int
+	for i := range cols {
+		cols[i] = -1
+	}
+
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		name = strings.TrimPrefix(name, "\ufeff")
+
+		if col, in := headerAliases[name]; in && cols[col] < 0 {
+			cols[col] = i
+		}
+	}
+
+	if cols[colCode] < 0 || cols[colDescription] < 0 || cols[colClass] < 0 {
+		return cols, xerrors.ErrCatmatMissingColumns
+	}
+
+	return cols, nil
+}
+
+func parseRecord(record []string, cols [colCount]int) (Material, error) {
+	field := func(col int) string {
+		if cols[col] < 0 || cols[col] >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[cols[col]])
+	}
+
+	code, err := strconv.Atoi(field(colCode))
+	if err != nil {
+		return Material{}, xerrors.ErrMaterialCodeInvalid
+	}
+
+	class, err := strconv.Atoi(field(colClass))
+	if err != nil {
+		return Material{}, xerrors.ErrMaterialClassInvalid
+	}
+
+	return New(code, field(colDescription), class, field(colClassDescription))
+}
+
+func sniffComma(br *bufio.Reader) rune {
+	line, _ := br.Peek(br.Size())
+	if i := strings.IndexByte(string(line), '\n'); i >= 0 {
+		line = line[:i]
+	}
+
+	if strings.Count(string(line), ";") > strings.Count(string(line), ",") {
+		return ';'
+	}
+
+	return ','
+}
@@ -0,0 +1,96 @@
+package catmat
+
+import (
+	"strings"
+
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/errors"
+)
+
+// Material is an entry of the CATMAT (Catálogo de Materiais) reference
+// table, used by the federal government to classify purchases.
+type Material struct {
+	code             int
+	description      string
+	class            int
+	classDescription string
+}
+
+func New(code int, description string, class int, classDescription string) (Material, error) {
+	var m Material
+
+	err := errors.Join(
+		m.SetCode(code),
+		m.SetDescription(description),
+		m.SetClass(class),
+		m.SetClassDescription(classDescription),
+	)
+	if err != nil {
+		return Material{}, xerrors.ErrMaterialCreation.New(err)
+	}
+
+	return m, nil
+}
+
+func (m *Material) Code() int                { return m.code }
+func (m *Material) Description() string      { return m.description }
+func (m *Material) Class() int               { return m.class }
+func (m *Material) ClassDescription() string { return m.classDescription }
+
+// Group returns the CATMAT group of the material, which is given by
+// the two leading digits of its class.
+func (m *Material) Group() int { return m.class / 100 }
+
+func (m *Material) SetCode(code int) error { return set(&m.code, code, ProcessCode) }
+func (m *Material) SetDescription(desc string) error {
+	return set(&m.description, desc, ProcessDescription)
+}
+func (m *Material) SetClass(class int) error { return set(&m.class, class, ProcessClass) }
+func (m *Material) SetClassDescription(desc string) error {
+	return set(&m.classDescription, desc, ProcessClassDescription)
+}
+
+const (
+	_MaxCode  = 999_999
+	_MinClass = 1000
+	_MaxClass = 9999
+)
+
+func ProcessCode(code int) (int, error) {
+	if code <= 0 || code > _MaxCode {
+		return 0, xerrors.ErrMaterialCodeInvalid
+	}
+
+	return code, nil
+}
+
+func ProcessDescription(description string) (string, error) {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return "", xerrors.ErrMaterialDescriptionEmpty
+	}
+
+	return description, nil
+}
+
+func ProcessClass(class int) (int, error) {
+	if class < _MinClass || class > _MaxClass {
+		return 0, xerrors.ErrMaterialClassInvalid
+	}
+
+	return class, nil
+}
+
+func ProcessClassDescription(description string) (string, error) {
+	return strings.TrimSpace(description), nil
+}
+
+func set[D, S any](dst *D, src S, proc func(S) (D, error)) error {
+	val, err := proc(src)
+	if err != nil {
+		return err
+	}
+
+	*dst = val
+	return nil
+}
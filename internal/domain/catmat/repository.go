@@ -0,0 +1,42 @@
+package catmat
+
+type Repository interface {
+	Lister
+	Getter
+	Importer
+}
+
+type (
+	Lister interface {
+		List(class, offset, limit int) (Entities, error)
+	}
+
+	Getter interface {
+		Get(code int) (Entity, error)
+	}
+
+	Importer interface {
+		Import(materials []Material) (ImportEntity, error)
+	}
+)
+
+type (
+	Entities struct {
+		Offset       int
+		Length       int
+		Records      []Entity
+		TotalRecords int
+	}
+
+	Entity struct {
+		Code             int
+		Description      string
+		Class            int
+		ClassDescription string
+	}
+
+	ImportEntity struct {
+		Created int
+		Updated int
+	}
+)
@@ -0,0 +1,121 @@
+package catmatrepo
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+
+	"github.com/alan-b-lima/almodon/internal/domain/catmat"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+)
+
+// Map is an in-memory CATMAT reference table, kept sorted by code.
+type Map struct {
+	codeIndex map[int]int
+
+	repo []catmat.Material
+	mu   sync.RWMutex
+}
+
+func NewMap() catmat.Repository {
+	repo := Map{
+		codeIndex: make(map[int]int),
+	}
+
+	return &repo
+}
+
+func (m *Map) List(class, offset, limit int) (catmat.Entities, error) {
+	defer m.mu.RUnlock()
+	m.mu.RLock()
+
+	repo := m.repo
+	if class != 0 {
+		repo = m.byClass(class)
+	}
+
+	lo := clamp(0, offset, len(repo))
+	hi := clamp(0, offset+limit, len(repo))
+
+	if lo >= hi {
+		return catmat.Entities{
+			Records:      []catmat.Entity{},
+			TotalRecords: len(repo),
+		}, nil
+	}
+
+	res := make([]catmat.Entity, hi-lo)
+	for i := range repo[lo:hi] {
+		transform(&res[i], &repo[lo+i])
+	}
+
+	return catmat.Entities{
+		Offset:       lo,
+		Length:       len(res),
+		Records:      res,
+		TotalRecords: len(repo),
+	}, nil
+}
+
+func (m *Map) Get(code int) (catmat.Entity, error) {
+	defer m.mu.RUnlock()
+	m.mu.RLock()
+
+	index, in := m.codeIndex[code]
+	if !in {
+		return catmat.Entity{}, xerrors.ErrMaterialNotFound
+	}
+
+	var res catmat.Entity
+	transform(&res, &m.repo[index])
+	return res, nil
+}
+
+func (m *Map) Import(materials []catmat.Material) (catmat.ImportEntity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	var res catmat.ImportEntity
+	for _, mat := range materials {
+		if index, in := m.codeIndex[mat.Code()]; in {
+			m.repo[index] = mat
+			res.Updated++
+			continue
+		}
+
+		m.codeIndex[mat.Code()] = len(m.repo)
+		m.repo = append(m.repo, mat)
+		res.Created++
+	}
+
+	slices.SortFunc(m.repo, func(m0, m1 catmat.Material) int {
+		return cmp.Compare(m0.Code(), m1.Code())
+	})
+	for i := range m.repo {
+		m.codeIndex[m.repo[i].Code()] = i
+	}
+
+	return res, nil
+}
+
+func (m *Map) byClass(class int) []catmat.Material {
+	var res []catmat.Material
+	for _, mat := range m.repo {
+		if mat.Class() == class {
+			res = append(res, mat)
+		}
+	}
+
+	return res
+}
+
+func transform(r *catmat.Entity, m *catmat.Material) {
+	r.Code = m.Code()
+	r.Description = m.Description()
+	r.Class = m.Class()
+	r.ClassDescription = m.ClassDescription()
+}
+
+func clamp[T cmp.Ordered](mn, val, mx T) T {
+	return min(max(mn, val), mx)
+}
@@ -0,0 +1,110 @@
+package catmats
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/alan-b-lima/almodon/internal/domain/catmat"
+	"github.com/alan-b-lima/almodon/internal/domain/user"
+	"github.com/alan-b-lima/almodon/internal/support/resource"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+)
+
+type Resource struct {
+	http.ServeMux
+	Materials catmat.Service
+	Users     user.Service
+}
+
+func New(materials catmat.Service, users user.Service) *Resource {
+	rc := Resource{Materials: materials, Users: users}
+
+	routes := map[string]http.HandlerFunc{
+		"GET /catmat/":        rc.List,
+		"GET /catmat/{code}":  rc.Get,
+		"POST /catmat/import": rc.Import,
+	}
+
+	for route, handler := range routes {
+		rc.Handle(route, handler)
+	}
+
+	return &rc
+}
+
+func (rc *Resource) List(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.Session(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	req := catmat.ListRequest{Offset: 0, Limit: 10}
+	if err := resource.QueryParams(r.URL.Query(), &req); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	res, err := rc.Materials.List(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusOK, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
+
+func (rc *Resource) Get(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.Session(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	code, err := strconv.Atoi(r.PathValue("code"))
+	if err != nil {
+		resource.WriteJsonError(w, xerrors.ErrMaterialCodeInvalid)
+		return
+	}
+	req := catmat.GetRequest{Code: code}
+
+	res, err := rc.Materials.Get(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusOK, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
+
+func (rc *Resource) Import(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.Session(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	body, err := resource.CSVBody(w, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+	req := catmat.ImportRequest{Body: body}
+
+	res, err := rc.Materials.Import(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusOK, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
@@ -0,0 +1,11 @@
+package catmat
+
+import (
+	"github.com/alan-b-lima/almodon/internal/auth"
+)
+
+type Service interface {
+	List(act auth.Actor, req ListRequest) (ListResponse, error)
+	Get(act auth.Actor, req GetRequest) (Response, error)
+	Import(act auth.Actor, req ImportRequest) (ImportResponse, error)
+}
@@ -0,0 +1,41 @@
+package catmatserve
+
+import (
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/domain/audit"
+	"github.com/alan-b-lima/almodon/internal/domain/catmat"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+)
+
+// AuditService records imports in the audit log, as one record for the
+// whole table, along with how many codes were created and updated. It
+// is meant to sit below the [AuthService].
+type AuditService struct {
+	service catmat.Service
+	records audit.Appender
+}
+
+func NewAudited(service catmat.Service, records audit.Appender) catmat.Service {
+	return &AuditService{service: service, records: records}
+}
+
+func (s *AuditService) List(act auth.Actor, req catmat.ListRequest) (catmat.ListResponse, error) {
+	return s.service.List(act, req)
+}
+
+func (s *AuditService) Get(act auth.Actor, req catmat.GetRequest) (catmat.Response, error) {
+	return s.service.Get(act, req)
+}
+
+func (s *AuditService) Import(act auth.Actor, req catmat.ImportRequest) (catmat.ImportResponse, error) {
+	res, err := s.service.Import(act, req)
+	if err != nil {
+		return catmat.ImportResponse{}, err
+	}
+
+	if _, err := audit.Log(s.records, act, actImport, audit.Target{Kind: "catmat"}, nil, res); err != nil {
+		return res, xerrors.ErrAuditRecord.New(err)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,56 @@
+package catmatserve
+
+import (
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/domain/catmat"
+	"github.com/alan-b-lima/almodon/internal/support/service"
+)
+
+type AuthService struct {
+	service catmat.Service
+	policy  *auth.Policy
+}
+
+func New(service catmat.Service, policy *auth.Policy) catmat.Service {
+	return &AuthService{service: service, policy: policy}
+}
+
+const (
+	actList   auth.Action = "catmat.list"
+	actGet    auth.Action = "catmat.get"
+	actImport auth.Action = "catmat.import"
+)
+
+// Actions returns the actions checked by the service, along with their
+// default permissions.
+func Actions() map[auth.Action]auth.Permission {
+	return map[auth.Action]auth.Permission{
+		actList:   auth.Permit(auth.User),
+		actGet:    auth.Permit(auth.User),
+		actImport: auth.Permit(auth.Promoted),
+	}
+}
+
+func (s *AuthService) List(act auth.Actor, req catmat.ListRequest) (catmat.ListResponse, error) {
+	if err := service.Allow(s.policy, actList, act); err != nil {
+		return catmat.ListResponse{}, err
+	}
+
+	return s.service.List(act, req)
+}
+
+func (s *AuthService) Get(act auth.Actor, req catmat.GetRequest) (catmat.Response, error) {
+	if err := service.Allow(s.policy, actGet, act); err != nil {
+		return catmat.Response{}, err
+	}
+
+	return s.service.Get(act, req)
+}
+
+func (s *AuthService) Import(act auth.Actor, req catmat.ImportRequest) (catmat.ImportResponse, error) {
+	if err := service.Allow(s.policy, actImport, act); err != nil {
+		return catmat.ImportResponse{}, err
+	}
+
+	return s.service.Import(act, req)
+}
@@ -0,0 +1,61 @@
+package catmatserve
+
+import (
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/domain/catmat"
+)
+
+type Service struct {
+	Repo catmat.Repository
+}
+
+func NewService(materials catmat.Repository) catmat.Service {
+	return &Service{Repo: materials}
+}
+
+func (s *Service) List(act auth.Actor, req catmat.ListRequest) (catmat.ListResponse, error) {
+	res, err := catmat.List(s.Repo, req.Class, req.Offset, req.Limit)
+	if err != nil {
+		return catmat.ListResponse{}, err
+	}
+
+	lres := catmat.ListResponse{
+		Offset:       res.Offset,
+		Length:       res.Length,
+		Records:      make([]catmat.Response, res.Length),
+		TotalRecords: res.TotalRecords,
+	}
+	for i := range res.Records {
+		transformP(&lres.Records[i], &res.Records[i])
+	}
+
+	return lres, nil
+}
+
+func (s *Service) Get(act auth.Actor, req catmat.GetRequest) (catmat.Response, error) {
+	res, err := catmat.Get(s.Repo, req.Code)
+	if err != nil {
+		return catmat.Response{}, err
+	}
+
+	var r catmat.Response
+	transformP(&r, &res)
+	return r, nil
+}
+
+func (s *Service) Import(act auth.Actor, req catmat.ImportRequest) (catmat.ImportResponse, error) {
+	res, err := catmat.Import(s.Repo, req.Body)
+	if err != nil {
+		return catmat.ImportResponse{}, err
+	}
+
+	return catmat.ImportResponse(res), nil
+}
+
+func transformP(r *catmat.Response, e *catmat.Entity) {
+	r.Code = e.Code
+	r.Description = e.Description
+	r.Class = e.Class
+	r.ClassDescription = e.ClassDescription
+	r.Group = e.Class / 100
+}
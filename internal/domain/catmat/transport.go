@@ -0,0 +1,41 @@
+package catmat
+
+import "io"
+
+type (
+	ListRequest struct {
+		Class  int `query:"class"`
+		Offset int `query:"offset"`
+		Limit  int `query:"limit"`
+	}
+
+	GetRequest struct {
+		Code int `json:"-"`
+	}
+
+	ImportRequest struct {
+		Body io.Reader `json:"-"`
+	}
+)
+
+type (
+	ListResponse struct {
+		Offset       int        `json:"offset"`
+		Length       int        `json:"length"`
+		Records      []Response `json:"records"`
+		TotalRecords int        `json:"total_records"`
+	}
+
+	Response struct {
+		Code             int    `json:"code"`
+		Description      string `json:"description"`
+		Class            int    `json:"class"`
+		ClassDescription string `json:"class_description"`
+		Group            int    `json:"group"`
+	}
+
+	ImportResponse struct {
+		Created int `json:"created"`
+		Updated int `json:"updated"`
+	}
+)
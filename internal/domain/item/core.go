@@ -0,0 +1,74 @@
+package item
+
+import (
+	"fmt"
+
+	"github.com/alan-b-lima/almodon/internal/domain/catmat"
+	"github.com/alan-b-lima/almodon/internal/notify"
+	"github.com/alan-b-lima/almodon/pkg/opt"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+func List(items Lister, filter Filter, offset, limit int) (Entities, error) {
+	return items.List(filter, offset, limit)
+}
+
+func Get(items Getter, uuid uuid.UUID) (Entity, error) {
+	return items.Get(uuid)
+}
+
+// Create creates the item, whose CATMAT code must be in the reference
+// table, the description and class of the code being stored along.
+func Create(items Creater, materials catmat.Getter, name, unit string, code, minStock int) (Entity, error) {
+	material, err := lookup(materials, code)
+	if err != nil {
+		return Entity{}, err
+	}
+
+	return items.Create(name, unit, material, minStock)
+}
+
+// Update changes what is given of the item, the CATMAT code being
+// looked up as in [Create].
+func Update(items Patcher, materials catmat.Getter, uuid uuid.UUID, name, unit opt.Opt[string], code, minStock opt.Opt[int]) (Entity, error) {
+	material := opt.None[catmat.Entity]()
+	if code, ok := code.Unwrap(); ok {
+		res, err := lookup(materials, code)
+		if err != nil {
+			return Entity{}, err
+		}
+
+		material = opt.Some(res)
+	}
+
+	return items.Patch(uuid, name, unit, material, minStock)
+}
+
+func lookup(materials catmat.Getter, code int) (catmat.Entity, error) {
+	if err := catmat.Validate(materials, code); err != nil {
+		return catmat.Entity{}, err
+	}
+
+	return catmat.Get(materials, code)
+}
+
+// AddStock adds to the stock of the item, or takes from it if delta is
+// negative.
+func AddStock(items Stocker, uuid uuid.UUID, delta int) (Entity, error) {
+	return items.AddStock(uuid, delta)
+}
+
+// AlertLow tells to, through the notifier, that the stock of the item
+// is below its minimum. Nothing is sent if to is empty.
+func AlertLow(notifier notify.Notifier, to string, it *Entity) error {
+	if to == "" {
+		return nil
+	}
+
+	return notifier.Notify(notify.Message{
+		To:      to,
+		Subject: "Almodon: estoque baixo de " + it.Name,
+		Body: fmt.Sprintf("O estoque de %[1]s (CATMAT %[2]d) caiu para %[3]d %[4]s, abaixo do mínimo de %[5]d %[4]s.\n",
+			it.Name, it.Material.Code, it.Stock, it.Unit, it.MinStock),
+	})
+}
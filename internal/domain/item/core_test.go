@@ -0,0 +1,121 @@
+package item_test
+
+import (
+	"testing"
+
+	"github.com/alan-b-lima/almodon/internal/domain/catmat"
+	catmatrepo "github.com/alan-b-lima/almodon/internal/domain/catmat/repository"
+	. "github.com/alan-b-lima/almodon/internal/domain/item"
+	itemrepo "github.com/alan-b-lima/almodon/internal/domain/item/repository"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/errors"
+	"github.com/alan-b-lima/almodon/pkg/opt"
+)
+
+func materials(t *testing.T) catmat.Repository {
+	t.Helper()
+
+	repo := catmatrepo.NewMap()
+
+	var table []catmat.Material
+	for _, m := range []struct {
+		code        int
+		description string
+		class       int
+	}{
+		{150505, "Papel A4, 75 g/m²", 7510},
+		{232476, "Luva de procedimento, látex", 6515},
+	} {
+		mat, err := catmat.New(m.code, m.description, m.class, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		table = append(table, mat)
+	}
+
+	if _, err := repo.Import(table); err != nil {
+		t.Fatal(err)
+	}
+
+	return repo
+}
+
+func TestCreate(t *testing.T) {
+	mats := materials(t)
+	items := itemrepo.NewMap()
+
+	res, err := Create(items, mats, " Papel sulfite ", "resma", 150505, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Name != "Papel sulfite" || res.Material.Description != "Papel A4, 75 g/m²" || res.Material.Class != 7510 {
+		t.Errorf("Create() = %+v, expected the description and class of the code", res)
+	}
+
+	if _, err := Create(items, mats, "Caneta", "un", 999999, 0); err != xerrors.ErrMaterialNotFound {
+		t.Errorf("Create() with a code missing from the table = %v, expected %v", err, xerrors.ErrMaterialNotFound)
+	}
+
+	if _, err := Create(items, mats, "Caneta", "un", 0, 0); err != xerrors.ErrMaterialCodeInvalid {
+		t.Errorf("Create() with an invalid code = %v, expected %v", err, xerrors.ErrMaterialCodeInvalid)
+	}
+
+	if _, err := Create(items, mats, "", "", 150505, -1); err == nil {
+		t.Error("Create() with no name, no unit and a negative minimum should fail")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	mats := materials(t)
+	items := itemrepo.NewMap()
+
+	res, err := Create(items, mats, "Luva", "caixa", 150505, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err = Update(items, mats, res.UUID, opt.None[string](), opt.None[string](), opt.Some(232476), opt.Some(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Name != "Luva" || res.Material.Code != 232476 || res.Material.Class != 6515 || res.MinStock != 5 {
+		t.Errorf("Update() = %+v, expected the new code and minimum", res)
+	}
+
+	if _, err := Update(items, mats, res.UUID, opt.None[string](), opt.None[string](), opt.Some(999999), opt.None[int]()); err == nil {
+		t.Error("Update() to a code missing from the table should fail")
+	}
+}
+
+func TestAddStock(t *testing.T) {
+	items := itemrepo.NewMap()
+
+	res, err := Create(items, materials(t), "Papel", "resma", 150505, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res, err = AddStock(items, res.UUID, 12); err != nil || res.Stock != 12 || res.Low() {
+		t.Fatalf("AddStock(12) = %d, %v, expected 12 in stock", res.Stock, err)
+	}
+
+	if res, err = AddStock(items, res.UUID, -3); err != nil || res.Stock != 9 || !res.Low() {
+		t.Fatalf("AddStock(-3) = %d, %v, expected 9 in stock, below the minimum", res.Stock, err)
+	}
+
+	_, err = AddStock(items, res.UUID, -10)
+	if e, ok := errors.AsType[*errors.Error](err); !ok || e.Title != "stock-insufficient" {
+		t.Errorf("AddStock(-10) = %v, expected stock-insufficient", err)
+	}
+
+	if res, _ := Get(items, res.UUID); res.Stock != 9 {
+		t.Errorf("stock is %d after a failed AddStock(), expected 9", res.Stock)
+	}
+
+	low, err := List(items, Filter{Low: opt.Some(true)}, 0, 10)
+	if err != nil || low.TotalRecords != 1 {
+		t.Errorf("List() of low items = %d, %v, expected 1", low.TotalRecords, err)
+	}
+}
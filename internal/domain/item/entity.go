@@ -0,0 +1,129 @@
+package item
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alan-b-lima/almodon/internal/domain/catmat"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/errors"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+// Item is a material kept in the warehouse, classified by its CATMAT
+// code. The description and class of the code are copied from the
+// reference table when the code is set, so items keep them even if
+// the table is later replaced.
+type Item struct {
+	uuid     uuid.UUID
+	name     string
+	unit     string
+	material catmat.Entity
+	minStock int
+	stock    int
+	created  time.Time
+}
+
+func New(name, unit string, material catmat.Entity, minStock int) (Item, error) {
+	var i Item
+
+	err := errors.Join(
+		i.SetName(name),
+		i.SetUnit(unit),
+		i.SetMaterial(material),
+		i.SetMinStock(minStock),
+	)
+	if err != nil {
+		return Item{}, xerrors.ErrItemCreation.New(err)
+	}
+
+	i.uuid = uuid.NewUUIDv7()
+	i.created = time.Now()
+	return i, nil
+}
+
+func (i *Item) UUID() uuid.UUID         { return i.uuid }
+func (i *Item) Name() string            { return i.name }
+func (i *Item) Unit() string            { return i.unit }
+func (i *Item) Material() catmat.Entity { return i.material }
+func (i *Item) MinStock() int           { return i.minStock }
+func (i *Item) Stock() int              { return i.stock }
+func (i *Item) Created() time.Time      { return i.created }
+
+func (i *Item) SetName(name string) error { return set(&i.name, name, ProcessName) }
+func (i *Item) SetUnit(unit string) error { return set(&i.unit, unit, ProcessUnit) }
+func (i *Item) SetMaterial(material catmat.Entity) error {
+	return set(&i.material, material, ProcessMaterial)
+}
+func (i *Item) SetMinStock(min int) error { return set(&i.minStock, min, ProcessMinStock) }
+
+// AddStock adds delta to the stock, which is taken out of it if
+// negative. The stock cannot go below zero.
+func (i *Item) AddStock(delta int) error {
+	if i.stock+delta < 0 {
+		return xerrors.ErrStockInsufficient.New(i.stock)
+	}
+
+	i.stock += delta
+	return nil
+}
+
+const (
+	_MaxNameLength = 200
+	_MaxUnitLength = 20
+)
+
+func ProcessName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", xerrors.ErrItemNameEmpty
+	}
+
+	if utf8.RuneCountInString(name) > _MaxNameLength {
+		return "", xerrors.ErrItemNameTooLong.New(_MaxNameLength)
+	}
+
+	return name, nil
+}
+
+func ProcessUnit(unit string) (string, error) {
+	unit = strings.TrimSpace(unit)
+	if unit == "" {
+		return "", xerrors.ErrItemUnitEmpty
+	}
+
+	if utf8.RuneCountInString(unit) > _MaxUnitLength {
+		return "", xerrors.ErrItemUnitTooLong.New(_MaxUnitLength)
+	}
+
+	return unit, nil
+}
+
+// ProcessMaterial checks the entry of the reference table, which is
+// expected to come from it, see [Create].
+func ProcessMaterial(material catmat.Entity) (catmat.Entity, error) {
+	if _, err := catmat.ProcessCode(material.Code); err != nil {
+		return catmat.Entity{}, err
+	}
+
+	return material, nil
+}
+
+func ProcessMinStock(min int) (int, error) {
+	if min < 0 {
+		return 0, xerrors.ErrItemMinStockNegative
+	}
+
+	return min, nil
+}
+
+func set[D, S any](dst *D, src S, proc func(S) (D, error)) error {
+	val, err := proc(src)
+	if err != nil {
+		return err
+	}
+
+	*dst = val
+	return nil
+}
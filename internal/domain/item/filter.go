@@ -0,0 +1,26 @@
+package item
+
+import "github.com/alan-b-lima/almodon/pkg/opt"
+
+// Filter selects the items of a listing, its zero value selects all
+// of them.
+type Filter struct {
+	// Class selects items of the CATMAT class, zero for any class.
+	Class int
+
+	// Low selects items by whether their stock is below the minimum.
+	Low opt.Opt[bool]
+}
+
+// Match returns whether the filter selects the item.
+func (f *Filter) Match(e *Entity) bool {
+	if f.Class != 0 && e.Material.Class != f.Class {
+		return false
+	}
+
+	if low, ok := f.Low.Unwrap(); ok && e.Low() != low {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,65 @@
+package item
+
+import (
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/domain/catmat"
+	"github.com/alan-b-lima/almodon/pkg/opt"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+type Repository interface {
+	Lister
+	Getter
+	Creater
+	Patcher
+	Stocker
+}
+
+type (
+	Lister interface {
+		List(filter Filter, offset, limit int) (Entities, error)
+	}
+
+	Getter interface {
+		Get(uuid uuid.UUID) (Entity, error)
+	}
+
+	Creater interface {
+		Create(name, unit string, material catmat.Entity, minStock int) (Entity, error)
+	}
+
+	Patcher interface {
+		Patch(uuid uuid.UUID, name, unit opt.Opt[string], material opt.Opt[catmat.Entity], minStock opt.Opt[int]) (Entity, error)
+	}
+
+	// Stocker adds to the stock of items, or takes from it, failing
+	// rather than leaving the stock negative, see [Item.AddStock].
+	Stocker interface {
+		AddStock(uuid uuid.UUID, delta int) (Entity, error)
+	}
+)
+
+type (
+	Entities struct {
+		Offset       int
+		Length       int
+		Records      []Entity
+		TotalRecords int
+	}
+
+	Entity struct {
+		UUID     uuid.UUID
+		Name     string
+		Unit     string
+		Material catmat.Entity
+		MinStock int
+		Stock    int
+		Created  time.Time
+	}
+)
+
+// Low returns whether the stock of the item is below its minimum.
+func (e *Entity) Low() bool {
+	return e.Stock < e.MinStock
+}
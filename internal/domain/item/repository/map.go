@@ -0,0 +1,162 @@
+package itemrepo
+
+import (
+	"cmp"
+	"sync"
+
+	"github.com/alan-b-lima/almodon/internal/domain/catmat"
+	"github.com/alan-b-lima/almodon/internal/domain/item"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/errors"
+	"github.com/alan-b-lima/almodon/pkg/opt"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+// Map is an in-memory item catalog, kept in the order of creation.
+type Map struct {
+	uuidIndex map[uuid.UUID]int
+
+	repo []item.Item
+	mu   sync.RWMutex
+}
+
+func NewMap() item.Repository {
+	repo := Map{
+		uuidIndex: make(map[uuid.UUID]int),
+	}
+
+	return &repo
+}
+
+func (m *Map) List(filter item.Filter, offset, limit int) (item.Entities, error) {
+	defer m.mu.RUnlock()
+	m.mu.RLock()
+
+	var matched []item.Entity
+	for i := range m.repo {
+		var e item.Entity
+		transform(&e, &m.repo[i])
+
+		if filter.Match(&e) {
+			matched = append(matched, e)
+		}
+	}
+
+	lo := clamp(0, offset, len(matched))
+	hi := clamp(0, offset+limit, len(matched))
+
+	if lo >= hi {
+		return item.Entities{
+			Records:      []item.Entity{},
+			TotalRecords: len(matched),
+		}, nil
+	}
+
+	res := matched[lo:hi]
+	return item.Entities{
+		Offset:       lo,
+		Length:       len(res),
+		Records:      res,
+		TotalRecords: len(matched),
+	}, nil
+}
+
+func (m *Map) Get(uuid uuid.UUID) (item.Entity, error) {
+	defer m.mu.RUnlock()
+	m.mu.RLock()
+
+	index, in := m.uuidIndex[uuid]
+	if !in {
+		return item.Entity{}, xerrors.ErrItemNotFound
+	}
+
+	var res item.Entity
+	transform(&res, &m.repo[index])
+	return res, nil
+}
+
+func (m *Map) Create(name, unit string, material catmat.Entity, minStock int) (item.Entity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	i, err := item.New(name, unit, material, minStock)
+	if err != nil {
+		return item.Entity{}, err
+	}
+
+	m.uuidIndex[i.UUID()] = len(m.repo)
+	m.repo = append(m.repo, i)
+
+	var res item.Entity
+	transform(&res, &i)
+	return res, nil
+}
+
+func (m *Map) Patch(uuid uuid.UUID, name, unit opt.Opt[string], material opt.Opt[catmat.Entity], minStock opt.Opt[int]) (item.Entity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	index, in := m.uuidIndex[uuid]
+	if !in {
+		return item.Entity{}, xerrors.ErrItemNotFound
+	}
+
+	i := m.repo[index]
+
+	err := errors.Join(
+		some_then(name, i.SetName),
+		some_then(unit, i.SetUnit),
+		some_then(material, i.SetMaterial),
+		some_then(minStock, i.SetMinStock),
+	)
+	if err != nil {
+		return item.Entity{}, err
+	}
+
+	m.repo[index] = i
+
+	var res item.Entity
+	transform(&res, &i)
+	return res, nil
+}
+
+func (m *Map) AddStock(uuid uuid.UUID, delta int) (item.Entity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	index, in := m.uuidIndex[uuid]
+	if !in {
+		return item.Entity{}, xerrors.ErrItemNotFound
+	}
+
+	i := &m.repo[index]
+	if err := i.AddStock(delta); err != nil {
+		return item.Entity{}, err
+	}
+
+	var res item.Entity
+	transform(&res, i)
+	return res, nil
+}
+
+func some_then[F any](src opt.Opt[F], fn func(F) error) error {
+	val, ok := src.Unwrap()
+	if !ok {
+		return nil
+	}
+	return fn(val)
+}
+
+func transform(r *item.Entity, i *item.Item) {
+	r.UUID = i.UUID()
+	r.Name = i.Name()
+	r.Unit = i.Unit()
+	r.Material = i.Material()
+	r.MinStock = i.MinStock()
+	r.Stock = i.Stock()
+	r.Created = i.Created()
+}
+
+func clamp[T cmp.Ordered](mn, val, mx T) T {
+	return min(max(mn, val), mx)
+}
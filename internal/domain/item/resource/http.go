@@ -0,0 +1,142 @@
+package items
+
+import (
+	"net/http"
+
+	"github.com/alan-b-lima/almodon/internal/domain/item"
+	"github.com/alan-b-lima/almodon/internal/domain/user"
+	"github.com/alan-b-lima/almodon/internal/support/resource"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+type Resource struct {
+	http.ServeMux
+	Items item.Service
+	Users user.Service
+}
+
+func New(items item.Service, users user.Service) *Resource {
+	rc := Resource{Items: items, Users: users}
+
+	routes := map[string]http.HandlerFunc{
+		"GET /items/":         rc.List,
+		"GET /items/{uuid}":   rc.Get,
+		"POST /items/":        rc.Create,
+		"PATCH /items/{uuid}": rc.Update,
+	}
+
+	for route, handler := range routes {
+		rc.Handle(route, handler)
+	}
+
+	return &rc
+}
+
+func (rc *Resource) List(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.Session(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	req := item.ListRequest{Offset: 0, Limit: 10}
+	if err := resource.QueryParams(r.URL.Query(), &req); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	res, err := rc.Items.List(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusOK, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
+
+func (rc *Resource) Get(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.Session(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	uuid, err := uuid.FromString(r.PathValue("uuid"))
+	if err != nil {
+		resource.WriteJsonError(w, xerrors.ErrBadUUID)
+		return
+	}
+	req := item.GetRequest{UUID: uuid}
+
+	res, err := rc.Items.Get(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusOK, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
+
+func (rc *Resource) Create(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.Session(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	var req item.CreateRequest
+
+	if err := resource.DecodeJSON(&req, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	res, err := rc.Items.Create(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusCreated, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
+
+func (rc *Resource) Update(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.Session(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	uuid, err := uuid.FromString(r.PathValue("uuid"))
+	if err != nil {
+		resource.WriteJsonError(w, xerrors.ErrBadUUID)
+		return
+	}
+	req := item.UpdateRequest{UUID: uuid}
+
+	if err := resource.DecodeJSON(&req, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	res, err := rc.Items.Update(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusOK, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
@@ -0,0 +1,12 @@
+package item
+
+import (
+	"github.com/alan-b-lima/almodon/internal/auth"
+)
+
+type Service interface {
+	List(act auth.Actor, req ListRequest) (ListResponse, error)
+	Get(act auth.Actor, req GetRequest) (Response, error)
+	Create(act auth.Actor, req CreateRequest) (Response, error)
+	Update(act auth.Actor, req UpdateRequest) (Response, error)
+}
@@ -0,0 +1,84 @@
+package itemserve
+
+import (
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/domain/audit"
+	"github.com/alan-b-lima/almodon/internal/domain/item"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+// AuditService records changes to the catalog in the audit log, along
+// with the fields of items before and after. Stock is left out of the
+// catalog, it is changed by movements, which are records themselves.
+// It is meant to sit below the [AuthService].
+type AuditService struct {
+	service item.Service
+	records audit.Appender
+}
+
+func NewAudited(service item.Service, records audit.Appender) item.Service {
+	return &AuditService{service: service, records: records}
+}
+
+func itemTarget(uuid uuid.UUID) audit.Target {
+	return audit.Target{Kind: "item", ID: uuid.String()}
+}
+
+func (s *AuditService) List(act auth.Actor, req item.ListRequest) (item.ListResponse, error) {
+	return s.service.List(act, req)
+}
+
+func (s *AuditService) Get(act auth.Actor, req item.GetRequest) (item.Response, error) {
+	return s.service.Get(act, req)
+}
+
+func (s *AuditService) Create(act auth.Actor, req item.CreateRequest) (item.Response, error) {
+	res, err := s.service.Create(act, req)
+	if err != nil {
+		return item.Response{}, err
+	}
+
+	return res, s.log(act, actCreate, itemTarget(res.UUID), nil, &res)
+}
+
+func (s *AuditService) Update(act auth.Actor, req item.UpdateRequest) (item.Response, error) {
+	before, err := s.service.Get(act, item.GetRequest{UUID: req.UUID})
+	if err != nil {
+		return item.Response{}, err
+	}
+
+	res, err := s.service.Update(act, req)
+	if err != nil {
+		return item.Response{}, err
+	}
+
+	return res, s.log(act, actUpdate, itemTarget(res.UUID), &before, &res)
+}
+
+// log records the action, failing as internal, since by then the
+// action was already taken. Items are recorded as in the catalog,
+// without their stock, see [AuditService].
+func (s *AuditService) log(act auth.Actor, action auth.Action, target audit.Target, before, after *item.Response) error {
+	if _, err := audit.Log(s.records, act, action, target, catalog(before), catalog(after)); err != nil {
+		return xerrors.ErrAuditRecord.New(err)
+	}
+
+	return nil
+}
+
+type catalogRecord struct {
+	Name     string              `json:"name"`
+	Unit     string              `json:"unit"`
+	Catmat   item.CatmatResponse `json:"catmat"`
+	MinStock int                 `json:"min_stock"`
+}
+
+// catalog returns the item as in the catalog, nil if there is none.
+func catalog(r *item.Response) any {
+	if r == nil {
+		return nil
+	}
+
+	return catalogRecord{Name: r.Name, Unit: r.Unit, Catmat: r.Catmat, MinStock: r.MinStock}
+}
@@ -0,0 +1,66 @@
+package itemserve
+
+import (
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/domain/item"
+	"github.com/alan-b-lima/almodon/internal/support/service"
+)
+
+type AuthService struct {
+	service item.Service
+	policy  *auth.Policy
+}
+
+func New(service item.Service, policy *auth.Policy) item.Service {
+	return &AuthService{service: service, policy: policy}
+}
+
+const (
+	actList   auth.Action = "item.list"
+	actGet    auth.Action = "item.get"
+	actCreate auth.Action = "item.create"
+	actUpdate auth.Action = "item.update"
+)
+
+// Actions returns the actions checked by the service, along with their
+// default permissions.
+func Actions() map[auth.Action]auth.Permission {
+	return map[auth.Action]auth.Permission{
+		actList:   auth.Permit(auth.User),
+		actGet:    auth.Permit(auth.User),
+		actCreate: auth.Permit(auth.Admin),
+		actUpdate: auth.Permit(auth.Admin),
+	}
+}
+
+func (s *AuthService) List(act auth.Actor, req item.ListRequest) (item.ListResponse, error) {
+	if err := service.Allow(s.policy, actList, act); err != nil {
+		return item.ListResponse{}, err
+	}
+
+	return s.service.List(act, req)
+}
+
+func (s *AuthService) Get(act auth.Actor, req item.GetRequest) (item.Response, error) {
+	if err := service.Allow(s.policy, actGet, act); err != nil {
+		return item.Response{}, err
+	}
+
+	return s.service.Get(act, req)
+}
+
+func (s *AuthService) Create(act auth.Actor, req item.CreateRequest) (item.Response, error) {
+	if err := service.Allow(s.policy, actCreate, act); err != nil {
+		return item.Response{}, err
+	}
+
+	return s.service.Create(act, req)
+}
+
+func (s *AuthService) Update(act auth.Actor, req item.UpdateRequest) (item.Response, error) {
+	if err := service.Allow(s.policy, actUpdate, act); err != nil {
+		return item.Response{}, err
+	}
+
+	return s.service.Update(act, req)
+}
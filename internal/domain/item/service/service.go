@@ -0,0 +1,99 @@
+package itemserve
+
+import (
+	"strconv"
+
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/domain/catmat"
+	"github.com/alan-b-lima/almodon/internal/domain/item"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/opt"
+)
+
+type Service struct {
+	Repo      item.Repository
+	Materials catmat.Getter
+}
+
+func NewService(items item.Repository, materials catmat.Getter) item.Service {
+	return &Service{Repo: items, Materials: materials}
+}
+
+func (s *Service) List(act auth.Actor, req item.ListRequest) (item.ListResponse, error) {
+	filter := item.Filter{Class: req.Class}
+
+	if req.Low != "" {
+		low, err := strconv.ParseBool(req.Low)
+		if err != nil {
+			return item.ListResponse{}, xerrors.ErrItemLowInvalid
+		}
+
+		filter.Low = opt.Some(low)
+	}
+
+	res, err := item.List(s.Repo, filter, req.Offset, req.Limit)
+	if err != nil {
+		return item.ListResponse{}, err
+	}
+
+	lres := item.ListResponse{
+		Offset:       res.Offset,
+		Length:       res.Length,
+		Records:      make([]item.Response, res.Length),
+		TotalRecords: res.TotalRecords,
+	}
+	for i := range res.Records {
+		transformP(&lres.Records[i], &res.Records[i])
+	}
+
+	return lres, nil
+}
+
+func (s *Service) Get(act auth.Actor, req item.GetRequest) (item.Response, error) {
+	res, err := item.Get(s.Repo, req.UUID)
+	if err != nil {
+		return item.Response{}, err
+	}
+
+	return transform(&res), nil
+}
+
+func (s *Service) Create(act auth.Actor, req item.CreateRequest) (item.Response, error) {
+	res, err := item.Create(s.Repo, s.Materials, req.Name, req.Unit, req.Catmat, req.MinStock)
+	if err != nil {
+		return item.Response{}, err
+	}
+
+	return transform(&res), nil
+}
+
+func (s *Service) Update(act auth.Actor, req item.UpdateRequest) (item.Response, error) {
+	res, err := item.Update(s.Repo, s.Materials, req.UUID, req.Name, req.Unit, req.Catmat, req.MinStock)
+	if err != nil {
+		return item.Response{}, err
+	}
+
+	return transform(&res), nil
+}
+
+func transform(e *item.Entity) item.Response {
+	var r item.Response
+	transformP(&r, e)
+	return r
+}
+
+func transformP(r *item.Response, e *item.Entity) {
+	r.UUID = e.UUID
+	r.Name = e.Name
+	r.Unit = e.Unit
+	r.Catmat = item.CatmatResponse{
+		Code:             e.Material.Code,
+		Description:      e.Material.Description,
+		Class:            e.Material.Class,
+		ClassDescription: e.Material.ClassDescription,
+	}
+	r.MinStock = e.MinStock
+	r.Stock = e.Stock
+	r.Low = e.Low()
+	r.Created = e.Created
+}
@@ -0,0 +1,65 @@
+package item
+
+import (
+	"time"
+
+	"github.com/alan-b-lima/almodon/pkg/opt"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+type (
+	// ListRequest filters by CATMAT class and by whether the stock is
+	// below the minimum, given as true or false.
+	ListRequest struct {
+		Class  int    `query:"class"`
+		Low    string `query:"low"`
+		Offset int    `query:"offset"`
+		Limit  int    `query:"limit"`
+	}
+
+	GetRequest struct {
+		UUID uuid.UUID `json:"-"`
+	}
+
+	CreateRequest struct {
+		Name     string `json:"name"`
+		Unit     string `json:"unit"`
+		Catmat   int    `json:"catmat"`
+		MinStock int    `json:"min_stock"`
+	}
+
+	UpdateRequest struct {
+		UUID     uuid.UUID       `json:"-"`
+		Name     opt.Opt[string] `json:"name"`
+		Unit     opt.Opt[string] `json:"unit"`
+		Catmat   opt.Opt[int]    `json:"catmat"`
+		MinStock opt.Opt[int]    `json:"min_stock"`
+	}
+)
+
+type (
+	ListResponse struct {
+		Offset       int        `json:"offset"`
+		Length       int        `json:"length"`
+		Records      []Response `json:"records"`
+		TotalRecords int        `json:"total_records"`
+	}
+
+	Response struct {
+		UUID     uuid.UUID      `json:"uuid"`
+		Name     string         `json:"name"`
+		Unit     string         `json:"unit"`
+		Catmat   CatmatResponse `json:"catmat"`
+		MinStock int            `json:"min_stock"`
+		Stock    int            `json:"stock"`
+		Low      bool           `json:"low"`
+		Created  time.Time      `json:"created"`
+	}
+
+	CatmatResponse struct {
+		Code             int    `json:"code"`
+		Description      string `json:"description"`
+		Class            int    `json:"class"`
+		ClassDescription string `json:"class_description"`
+	}
+)
@@ -0,0 +1,154 @@
+package movement
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+
+	"github.com/alan-b-lima/almodon/internal/domain/item"
+	"github.com/alan-b-lima/almodon/internal/notify"
+	"github.com/alan-b-lima/almodon/pkg/errors"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+func List(movements Lister, filter Filter, offset, limit int) (Entities, error) {
+	return movements.List(filter, offset, limit)
+}
+
+// Walk walks over the movements selected by the filter, the oldest
+// first.
+func Walk(movements Walker, filter Filter) iter.Seq[Entity] {
+	return movements.Walk(filter)
+}
+
+// Record records the movement and changes the stock of the item by
+// it, an exit failing if there is not enough in stock. The movement
+// is checked before the stock is touched, and the stock is restored
+// if the movement cannot be recorded after all. If the stock falls
+// below the minimum of the item, alertTo is told through the
+// notifier, as of [item.AlertLow].
+func Record(movements Creater, items item.Stocker, notifier notify.Notifier, alertTo string, uuid uuid.UUID, kind Kind, quantity int, location, costCenter string, user uuid.UUID) (Entity, error) {
+	m, err := New(uuid, 0, kind, quantity, location, costCenter, user)
+	if err != nil {
+		return Entity{}, err
+	}
+
+	it, err := items.AddStock(uuid, m.Delta())
+	if err != nil {
+		return Entity{}, err
+	}
+
+	res, err := movements.Create(uuid, it.Material.Class, kind, quantity, location, costCenter, user)
+	if err != nil {
+		_, rerr := items.AddStock(uuid, -m.Delta())
+		return Entity{}, errors.Join(err, rerr)
+	}
+
+	// only the movement crossing the minimum alerts, not every one
+	// after it. The movement is not failed by the alert, as it would
+	// be recorded again if retried.
+	if it.Low() && it.Stock-m.Delta() >= it.MinStock {
+		item.AlertLow(notifier, alertTo, &it)
+	}
+
+	return res, nil
+}
+
+// Group is what consumption is totaled by.
+type Group uint8
+
+const (
+	ByItem Group = iota + 1
+	ByLocation
+	ByCostCenter
+	ByUser
+	ByClass
+)
+
+// GroupFromString returns the Group of the given name.
+func GroupFromString(str string) (Group, bool) {
+	switch str {
+	case "item":
+		return ByItem, true
+	case "location":
+		return ByLocation, true
+	case "cost-center":
+		return ByCostCenter, true
+	case "user":
+		return ByUser, true
+	case "catmat-class":
+		return ByClass, true
+	}
+
+	return 0, false
+}
+
+// Total is how much was consumed by a group, over how many exits of
+// how many distinct items. Only the field the totals are grouped by
+// is set.
+type Total struct {
+	Item       uuid.UUID
+	Location   string
+	CostCenter string
+	User       uuid.UUID
+	Class      int
+
+	Quantity  int
+	Movements int
+	Items     int
+}
+
+// Consumption totals the exits selected by the filter by the group,
+// the largest quantities first, or for classes, the most exits. Movements are walked one at a time,
+// only the totals are kept.
+func Consumption(movements Walker, filter Filter, group Group) []Total {
+	filter.Kind = Exit
+
+	index := make(map[Total]int)
+	seen := make(map[Total]map[uuid.UUID]struct{})
+	var totals []Total
+	for e := range movements.Walk(filter) {
+		var key Total
+		switch group {
+		case ByItem:
+			key.Item = e.Item
+		case ByLocation:
+			key.Location = e.Location
+		case ByCostCenter:
+			key.CostCenter = e.CostCenter
+		case ByUser:
+			key.User = e.User
+		case ByClass:
+			key.Class = e.Class
+		}
+
+		i, in := index[key]
+		if !in {
+			i = len(totals)
+			index[key] = i
+			seen[key] = make(map[uuid.UUID]struct{})
+			totals = append(totals, key)
+		}
+
+		totals[i].Quantity += e.Quantity
+		totals[i].Movements++
+
+		if _, in := seen[key][e.Item]; !in {
+			seen[key][e.Item] = struct{}{}
+			totals[i].Items++
+		}
+	}
+
+	// ties keep the order in which groups were first consumed, and as
+	// the items of a class may be counted in different units, classes
+	// are ordered by their exits instead
+	slices.SortStableFunc(totals, func(a, b Total) int {
+		if group == ByClass {
+			return cmp.Compare(b.Movements, a.Movements)
+		}
+
+		return cmp.Compare(b.Quantity, a.Quantity)
+	})
+
+	return totals
+}
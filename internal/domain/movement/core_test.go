@@ -0,0 +1,195 @@
+package movement_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/domain/catmat"
+	"github.com/alan-b-lima/almodon/internal/domain/item"
+	itemrepo "github.com/alan-b-lima/almodon/internal/domain/item/repository"
+	. "github.com/alan-b-lima/almodon/internal/domain/movement"
+	movementrepo "github.com/alan-b-lima/almodon/internal/domain/movement/repository"
+	"github.com/alan-b-lima/almodon/internal/notify"
+	"github.com/alan-b-lima/almodon/pkg/errors"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+func newItem(t *testing.T, items item.Repository, name string, class int) uuid.UUID {
+	t.Helper()
+
+	res, err := items.Create(name, "un", catmat.Entity{Code: 150505, Class: class}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return res.UUID
+}
+
+func stock(t *testing.T, items item.Getter, uuid uuid.UUID) int {
+	t.Helper()
+
+	res, err := items.Get(uuid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return res.Stock
+}
+
+func TestRecord(t *testing.T) {
+	items := itemrepo.NewMap()
+	movements := movementrepo.NewMap()
+
+	paper := newItem(t, items, "Papel", 7510)
+	user := uuid.NewUUIDv7()
+
+	if _, err := Record(movements, items, nil, "", paper, Entry, 10, "Almoxarifado", "", user); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Record(movements, items, nil, "", paper, Exit, 4, "Almoxarifado", "DCOMP", user); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := stock(t, items, paper); got != 6 {
+		t.Fatalf("stock is %d, expected 6", got)
+	}
+
+	_, err := Record(movements, items, nil, "", paper, Exit, 7, "Almoxarifado", "DCOMP", user)
+	if e, ok := errors.AsType[*errors.Error](err); !ok || e.Title != "stock-insufficient" {
+		t.Errorf("Record() of more than in stock = %v, expected stock-insufficient", err)
+	}
+
+	if _, err := Record(movements, items, nil, "", paper, Exit, 1, "Almoxarifado", "", user); err == nil {
+		t.Error("Record() of an exit without a cost center should fail")
+	}
+
+	if _, err := Record(movements, items, nil, "", paper, Entry, 0, "Almoxarifado", "", user); err == nil {
+		t.Error("Record() of no quantity should fail")
+	}
+
+	if _, err := Record(movements, items, nil, "", uuid.NewUUIDv7(), Entry, 1, "Almoxarifado", "", user); err == nil {
+		t.Error("Record() of an unknown item should fail")
+	}
+
+	if got := stock(t, items, paper); got != 6 {
+		t.Errorf("stock is %d after failed movements, expected 6", got)
+	}
+
+	res, err := List(movements, Filter{}, 0, 10)
+	if err != nil || res.TotalRecords != 2 {
+		t.Errorf("List() = %d, %v, expected the 2 movements recorded", res.TotalRecords, err)
+	}
+}
+
+type inbox []notify.Message
+
+func (in *inbox) Notify(msg notify.Message) error {
+	*in = append(*in, msg)
+	return nil
+}
+
+func TestRecordAlertsLowStock(t *testing.T) {
+	items := itemrepo.NewMap()
+	movements := movementrepo.NewMap()
+
+	res, err := items.Create("Papel", "resma", catmat.Entity{Code: 150505, Class: 7510}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var in inbox
+	user := uuid.NewUUIDv7()
+	for _, m := range []struct {
+		kind     Kind
+		quantity int
+		alerts   int
+	}{
+		{Entry, 10, 0},
+		{Exit, 5, 0},
+		{Exit, 1, 1},
+		{Exit, 1, 1},
+		{Entry, 10, 1},
+		{Exit, 9, 2},
+	} {
+		if _, err := Record(movements, items, &in, "almoxarifado@ufvjm.edu.br", res.UUID, m.kind, m.quantity, "Almoxarifado", "DCOMP", user); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(in) != m.alerts {
+			t.Fatalf("%d alerts after %v of %d, expected %d", len(in), m.kind, m.quantity, m.alerts)
+		}
+	}
+
+	if in[0].To != "almoxarifado@ufvjm.edu.br" || !strings.Contains(in[0].Body, "4 resma") {
+		t.Errorf("unexpected alert %+v", in[0])
+	}
+}
+
+func TestConsumption(t *testing.T) {
+	items := itemrepo.NewMap()
+	movements := movementrepo.NewMap()
+
+	paper := newItem(t, items, "Papel", 7510)
+	pens := newItem(t, items, "Caneta", 7510)
+	gloves := newItem(t, items, "Luva", 6515)
+	lucas, breno := uuid.NewUUIDv7(), uuid.NewUUIDv7()
+
+	start := time.Now()
+	for _, m := range []struct {
+		item       uuid.UUID
+		kind       Kind
+		quantity   int
+		location   string
+		costCenter string
+		user       uuid.UUID
+	}{
+		{paper, Entry, 100, "Almoxarifado", "", lucas},
+		{gloves, Entry, 100, "Almoxarifado", "", lucas},
+		{paper, Exit, 5, "Bloco A", "DCOMP", lucas},
+		{gloves, Exit, 20, "Bloco B", "DEMEC", breno},
+		{paper, Exit, 3, "Bloco B", "DCOMP", breno},
+		{pens, Entry, 10, "Almoxarifado", "", lucas},
+		{pens, Exit, 1, "Bloco A", "DCOMP", lucas},
+	} {
+		if _, err := Record(movements, items, nil, "", m.item, m.kind, m.quantity, m.location, m.costCenter, m.user); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := map[Group][]Total{
+		ByItem: {
+			{Item: gloves, Quantity: 20, Movements: 1, Items: 1},
+			{Item: paper, Quantity: 8, Movements: 2, Items: 1},
+			{Item: pens, Quantity: 1, Movements: 1, Items: 1},
+		},
+		ByLocation: {
+			{Location: "Bloco B", Quantity: 23, Movements: 2, Items: 2},
+			{Location: "Bloco A", Quantity: 6, Movements: 2, Items: 2},
+		},
+		ByCostCenter: {
+			{CostCenter: "DEMEC", Quantity: 20, Movements: 1, Items: 1},
+			{CostCenter: "DCOMP", Quantity: 9, Movements: 3, Items: 2},
+		},
+		ByUser: {
+			{User: breno, Quantity: 23, Movements: 2, Items: 2},
+			{User: lucas, Quantity: 6, Movements: 2, Items: 2},
+		},
+		ByClass: {
+			{Class: 7510, Quantity: 9, Movements: 3, Items: 2},
+			{Class: 6515, Quantity: 20, Movements: 1, Items: 1},
+		},
+	}
+
+	for group, want := range tests {
+		if got := Consumption(movements, Filter{From: start}, group); !slices.Equal(got, want) {
+			t.Errorf("Consumption(%d) = %+v, expected %+v", group, got, want)
+		}
+	}
+
+	if got := Consumption(movements, Filter{To: start}, ByItem); len(got) != 0 {
+		t.Errorf("Consumption() before any movement = %+v, expected none", got)
+	}
+}
@@ -0,0 +1,161 @@
+package movement
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/errors"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+// Kind is whether a movement brings items into the warehouse or takes
+// them out of it.
+type Kind uint8
+
+const (
+	Entry Kind = iota + 1
+	Exit
+)
+
+// KindFromString returns the Kind of the given name.
+func KindFromString(str string) (Kind, bool) {
+	switch str {
+	case "entry":
+		return Entry, true
+	case "exit":
+		return Exit, true
+	}
+
+	return 0, false
+}
+
+func (k Kind) String() string {
+	switch k {
+	case Entry:
+		return "entry"
+	case Exit:
+		return "exit"
+	}
+
+	return ""
+}
+
+// Movement is an entry of items into the warehouse, or an exit of them
+// out of it, by a user. Exits are what is consumed, and are charged to
+// a cost center. Movements are never changed once recorded, a wrong
+// one is undone by another of the opposite kind.
+//
+// The CATMAT class of the item is copied into the movement, so that
+// consumption is reported by the class the item had when consumed.
+type Movement struct {
+	uuid       uuid.UUID
+	item       uuid.UUID
+	class      int
+	kind       Kind
+	quantity   int
+	location   string
+	costCenter string
+	user       uuid.UUID
+	time       time.Time
+}
+
+func New(item uuid.UUID, class int, kind Kind, quantity int, location, costCenter string, user uuid.UUID) (Movement, error) {
+	var m Movement
+
+	err := errors.Join(
+		set(&m.kind, kind, ProcessKind),
+		set(&m.quantity, quantity, ProcessQuantity),
+		set(&m.location, location, ProcessLocation),
+		set(&m.costCenter, costCenter, ProcessCostCenter),
+	)
+	if err == nil && m.kind == Exit && m.costCenter == "" {
+		err = xerrors.ErrMovementCostCenterEmpty
+	}
+	if err != nil {
+		return Movement{}, xerrors.ErrMovementCreation.New(err)
+	}
+
+	m.uuid = uuid.NewUUIDv7()
+	m.item = item
+	m.class = class
+	m.user = user
+	m.time = time.Now()
+	return m, nil
+}
+
+func (m *Movement) UUID() uuid.UUID    { return m.uuid }
+func (m *Movement) Item() uuid.UUID    { return m.item }
+func (m *Movement) Class() int         { return m.class }
+func (m *Movement) Kind() Kind         { return m.kind }
+func (m *Movement) Quantity() int      { return m.quantity }
+func (m *Movement) Location() string   { return m.location }
+func (m *Movement) CostCenter() string { return m.costCenter }
+func (m *Movement) User() uuid.UUID    { return m.user }
+func (m *Movement) Time() time.Time    { return m.time }
+
+// Delta returns how much the movement changes the stock of the item.
+func (m *Movement) Delta() int {
+	if m.kind == Exit {
+		return -m.quantity
+	}
+
+	return m.quantity
+}
+
+const (
+	_MaxQuantity         = 1_000_000
+	_MaxLocationLength   = 100
+	_MaxCostCenterLength = 100
+)
+
+func ProcessKind(kind Kind) (Kind, error) {
+	if kind != Entry && kind != Exit {
+		return 0, xerrors.ErrMovementKindInvalid
+	}
+
+	return kind, nil
+}
+
+func ProcessQuantity(quantity int) (int, error) {
+	if quantity <= 0 || quantity > _MaxQuantity {
+		return 0, xerrors.ErrMovementQuantityInvalid.New(_MaxQuantity)
+	}
+
+	return quantity, nil
+}
+
+func ProcessLocation(location string) (string, error) {
+	location = strings.TrimSpace(location)
+	if location == "" {
+		return "", xerrors.ErrMovementLocationEmpty
+	}
+
+	if utf8.RuneCountInString(location) > _MaxLocationLength {
+		return "", xerrors.ErrMovementLocationTooLong.New(_MaxLocationLength)
+	}
+
+	return location, nil
+}
+
+// ProcessCostCenter checks the cost center, which may only be left
+// out of entries.
+func ProcessCostCenter(costCenter string) (string, error) {
+	costCenter = strings.TrimSpace(costCenter)
+	if utf8.RuneCountInString(costCenter) > _MaxCostCenterLength {
+		return "", xerrors.ErrMovementCostCenterTooLong.New(_MaxCostCenterLength)
+	}
+
+	return costCenter, nil
+}
+
+func set[D, S any](dst *D, src S, proc func(S) (D, error)) error {
+	val, err := proc(src)
+	if err != nil {
+		return err
+	}
+
+	*dst = val
+	return nil
+}
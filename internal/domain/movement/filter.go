@@ -0,0 +1,38 @@
+package movement
+
+import (
+	"time"
+
+	"github.com/alan-b-lima/almodon/pkg/opt"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+// Filter selects movements by item, by kind, zero for any, and by
+// time, within [From, To), either bound left out if zero.
+type Filter struct {
+	Item opt.Opt[uuid.UUID]
+	Kind Kind
+	From time.Time
+	To   time.Time
+}
+
+// Match returns whether the movement passes the filter.
+func (f *Filter) Match(e *Entity) bool {
+	if item, ok := f.Item.Unwrap(); ok && e.Item != item {
+		return false
+	}
+
+	if f.Kind != 0 && e.Kind != f.Kind {
+		return false
+	}
+
+	if !f.From.IsZero() && e.Time.Before(f.From) {
+		return false
+	}
+
+	if !f.To.IsZero() && !e.Time.Before(f.To) {
+		return false
+	}
+
+	return true
+}
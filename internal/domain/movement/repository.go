@@ -0,0 +1,52 @@
+package movement
+
+import (
+	"iter"
+	"time"
+
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+type Repository interface {
+	Lister
+	Walker
+	Creater
+}
+
+type (
+	// Lister lists movements, the latest first.
+	Lister interface {
+		List(filter Filter, offset, limit int) (Entities, error)
+	}
+
+	// Walker walks over the movements, the oldest first, one at a time,
+	// so that long periods are never loaded all at once.
+	Walker interface {
+		Walk(filter Filter) iter.Seq[Entity]
+	}
+
+	Creater interface {
+		Create(item uuid.UUID, class int, kind Kind, quantity int, location, costCenter string, user uuid.UUID) (Entity, error)
+	}
+)
+
+type (
+	Entities struct {
+		Offset       int
+		Length       int
+		Records      []Entity
+		TotalRecords int
+	}
+
+	Entity struct {
+		UUID       uuid.UUID
+		Item       uuid.UUID
+		Class      int
+		Kind       Kind
+		Quantity   int
+		Location   string
+		CostCenter string
+		User       uuid.UUID
+		Time       time.Time
+	}
+)
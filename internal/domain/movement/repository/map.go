@@ -0,0 +1,119 @@
+package movementrepo
+
+import (
+	"cmp"
+	"iter"
+	"sort"
+	"sync"
+
+	"github.com/alan-b-lima/almodon/internal/domain/movement"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+// Map is an in-memory log of movements, kept in the order they were
+// recorded, which is also the order of their times.
+type Map struct {
+	repo []movement.Movement
+	mu   sync.RWMutex
+}
+
+func NewMap() movement.Repository {
+	return &Map{}
+}
+
+func (m *Map) List(filter movement.Filter, offset, limit int) (movement.Entities, error) {
+	defer m.mu.RUnlock()
+	m.mu.RLock()
+
+	var matched []movement.Entity
+	for i := len(m.repo) - 1; i >= 0; i-- {
+		var e movement.Entity
+		transform(&e, &m.repo[i])
+
+		if filter.Match(&e) {
+			matched = append(matched, e)
+		}
+	}
+
+	lo := clamp(0, offset, len(matched))
+	hi := clamp(0, offset+limit, len(matched))
+
+	if lo >= hi {
+		return movement.Entities{
+			Records:      []movement.Entity{},
+			TotalRecords: len(matched),
+		}, nil
+	}
+
+	res := matched[lo:hi]
+	return movement.Entities{
+		Offset:       lo,
+		Length:       len(res),
+		Records:      res,
+		TotalRecords: len(matched),
+	}, nil
+}
+
+// Walk walks over the movements recorded by the time it is called.
+// Movements are only ever appended and never changed, so the log is
+// walked without holding the lock, which would otherwise block every
+// movement recorded while a report is downloaded.
+func (m *Map) Walk(filter movement.Filter) iter.Seq[movement.Entity] {
+	m.mu.RLock()
+	repo := m.repo
+	m.mu.RUnlock()
+
+	return func(yield func(movement.Entity) bool) {
+		start := 0
+		if !filter.From.IsZero() {
+			start = sort.Search(len(repo), func(i int) bool {
+				return !repo[i].Time().Before(filter.From)
+			})
+		}
+
+		for i := start; i < len(repo); i++ {
+			if !filter.To.IsZero() && !repo[i].Time().Before(filter.To) {
+				return
+			}
+
+			var e movement.Entity
+			transform(&e, &repo[i])
+
+			if filter.Match(&e) && !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+func (m *Map) Create(item uuid.UUID, class int, kind movement.Kind, quantity int, location, costCenter string, user uuid.UUID) (movement.Entity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	mv, err := movement.New(item, class, kind, quantity, location, costCenter, user)
+	if err != nil {
+		return movement.Entity{}, err
+	}
+
+	m.repo = append(m.repo, mv)
+
+	var res movement.Entity
+	transform(&res, &mv)
+	return res, nil
+}
+
+func transform(r *movement.Entity, m *movement.Movement) {
+	r.UUID = m.UUID()
+	r.Item = m.Item()
+	r.Class = m.Class()
+	r.Kind = m.Kind()
+	r.Quantity = m.Quantity()
+	r.Location = m.Location()
+	r.CostCenter = m.CostCenter()
+	r.User = m.User()
+	r.Time = m.Time()
+}
+
+func clamp[T cmp.Ordered](mn, val, mx T) T {
+	return min(max(mn, val), mx)
+}
@@ -0,0 +1,133 @@
+package movements
+
+import (
+	"net/http"
+
+	"github.com/alan-b-lima/almodon/internal/domain/movement"
+	"github.com/alan-b-lima/almodon/internal/domain/user"
+	"github.com/alan-b-lima/almodon/internal/support/resource"
+)
+
+type Resource struct {
+	http.ServeMux
+	Movements movement.Service
+	Users     user.Service
+}
+
+func New(movements movement.Service, users user.Service) *Resource {
+	rc := Resource{Movements: movements, Users: users}
+
+	routes := map[string]http.HandlerFunc{
+		"GET /movements/":            rc.List,
+		"POST /movements/":           rc.Record,
+		"GET /movements/report":      rc.Report,
+		"GET /movements/consumption": rc.Consumption,
+	}
+
+	for route, handler := range routes {
+		rc.Handle(route, handler)
+	}
+
+	return &rc
+}
+
+func (rc *Resource) List(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.Session(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	req := movement.ListRequest{Offset: 0, Limit: 10}
+	if err := resource.QueryParams(r.URL.Query(), &req); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	res, err := rc.Movements.List(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusOK, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
+
+func (rc *Resource) Record(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.Session(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	var req movement.RecordRequest
+
+	if err := resource.DecodeJSON(&req, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	res, err := rc.Movements.Record(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusCreated, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
+
+func (rc *Resource) Report(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.Session(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	var req movement.ReportRequest
+	if err := resource.QueryParams(r.URL.Query(), &req); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	res, err := rc.Movements.Report(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.WriteReport(w, res.Format, res.Name, res.Header, res.Rows); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
+
+func (rc *Resource) Consumption(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.Session(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	var req movement.ConsumptionRequest
+	if err := resource.QueryParams(r.URL.Query(), &req); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	res, err := rc.Movements.Consumption(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.WriteReport(w, res.Format, res.Name, res.Header, res.Rows); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
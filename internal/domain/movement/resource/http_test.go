@@ -0,0 +1,124 @@
+package movements_test
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/domain/catmat"
+	catmatrepo "github.com/alan-b-lima/almodon/internal/domain/catmat/repository"
+	itemrepo "github.com/alan-b-lima/almodon/internal/domain/item/repository"
+	movementrepo "github.com/alan-b-lima/almodon/internal/domain/movement/repository"
+	. "github.com/alan-b-lima/almodon/internal/domain/movement/resource"
+	movementserve "github.com/alan-b-lima/almodon/internal/domain/movement/service"
+	"github.com/alan-b-lima/almodon/internal/domain/user"
+	userrepo "github.com/alan-b-lima/almodon/internal/domain/user/repository"
+	"github.com/alan-b-lima/almodon/internal/support/resource"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+// sessions stands for a service whose every session belongs to the
+// same user.
+type sessions struct {
+	user.Service
+	owner uuid.UUID
+}
+
+func (s *sessions) Actor(req user.ActorRequest) (auth.Actor, error) {
+	return auth.NewLogged(s.owner, auth.Admin), nil
+}
+
+func TestConsumptionReport(t *testing.T) {
+	users := userrepo.NewMap()
+	lucas, err := users.Create(user.Identities{SIAPE: 3}, "Lucas Rocha Oliveira", "l@ufvjm.edu.br", "12345678", auth.Admin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	materials := catmatrepo.NewMap()
+	mat, err := catmat.New(150505, "Papel A4, 75 g/m²", 7510, "Artigos de escritório")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := materials.Import([]catmat.Material{mat}); err != nil {
+		t.Fatal(err)
+	}
+
+	items := itemrepo.NewMap()
+	paper, err := items.Create("Papel sulfite", "resma", catmat.Entity{Code: 150505, Class: 7510}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := movementserve.NewService(movementrepo.NewMap(), items, users, materials, nil, "", time.UTC)
+	rc := New(svc, &sessions{owner: lucas.UUID})
+
+	request := func(method, target, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, target, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("Accept", "application/json")
+		r.AddCookie(&http.Cookie{Name: resource.SessionCookieName, Value: uuid.NewUUIDv7().String()})
+
+		w := httptest.NewRecorder()
+		rc.ServeHTTP(w, r)
+		return w
+	}
+
+	for _, body := range []string{
+		`{"item":"` + paper.UUID.String() + `","kind":"entry","quantity":10,"location":"Almoxarifado"}`,
+		`{"item":"` + paper.UUID.String() + `","kind":"exit","quantity":4,"location":"Bloco A","cost_center":"=DCOMP"}`,
+	} {
+		if w := request(http.MethodPost, "/movements/", body); w.Code != http.StatusCreated {
+			t.Fatalf("POST /movements/ got %d: %s", w.Code, w.Body)
+		}
+	}
+
+	month := time.Now().UTC().Format("2006-01")
+
+	tests := map[string][][]string{
+		"item":         {{"item", "unit", "catmat", "quantity", "movements"}, {"Papel sulfite", "resma", "150505", "4", "1"}},
+		"cost-center":  {{"cost_center", "quantity", "movements"}, {"'=DCOMP", "4", "1"}},
+		"user":         {{"user", "quantity", "movements"}, {"Lucas Rocha Oliveira", "4", "1"}},
+		"catmat-class": {{"class", "class_description", "items", "movements"}, {"7510", "Artigos de escritório", "1", "1"}},
+	}
+
+	for by, want := range tests {
+		w := request(http.MethodGet, "/movements/consumption?by="+by+"&month="+month, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("consumption by %s got %d: %s", by, w.Code, w.Body)
+		}
+
+		if cd := w.Header().Get("Content-Disposition"); !strings.Contains(cd, "consumption-"+by+"-"+month+".csv") {
+			t.Errorf("consumption by %s is named %q", by, cd)
+		}
+
+		got, err := csv.NewReader(w.Body).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !slices.EqualFunc(got, want, slices.Equal) {
+			t.Errorf("consumption by %s = %q, expected %q", by, got, want)
+		}
+	}
+
+	if w := request(http.MethodGet, "/movements/report?month="+month+"&format=xlsx", ""); w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet" {
+		t.Errorf("movements report got %d, %s", w.Code, w.Header().Get("Content-Type"))
+	}
+
+	for _, target := range []string{
+		"/movements/consumption?by=item&month=2025-13",
+		"/movements/consumption?by=shelf&month=" + month,
+		"/movements/report?month=" + month + "&format=pdf",
+		"/movements/report",
+	} {
+		if w := request(http.MethodGet, target, ""); w.Code != http.StatusBadRequest || w.Header().Get("Content-Disposition") != "" {
+			t.Errorf("GET %s got %d, expected %d and no file", target, w.Code, http.StatusBadRequest)
+		}
+	}
+}
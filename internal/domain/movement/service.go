@@ -0,0 +1,12 @@
+package movement
+
+import (
+	"github.com/alan-b-lima/almodon/internal/auth"
+)
+
+type Service interface {
+	List(act auth.Actor, req ListRequest) (ListResponse, error)
+	Record(act auth.Actor, req RecordRequest) (Response, error)
+	Report(act auth.Actor, req ReportRequest) (ReportResponse, error)
+	Consumption(act auth.Actor, req ConsumptionRequest) (ReportResponse, error)
+}
@@ -0,0 +1,47 @@
+package movementserve
+
+import (
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/domain/audit"
+	"github.com/alan-b-lima/almodon/internal/domain/movement"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+)
+
+// AuditService records movements in the audit log, as the movement
+// recorded, since they are never changed afterwards. Reports only read
+// movements, so they are not recorded. It is meant to sit below the
+// [AuthService].
+type AuditService struct {
+	service movement.Service
+	records audit.Appender
+}
+
+func NewAudited(service movement.Service, records audit.Appender) movement.Service {
+	return &AuditService{service: service, records: records}
+}
+
+func (s *AuditService) List(act auth.Actor, req movement.ListRequest) (movement.ListResponse, error) {
+	return s.service.List(act, req)
+}
+
+func (s *AuditService) Record(act auth.Actor, req movement.RecordRequest) (movement.Response, error) {
+	res, err := s.service.Record(act, req)
+	if err != nil {
+		return movement.Response{}, err
+	}
+
+	target := audit.Target{Kind: "movement", ID: res.UUID.String()}
+	if _, err := audit.Log(s.records, act, actRecord, target, nil, res); err != nil {
+		return res, xerrors.ErrAuditRecord.New(err)
+	}
+
+	return res, nil
+}
+
+func (s *AuditService) Report(act auth.Actor, req movement.ReportRequest) (movement.ReportResponse, error) {
+	return s.service.Report(act, req)
+}
+
+func (s *AuditService) Consumption(act auth.Actor, req movement.ConsumptionRequest) (movement.ReportResponse, error) {
+	return s.service.Consumption(act, req)
+}
@@ -0,0 +1,67 @@
+package movementserve
+
+import (
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/domain/movement"
+	"github.com/alan-b-lima/almodon/internal/support/service"
+)
+
+type AuthService struct {
+	service movement.Service
+	policy  *auth.Policy
+}
+
+func New(service movement.Service, policy *auth.Policy) movement.Service {
+	return &AuthService{service: service, policy: policy}
+}
+
+const (
+	actList        auth.Action = "movement.list"
+	actRecord      auth.Action = "movement.record"
+	actReport      auth.Action = "movement.report"
+	actConsumption auth.Action = "movement.consumption"
+)
+
+// Actions returns the actions checked by the service, along with their
+// default permissions.
+func Actions() map[auth.Action]auth.Permission {
+	return map[auth.Action]auth.Permission{
+		actList:        auth.Permit(auth.Admin),
+		actRecord:      auth.Permit(auth.Admin),
+		actReport:      auth.Permit(auth.Promoted),
+		actConsumption: auth.Permit(auth.Promoted),
+	}
+}
+
+func (s *AuthService) List(act auth.Actor, req movement.ListRequest) (movement.ListResponse, error) {
+	if err := service.Allow(s.policy, actList, act); err != nil {
+		return movement.ListResponse{}, err
+	}
+
+	return s.service.List(act, req)
+}
+
+// Record may also be granted by the location of the movement.
+func (s *AuthService) Record(act auth.Actor, req movement.RecordRequest) (movement.Response, error) {
+	if err := service.AllowOn(s.policy, actRecord, act, auth.Resource{Location: req.Location}); err != nil {
+		return movement.Response{}, err
+	}
+
+	return s.service.Record(act, req)
+}
+
+func (s *AuthService) Report(act auth.Actor, req movement.ReportRequest) (movement.ReportResponse, error) {
+	if err := service.Allow(s.policy, actReport, act); err != nil {
+		return movement.ReportResponse{}, err
+	}
+
+	return s.service.Report(act, req)
+}
+
+func (s *AuthService) Consumption(act auth.Actor, req movement.ConsumptionRequest) (movement.ReportResponse, error) {
+	if err := service.Allow(s.policy, actConsumption, act); err != nil {
+		return movement.ReportResponse{}, err
+	}
+
+	return s.service.Consumption(act, req)
+}
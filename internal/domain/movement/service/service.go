@@ -0,0 +1,311 @@
+package movementserve
+
+import (
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/domain/catmat"
+	"github.com/alan-b-lima/almodon/internal/domain/item"
+	"github.com/alan-b-lima/almodon/internal/domain/movement"
+	"github.com/alan-b-lima/almodon/internal/domain/user"
+	"github.com/alan-b-lima/almodon/internal/notify"
+	"github.com/alan-b-lima/almodon/internal/support/report"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/opt"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+type Service struct {
+	Repo      movement.Repository
+	Items     item.Repository
+	Users     user.Getter
+	Materials catmat.Lister
+	Notifier  notify.Notifier
+	AlertTo   string
+	Location  *time.Location
+}
+
+// NewService creates the service, periods being taken in the given
+// location. Low stock is alerted to alertTo through the notifier, if
+// alertTo is given.
+func NewService(movements movement.Repository, items item.Repository, users user.Getter, materials catmat.Lister, notifier notify.Notifier, alertTo string, loc *time.Location) movement.Service {
+	return &Service{Repo: movements, Items: items, Users: users, Materials: materials, Notifier: notifier, AlertTo: alertTo, Location: loc}
+}
+
+func (s *Service) List(act auth.Actor, req movement.ListRequest) (movement.ListResponse, error) {
+	var filter movement.Filter
+
+	if req.Item != "" {
+		item, err := uuid.FromString(req.Item)
+		if err != nil {
+			return movement.ListResponse{}, xerrors.ErrBadUUID
+		}
+
+		filter.Item = opt.Some(item)
+	}
+
+	if req.Kind != "" {
+		kind, ok := movement.KindFromString(req.Kind)
+		if !ok {
+			return movement.ListResponse{}, xerrors.ErrMovementKindInvalid
+		}
+
+		filter.Kind = kind
+	}
+
+	if req.Month != "" || req.From != "" || req.To != "" {
+		period, err := report.ParsePeriod(req.Month, req.From, req.To, s.Location)
+		if err != nil {
+			return movement.ListResponse{}, err
+		}
+
+		filter.From, filter.To = period.From, period.To
+	}
+
+	res, err := movement.List(s.Repo, filter, req.Offset, req.Limit)
+	if err != nil {
+		return movement.ListResponse{}, err
+	}
+
+	lres := movement.ListResponse{
+		Offset:       res.Offset,
+		Length:       res.Length,
+		Records:      make([]movement.Response, res.Length),
+		TotalRecords: res.TotalRecords,
+	}
+	for i := range res.Records {
+		transformP(&lres.Records[i], &res.Records[i])
+	}
+
+	return lres, nil
+}
+
+func (s *Service) Record(act auth.Actor, req movement.RecordRequest) (movement.Response, error) {
+	kind, ok := movement.KindFromString(req.Kind)
+	if !ok {
+		return movement.Response{}, xerrors.ErrMovementKindInvalid
+	}
+
+	res, err := movement.Record(s.Repo, s.Items, s.Notifier, s.AlertTo, req.Item, kind, req.Quantity, req.Location, req.CostCenter, act.User())
+	if err != nil {
+		return movement.Response{}, err
+	}
+
+	var r movement.Response
+	transformP(&r, &res)
+	return r, nil
+}
+
+func (s *Service) Report(act auth.Actor, req movement.ReportRequest) (movement.ReportResponse, error) {
+	format, period, err := s.parse(req.Format, req.Month, req.From, req.To)
+	if err != nil {
+		return movement.ReportResponse{}, err
+	}
+
+	filter := movement.Filter{From: period.From, To: period.To}
+	names := s.names()
+
+	rows := func(yield func([]any, error) bool) {
+		for e := range movement.Walk(s.Repo, filter) {
+			it, err := names.item(e.Item)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			name, err := names.user(e.User)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			row := []any{e.Time.In(s.Location), e.Kind.String(), it.Name, it.Unit, it.Material.Code, e.Quantity, e.Location, e.CostCenter, name}
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+
+	return movement.ReportResponse{
+		Name:   "movements-" + period.String(),
+		Format: format,
+		Header: []string{"time", "kind", "item", "unit", "catmat", "quantity", "location", "cost_center", "user"},
+		Rows:   rows,
+	}, nil
+}
+
+func (s *Service) Consumption(act auth.Actor, req movement.ConsumptionRequest) (movement.ReportResponse, error) {
+	group, ok := movement.GroupFromString(req.By)
+	if !ok {
+		return movement.ReportResponse{}, xerrors.ErrConsumptionGroupInvalid
+	}
+
+	format, period, err := s.parse(req.Format, req.Month, req.From, req.To)
+	if err != nil {
+		return movement.ReportResponse{}, err
+	}
+
+	filter := movement.Filter{From: period.From, To: period.To}
+	names := s.names()
+
+	var header []string
+	switch group {
+	case movement.ByItem:
+		header = []string{"item", "unit", "catmat", "quantity"}
+	case movement.ByLocation:
+		header = []string{"location", "quantity"}
+	case movement.ByCostCenter:
+		header = []string{"cost_center", "quantity"}
+	case movement.ByUser:
+		header = []string{"user", "quantity"}
+	case movement.ByClass:
+		header = []string{"class", "class_description", "items"}
+	}
+	header = append(header, "movements")
+
+	// totals are computed once the file is written, not before
+	rows := func(yield func([]any, error) bool) {
+		for _, t := range movement.Consumption(s.Repo, filter, group) {
+			var row []any
+			switch group {
+			case movement.ByItem:
+				it, err := names.item(t.Item)
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				row = []any{it.Name, it.Unit, it.Material.Code, t.Quantity}
+			case movement.ByLocation:
+				row = []any{t.Location, t.Quantity}
+			case movement.ByCostCenter:
+				row = []any{t.CostCenter, t.Quantity}
+			case movement.ByUser:
+				name, err := names.user(t.User)
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				row = []any{name, t.Quantity}
+			case movement.ByClass:
+				desc, err := names.class(t.Class)
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				row = []any{t.Class, desc, t.Items}
+			}
+
+			if !yield(append(row, t.Movements), nil) {
+				return
+			}
+		}
+	}
+
+	return movement.ReportResponse{
+		Name:   "consumption-" + req.By + "-" + period.String(),
+		Format: format,
+		Header: header,
+		Rows:   rows,
+	}, nil
+}
+
+func (s *Service) parse(format, month, from, to string) (report.Format, report.Period, error) {
+	f, err := report.FormatFromString(format)
+	if err != nil {
+		return 0, report.Period{}, err
+	}
+
+	p, err := report.ParsePeriod(month, from, to, s.Location)
+	if err != nil {
+		return 0, report.Period{}, err
+	}
+
+	return f, p, nil
+}
+
+// names looks up the items, users and CATMAT classes of a report,
+// each only once.
+type names struct {
+	items     item.Getter
+	users     user.Getter
+	materials catmat.Lister
+	byItem    map[uuid.UUID]item.Entity
+	byUser    map[uuid.UUID]string
+	byClass   map[int]string
+}
+
+func (s *Service) names() *names {
+	return &names{
+		items:     s.Items,
+		users:     s.Users,
+		materials: s.Materials,
+		byItem:    make(map[uuid.UUID]item.Entity),
+		byUser:    make(map[uuid.UUID]string),
+		byClass:   make(map[int]string),
+	}
+}
+
+// class returns the description of the CATMAT class, as given by any
+// material of it in the reference table, empty if there is none.
+func (n *names) class(class int) (string, error) {
+	if desc, in := n.byClass[class]; in {
+		return desc, nil
+	}
+
+	res, err := catmat.List(n.materials, class, 0, 1)
+	if err != nil {
+		return "", err
+	}
+
+	var desc string
+	if len(res.Records) > 0 {
+		desc = res.Records[0].ClassDescription
+	}
+
+	n.byClass[class] = desc
+	return desc, nil
+}
+
+func (n *names) item(uuid uuid.UUID) (item.Entity, error) {
+	if res, in := n.byItem[uuid]; in {
+		return res, nil
+	}
+
+	res, err := item.Get(n.items, uuid)
+	if err != nil {
+		return item.Entity{}, err
+	}
+
+	n.byItem[uuid] = res
+	return res, nil
+}
+
+// user returns the name of the user, or their UUID if they were
+// purged since.
+func (n *names) user(uuid uuid.UUID) (string, error) {
+	if name, in := n.byUser[uuid]; in {
+		return name, nil
+	}
+
+	res, err := user.Get(n.users, uuid)
+	if err == xerrors.ErrUserNotFound {
+		res.Name, err = uuid.String(), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	n.byUser[uuid] = res.Name
+	return res.Name, nil
+}
+
+func transformP(r *movement.Response, e *movement.Entity) {
+	r.UUID = e.UUID
+	r.Item = e.Item
+	r.Kind = e.Kind.String()
+	r.Quantity = e.Quantity
+	r.Location = e.Location
+	r.CostCenter = e.CostCenter
+	r.User = e.User
+	r.Time = e.Time
+}
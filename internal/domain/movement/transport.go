@@ -0,0 +1,85 @@
+package movement
+
+import (
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/support/report"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+type (
+	// ListRequest filters by the UUID of the item, by kind, entry or
+	// exit, and by period, either a month, as in 2025-09, or a range
+	// of dates, to included. All movements are listed if no period is
+	// given.
+	ListRequest struct {
+		Item   string `query:"item"`
+		Kind   string `query:"kind"`
+		Month  string `query:"month"`
+		From   string `query:"from"`
+		To     string `query:"to"`
+		Offset int    `query:"offset"`
+		Limit  int    `query:"limit"`
+	}
+
+	// RecordRequest records a movement by the actor. The cost center
+	// is only required of exits.
+	RecordRequest struct {
+		Item       uuid.UUID `json:"item"`
+		Kind       string    `json:"kind"`
+		Quantity   int       `json:"quantity"`
+		Location   string    `json:"location"`
+		CostCenter string    `json:"cost_center"`
+	}
+
+	// ReportRequest asks for the movements of a period, given as in
+	// [ListRequest] but required, as a file of the format, csv or
+	// xlsx.
+	ReportRequest struct {
+		Month  string `query:"month"`
+		From   string `query:"from"`
+		To     string `query:"to"`
+		Format string `query:"format"`
+	}
+
+	// ConsumptionRequest asks for the exits of a period totaled by
+	// item, location, cost-center, user or catmat-class, as in
+	// [ReportRequest]. Quantities are not totaled by class, as the
+	// items of a class may be counted in different units.
+	ConsumptionRequest struct {
+		By     string `query:"by"`
+		Month  string `query:"month"`
+		From   string `query:"from"`
+		To     string `query:"to"`
+		Format string `query:"format"`
+	}
+)
+
+type (
+	ListResponse struct {
+		Offset       int        `json:"offset"`
+		Length       int        `json:"length"`
+		Records      []Response `json:"records"`
+		TotalRecords int        `json:"total_records"`
+	}
+
+	Response struct {
+		UUID       uuid.UUID `json:"uuid"`
+		Item       uuid.UUID `json:"item"`
+		Kind       string    `json:"kind"`
+		Quantity   int       `json:"quantity"`
+		Location   string    `json:"location"`
+		CostCenter string    `json:"cost_center,omitempty"`
+		User       uuid.UUID `json:"user"`
+		Time       time.Time `json:"time"`
+	}
+
+	// ReportResponse is a report to be written as a file, named after
+	// Name, whose rows are only produced as they are written.
+	ReportResponse struct {
+		Name   string        `json:"-"`
+		Format report.Format `json:"-"`
+		Header []string      `json:"-"`
+		Rows   report.Rows   `json:"-"`
+	}
+)
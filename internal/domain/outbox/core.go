@@ -0,0 +1,27 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+func Enqueue(repo Enqueuer, to, subject, body string) (Entity, error) {
+	return repo.Enqueue(to, subject, body)
+}
+
+func Due(repo DueLister, now time.Time, limit int) ([]Entity, error) {
+	return repo.Due(now, limit)
+}
+
+func NextDue(repo DueLister) (time.Time, bool, error) {
+	return repo.NextDue()
+}
+
+func Deliver(repo Marker, uuid uuid.UUID) (Entity, error) {
+	return repo.Deliver(uuid)
+}
+
+func Retry(repo Marker, uuid uuid.UUID, cause error, now time.Time) (Entity, error) {
+	return repo.Retry(uuid, cause, now)
+}
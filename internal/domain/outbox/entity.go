@@ -0,0 +1,146 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/errors"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+// Status is the delivery status of an envelope.
+type Status uint8
+
+const (
+	// Pending envelopes are waiting for their next delivery attempt.
+	Pending Status = iota
+
+	// Delivered envelopes were accepted by the transport.
+	Delivered
+
+	// Failed envelopes exhausted their delivery attempts.
+	Failed
+)
+
+// String returns the string representation of the Status.
+func (s Status) String() string {
+	switch s {
+	case Pending:
+		return "pending"
+	case Delivered:
+		return "delivered"
+	case Failed:
+		return "failed"
+	}
+
+	return "unknown"
+}
+
+const (
+	_MaxAttempts = 10
+	_BaseBackoff = 30 * time.Second
+	_MaxBackoff  = 2 * time.Hour
+)
+
+// Envelope is a message waiting in the outbox, along with the state
+// of its delivery.
+type Envelope struct {
+	uuid    uuid.UUID
+	to      string
+	subject string
+	body    string
+
+	status      Status
+	attempts    int
+	nextAttempt time.Time
+	lastError   string
+	created     time.Time
+}
+
+func New(to, subject, body string) (Envelope, error) {
+	var e Envelope
+
+	err := errors.Join(
+		e.setTo(to),
+		e.setSubject(subject),
+		e.setBody(body),
+	)
+	if err != nil {
+		return Envelope{}, xerrors.ErrEnvelopeCreation.New(err)
+	}
+
+	e.uuid = uuid.NewUUIDv7()
+	e.created = time.Now()
+	e.nextAttempt = e.created
+	return e, nil
+}
+
+func (e *Envelope) UUID() uuid.UUID        { return e.uuid }
+func (e *Envelope) To() string             { return e.to }
+func (e *Envelope) Subject() string        { return e.subject }
+func (e *Envelope) Body() string           { return e.body }
+func (e *Envelope) Status() Status         { return e.status }
+func (e *Envelope) Attempts() int          { return e.attempts }
+func (e *Envelope) NextAttempt() time.Time { return e.nextAttempt }
+func (e *Envelope) LastError() string      { return e.lastError }
+func (e *Envelope) Created() time.Time     { return e.created }
+
+// IsDue returns whether the envelope must be attempted at time now.
+func (e *Envelope) IsDue(now time.Time) bool {
+	return e.status == Pending && !now.Before(e.nextAttempt)
+}
+
+// Deliver marks the envelope as delivered.
+func (e *Envelope) Deliver() {
+	e.attempts++
+	e.status = Delivered
+	e.lastError = ""
+}
+
+// Retry records a failed delivery attempt, scheduling the next one
+// with exponential backoff, or marking the envelope as failed once
+// the attempts are exhausted.
+func (e *Envelope) Retry(cause error, now time.Time) {
+	e.attempts++
+	e.lastError = cause.Error()
+
+	if e.attempts >= _MaxAttempts {
+		e.status = Failed
+		return
+	}
+
+	e.nextAttempt = now.Add(Backoff(e.attempts))
+}
+
+// Backoff returns the delay before the attempt following the given
+// number of failed attempts.
+func Backoff(attempts int) time.Duration {
+	delay := _BaseBackoff
+	for range attempts - 1 {
+		delay *= 2
+		if delay >= _MaxBackoff {
+			return _MaxBackoff
+		}
+	}
+
+	return delay
+}
+
+func (e *Envelope) setTo(to string) error {
+	if to == "" {
+		return xerrors.ErrEnvelopeNoRecipient
+	}
+
+	e.to = to
+	return nil
+}
+
+func (e *Envelope) setSubject(subject string) error {
+	e.subject = subject
+	return nil
+}
+
+func (e *Envelope) setBody(body string) error {
+	e.body = body
+	return nil
+}
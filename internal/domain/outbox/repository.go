@@ -0,0 +1,43 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+type Repository interface {
+	Enqueuer
+	DueLister
+	Marker
+}
+
+type (
+	Enqueuer interface {
+		Enqueue(to, subject, body string) (Entity, error)
+	}
+
+	DueLister interface {
+		Due(now time.Time, limit int) ([]Entity, error)
+		NextDue() (time.Time, bool, error)
+	}
+
+	Marker interface {
+		Deliver(uuid uuid.UUID) (Entity, error)
+		Retry(uuid uuid.UUID, cause error, now time.Time) (Entity, error)
+	}
+)
+
+type (
+	Entity struct {
+		UUID        uuid.UUID
+		To          string
+		Subject     string
+		Body        string
+		Status      Status
+		Attempts    int
+		NextAttempt time.Time
+		LastError   string
+		Created     time.Time
+	}
+)
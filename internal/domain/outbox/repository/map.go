@@ -0,0 +1,164 @@
+package outboxrepo
+
+import (
+	"sync"
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/domain/outbox"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/heap"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+// Map keeps only pending envelopes, delivered and failed ones are
+// dropped as soon as they are marked, as their bodies may hold
+// secrets, such as recovery tokens. Pending envelopes are ordered by
+// their next attempt, so the due ones are found without going through
+// the others.
+type Map struct {
+	repo map[uuid.UUID]*outbox.Envelope
+	due  heap.Heap[attempt]
+	mu   sync.Mutex
+}
+
+func NewMap() outbox.Repository {
+	repo := Map{
+		repo: make(map[uuid.UUID]*outbox.Envelope),
+	}
+
+	return &repo
+}
+
+func (m *Map) Enqueue(to, subject, body string) (outbox.Entity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	e, err := outbox.New(to, subject, body)
+	if err != nil {
+		return outbox.Entity{}, err
+	}
+
+	m.repo[e.UUID()] = &e
+	m.due.Push(attempt{e.UUID(), e.NextAttempt()})
+
+	var res outbox.Entity
+	transform(&res, &e)
+	return res, nil
+}
+
+func (m *Map) Due(now time.Time, limit int) ([]outbox.Entity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	var (
+		res   = []outbox.Entity{}
+		taken []attempt
+		seen  = make(map[uuid.UUID]bool)
+	)
+	for len(res) < limit && m.due.Len() > 0 && !now.Before(m.due.Peek().at) {
+		a := m.due.Pop()
+
+		e, ok := m.current(a)
+		if !ok || seen[a.envelope] {
+			continue
+		}
+		seen[a.envelope] = true
+
+		var r outbox.Entity
+		transform(&r, e)
+		res = append(res, r)
+		taken = append(taken, a)
+	}
+
+	// the envelopes stay pending until they are marked
+	for _, a := range taken {
+		m.due.Push(a)
+	}
+
+	return res, nil
+}
+
+func (m *Map) NextDue() (time.Time, bool, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	for m.due.Len() > 0 {
+		a := m.due.Peek()
+		if _, ok := m.current(a); ok {
+			return a.at, true, nil
+		}
+
+		m.due.Pop()
+	}
+
+	return time.Time{}, false, nil
+}
+
+func (m *Map) Deliver(uuid uuid.UUID) (outbox.Entity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	e, in := m.repo[uuid]
+	if !in {
+		return outbox.Entity{}, xerrors.ErrEnvelopeNotFound
+	}
+
+	e.Deliver()
+	delete(m.repo, uuid)
+
+	var res outbox.Entity
+	transform(&res, e)
+	return res, nil
+}
+
+func (m *Map) Retry(uuid uuid.UUID, cause error, now time.Time) (outbox.Entity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	e, in := m.repo[uuid]
+	if !in {
+		return outbox.Entity{}, xerrors.ErrEnvelopeNotFound
+	}
+
+	e.Retry(cause, now)
+	if e.Status() == outbox.Failed {
+		delete(m.repo, uuid)
+	} else {
+		m.due.Push(attempt{uuid, e.NextAttempt()})
+	}
+
+	var res outbox.Entity
+	transform(&res, e)
+	return res, nil
+}
+
+// current returns the envelope of the attempt, if it is still pending
+// and the attempt was not rescheduled since. Outdated attempts are
+// left in the heap until they reach its top.
+func (m *Map) current(a attempt) (*outbox.Envelope, bool) {
+	e, in := m.repo[a.envelope]
+	if !in || !e.NextAttempt().Equal(a.at) {
+		return nil, false
+	}
+
+	return e, true
+}
+
+func transform(r *outbox.Entity, e *outbox.Envelope) {
+	r.UUID = e.UUID()
+	r.To = e.To()
+	r.Subject = e.Subject()
+	r.Body = e.Body()
+	r.Status = e.Status()
+	r.Attempts = e.Attempts()
+	r.NextAttempt = e.NextAttempt()
+	r.LastError = e.LastError()
+	r.Created = e.Created()
+}
+
+type attempt struct {
+	envelope uuid.UUID
+	at       time.Time
+}
+
+func (a0 attempt) Less(a1 attempt) bool { return a0.at.Before(a1.at) }
@@ -0,0 +1,94 @@
+package outboxrepo_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/domain/outbox"
+	. "github.com/alan-b-lima/almodon/internal/domain/outbox/repository"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+func TestDue(t *testing.T) {
+	repo := NewMap()
+
+	var enqueued []outbox.Entity
+	for _, to := range []string{"a@ufvjm.edu.br", "b@ufvjm.edu.br", "c@ufvjm.edu.br"} {
+		res, err := repo.Enqueue(to, "Assunto", "Corpo")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		enqueued = append(enqueued, res)
+	}
+
+	now := time.Now()
+	if _, err := repo.Retry(enqueued[0].UUID, errors.New("refused"), now); err != nil {
+		t.Fatal(err)
+	}
+
+	due, err := repo.Due(now, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(due) != 2 || due[0].UUID == enqueued[0].UUID || due[1].UUID == enqueued[0].UUID {
+		t.Fatalf("expected the envelopes not retried to be due, got %+v", due)
+	}
+
+	if due, _ := repo.Due(now, 1); len(due) != 1 {
+		t.Errorf("expected the limit to be kept, got %d envelopes", len(due))
+	}
+
+	next, ok, err := repo.NextDue()
+	if err != nil || !ok || next.After(now) {
+		t.Errorf("expected an envelope to be due by now, got %v, %v, %v", next, ok, err)
+	}
+
+	later := now.Add(outbox.Backoff(1))
+	if due, _ := repo.Due(later, 10); len(due) != 3 || due[2].UUID != enqueued[0].UUID {
+		t.Errorf("expected the retried envelope to be due last, got %+v", due)
+	}
+}
+
+func TestDeliverPrunes(t *testing.T) {
+	repo := NewMap()
+
+	delivered, err := repo.Enqueue("a@ufvjm.edu.br", "Redefinição de senha", "Seu código é: 1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failed, err := repo.Enqueue("b@ufvjm.edu.br", "Redefinição de senha", "Seu código é: 5678")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := repo.Deliver(delivered.UUID)
+	if err != nil || res.Status != outbox.Delivered {
+		t.Fatalf("expected the envelope to be delivered, got %v, %v", res.Status, err)
+	}
+
+	now := time.Now()
+	for res.Status != outbox.Failed {
+		res, err = repo.Retry(failed.UUID, errors.New("refused"), now)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, id := range [...]uuid.UUID{delivered.UUID, failed.UUID} {
+		if _, err := repo.Deliver(id); err == nil {
+			t.Errorf("envelope %v should have been dropped", id)
+		}
+	}
+
+	if due, _ := repo.Due(now.Add(24*time.Hour), 10); len(due) != 0 {
+		t.Errorf("expected no envelope to be kept, got %+v", due)
+	}
+
+	if _, ok, _ := repo.NextDue(); ok {
+		t.Error("expected no envelope to be due")
+	}
+}
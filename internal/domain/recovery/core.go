@@ -0,0 +1,38 @@
+package recovery
+
+import (
+	"time"
+
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+const _MaxAge = 15 * time.Minute
+
+// Create creates a recovery token for the user, replacing any token
+// previously created for them.
+func Create(repo Creater, user uuid.UUID) (CreateEntity, error) {
+	return repo.Create(user, _MaxAge)
+}
+
+// Get returns the token of the given secret, leaving it to be
+// consumed.
+func Get(repo Getter, secret string) (Entity, error) {
+	return repo.Get(secret)
+}
+
+// Consume invalidates the token of the given secret and returns it,
+// a token can only be consumed once.
+func Consume(repo Consumer, secret string) (Entity, error) {
+	return repo.Consume(secret)
+}
+
+// CreateWithMaxAge is like [Create], for tokens lasting the given
+// duration, as those of new accounts.
+func CreateWithMaxAge(repo Creater, user uuid.UUID, maxAge time.Duration) (CreateEntity, error) {
+	return repo.Create(user, maxAge)
+}
+
+// DeleteByUser revokes the token of the user, if any.
+func DeleteByUser(repo DeleterByUser, user uuid.UUID) error {
+	return repo.DeleteByUser(user)
+}
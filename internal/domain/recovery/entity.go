@@ -0,0 +1,68 @@
+package recovery
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/errors"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+const _MaxMaxAge = 1 * time.Hour
+
+// Token is a single-use password recovery token. Only the hash of the
+// secret is kept, the secret itself is handed to the user once.
+type Token struct {
+	hash    [32]byte
+	user    uuid.UUID
+	expires time.Time
+}
+
+// New creates a new token for the user, it returns the token and its
+// secret.
+func New(user uuid.UUID, maxAge time.Duration) (Token, string, error) {
+	var t Token
+
+	err := errors.Join(
+		t.setUser(user),
+		t.SetMaxAge(maxAge),
+	)
+	if err != nil {
+		return Token{}, "", err
+	}
+
+	var secret [32]byte
+	rand.Read(secret[:])
+
+	str := base64.RawURLEncoding.EncodeToString(secret[:])
+	t.hash = Hash(str)
+
+	return t, str, nil
+}
+
+// Hash returns the hash under which the token of the given secret is
+// stored.
+func Hash(secret string) [32]byte {
+	return sha256.Sum256([]byte(secret))
+}
+
+func (t *Token) Hash() [32]byte     { return t.hash }
+func (t *Token) User() uuid.UUID    { return t.user }
+func (t *Token) Expires() time.Time { return t.expires }
+
+func (t *Token) setUser(uuid uuid.UUID) error {
+	t.user = uuid
+	return nil
+}
+
+func (t *Token) SetMaxAge(maxAge time.Duration) error {
+	if maxAge > _MaxMaxAge {
+		return xerrors.ErrRecoveryTokenTooLong.New(_MaxMaxAge)
+	}
+
+	t.expires = time.Now().Add(maxAge)
+	return nil
+}
@@ -0,0 +1,48 @@
+package recovery
+
+import (
+	"time"
+
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+type Repository interface {
+	Creater
+	Getter
+	Consumer
+	DeleterByUser
+}
+
+type (
+	Creater interface {
+		Create(user uuid.UUID, maxAge time.Duration) (CreateEntity, error)
+	}
+
+	// Getter looks a token up without consuming it, failing as
+	// [Consumer] would if it is invalid or expired.
+	Getter interface {
+		Get(secret string) (Entity, error)
+	}
+
+	Consumer interface {
+		Consume(secret string) (Entity, error)
+	}
+
+	// DeleterByUser revokes the token of the user, if any.
+	DeleterByUser interface {
+		DeleteByUser(user uuid.UUID) error
+	}
+)
+
+type (
+	Entity struct {
+		User    uuid.UUID
+		Expires time.Time
+	}
+
+	CreateEntity struct {
+		Secret  string
+		User    uuid.UUID
+		Expires time.Time
+	}
+)
@@ -0,0 +1,123 @@
+package recoveryrepo
+
+import (
+	"sync"
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/domain/recovery"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+type Map struct {
+	hashIndex map[[32]byte]uuid.UUID
+	userIndex map[uuid.UUID]recovery.Token
+
+	mu sync.Mutex
+}
+
+func NewMap() recovery.Repository {
+	repo := Map{
+		hashIndex: make(map[[32]byte]uuid.UUID),
+		userIndex: make(map[uuid.UUID]recovery.Token),
+	}
+
+	return &repo
+}
+
+func (m *Map) Create(user uuid.UUID, maxAge time.Duration) (recovery.CreateEntity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	t, secret, err := recovery.New(user, maxAge)
+	if err != nil {
+		return recovery.CreateEntity{}, err
+	}
+
+	if old, in := m.userIndex[user]; in {
+		delete(m.hashIndex, old.Hash())
+	}
+	m.purge()
+
+	m.hashIndex[t.Hash()] = user
+	m.userIndex[user] = t
+
+	res := recovery.CreateEntity{
+		Secret:  secret,
+		User:    t.User(),
+		Expires: t.Expires(),
+	}
+	return res, nil
+}
+
+func (m *Map) Get(secret string) (recovery.Entity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	user, in := m.hashIndex[recovery.Hash(secret)]
+	if !in {
+		return recovery.Entity{}, xerrors.ErrRecoveryTokenInvalid
+	}
+
+	t := m.userIndex[user]
+	if time.Now().After(t.Expires()) {
+		return recovery.Entity{}, xerrors.ErrRecoveryTokenInvalid
+	}
+
+	res := recovery.Entity{
+		User:    t.User(),
+		Expires: t.Expires(),
+	}
+	return res, nil
+}
+
+func (m *Map) Consume(secret string) (recovery.Entity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	hash := recovery.Hash(secret)
+
+	user, in := m.hashIndex[hash]
+	if !in {
+		return recovery.Entity{}, xerrors.ErrRecoveryTokenInvalid
+	}
+
+	t := m.userIndex[user]
+
+	delete(m.hashIndex, hash)
+	delete(m.userIndex, user)
+
+	if time.Now().After(t.Expires()) {
+		return recovery.Entity{}, xerrors.ErrRecoveryTokenInvalid
+	}
+
+	res := recovery.Entity{
+		User:    t.User(),
+		Expires: t.Expires(),
+	}
+	return res, nil
+}
+
+func (m *Map) DeleteByUser(user uuid.UUID) error {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	if t, in := m.userIndex[user]; in {
+		delete(m.hashIndex, t.Hash())
+		delete(m.userIndex, user)
+	}
+
+	return nil
+}
+
+// purge removes expired tokens, tokens are few and short-lived, so a
+// linear sweep on creation suffices.
+func (m *Map) purge() {
+	now := time.Now()
+	for user, t := range m.userIndex {
+		if now.After(t.Expires()) {
+			delete(m.hashIndex, t.Hash())
+			delete(m.userIndex, user)
+		}
+	}
+}
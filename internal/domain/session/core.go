@@ -12,13 +12,17 @@ func Get(repo Getter, uuid uuid.UUID) (Entity, error) {
 	return repo.Get(uuid)
 }
 
+func ListByUser(repo ListerByUser, user uuid.UUID) ([]Entity, error) {
+	return repo.ListByUser(user)
+}
+
 // TODO: verify validity of _MaxAge and turn it to an internal error
-func Create(repo Creater, uuid uuid.UUID) (Entity, error) {
-	return repo.Create(uuid, _MaxAge)
+func Create(repo Creater, uuid uuid.UUID, userAgent, address string) (Entity, error) {
+	return repo.Create(uuid, _MaxAge, userAgent, address)
 }
 
-func CreateWithMaxAge(repo Creater, uuid uuid.UUID, maxAge time.Duration) (Entity, error) {
-	return repo.Create(uuid, maxAge)
+func CreateWithMaxAge(repo Creater, uuid uuid.UUID, maxAge time.Duration, userAgent, address string) (Entity, error) {
+	return repo.Create(uuid, maxAge, userAgent, address)
 }
 
 // TODO: verify validity of _MaxAge and turn it to an internal error
@@ -29,3 +33,53 @@ func Update(repo Updater, uuid uuid.UUID) (Entity, error) {
 func UpdateWithMaxAge(repo Updater, uuid uuid.UUID, maxAge time.Duration) (Entity, error) {
 	return repo.Update(uuid, maxAge)
 }
+
+// Renew slides the expiry of the session according to the policy. The
+// returned flag tells whether the session was renewed at all, which is
+// not the case if the gain would fall under the policy's renewal
+// interval, unless that brings the session to its absolute limit.
+func Renew(repo interface {
+	Getter
+	Updater
+}, policy Policy, uuid uuid.UUID) (Entity, bool, error) {
+	s, err := repo.Get(uuid)
+	if err != nil {
+		return Entity{}, false, err
+	}
+
+	now := time.Now()
+	expires, limit := policy.expiry(s.Created, now)
+
+	gain := expires.Sub(s.Expires)
+	if gain <= 0 || gain < policy.Renewal && !limit {
+		return s, false, nil
+	}
+
+	s, err = repo.Update(uuid, expires.Sub(now))
+	if err != nil {
+		return Entity{}, false, err
+	}
+
+	return s, true, nil
+}
+
+// Touch returns the session, recording that it was just seen.
+func Touch(repo Toucher, uuid uuid.UUID) (Entity, error) {
+	return repo.Touch(uuid)
+}
+
+// Revoke revokes a session, given it belongs to the user.
+func Revoke(repo Revoker, user, session uuid.UUID) error {
+	return repo.Revoke(user, session)
+}
+
+// Delete revokes the session, deleting a session that does not exist
+// is not an error.
+func Delete(repo Deleter, uuid uuid.UUID) error {
+	return repo.Delete(uuid)
+}
+
+// DeleteByUser revokes every session of the user.
+func DeleteByUser(repo DeleterByUser, user uuid.UUID) error {
+	return repo.DeleteByUser(user)
+}
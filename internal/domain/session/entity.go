@@ -11,12 +11,16 @@ import (
 const _MaxMaxAge = 7 * 24 * time.Hour
 
 type Session struct {
-	uuid    uuid.UUID
-	user    uuid.UUID
-	expires time.Time
+	uuid      uuid.UUID
+	user      uuid.UUID
+	userAgent string
+	address   string
+	created   time.Time
+	lastSeen  time.Time
+	expires   time.Time
 }
 
-func New(user uuid.UUID, maxAge time.Duration) (Session, error) {
+func New(user uuid.UUID, maxAge time.Duration, userAgent, address string) (Session, error) {
 	session := Session{}
 
 	err := errors.Join(
@@ -28,12 +32,20 @@ func New(user uuid.UUID, maxAge time.Duration) (Session, error) {
 	}
 
 	session.uuid = uuid.NewUUIDv7()
+	session.userAgent = userAgent
+	session.address = address
+	session.created = time.Now()
+	session.lastSeen = session.created
 	return session, nil
 }
 
-func (s *Session) UUID() uuid.UUID    { return s.uuid }
-func (s *Session) User() uuid.UUID    { return s.user }
-func (s *Session) Expires() time.Time { return s.expires }
+func (s *Session) UUID() uuid.UUID     { return s.uuid }
+func (s *Session) User() uuid.UUID     { return s.user }
+func (s *Session) UserAgent() string   { return s.userAgent }
+func (s *Session) Address() string     { return s.address }
+func (s *Session) Created() time.Time  { return s.created }
+func (s *Session) LastSeen() time.Time { return s.lastSeen }
+func (s *Session) Expires() time.Time  { return s.expires }
 
 func (s *Session) setUser(uuid uuid.UUID) error {
 	s.user = uuid
@@ -48,3 +60,8 @@ func (s *Session) SetMaxAge(maxAge time.Duration) error {
 	s.expires = time.Now().Add(maxAge)
 	return nil
 }
+
+// Touch records that the session was seen at the current time.
+func (s *Session) Touch() {
+	s.lastSeen = time.Now()
+}
@@ -0,0 +1,57 @@
+package session
+
+import (
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+)
+
+// Policy bounds the lifetime of sessions. A session expires once it
+// has been idle for Idle, or once Absolute has passed since it was
+// created, whichever comes first. A session is only renewed if that
+// pushes its expiry by at least Renewal, so not every request costs a
+// write to the repository.
+type Policy struct {
+	Idle     time.Duration
+	Absolute time.Duration
+	Renewal  time.Duration
+}
+
+// DefaultPolicy returns the policy used when none is given.
+func DefaultPolicy() Policy {
+	return Policy{
+		Idle:     _MaxAge,
+		Absolute: 12 * time.Hour,
+		Renewal:  time.Minute,
+	}
+}
+
+// Validate reports whether the policy is coherent: every duration
+// must be positive, the idle timeout cannot exceed the absolute one,
+// neither can last longer than a session is allowed to, and renewals
+// must be throttled for less than the idle timeout.
+func (p Policy) Validate() error {
+	switch {
+	case p.Idle <= 0 || p.Absolute <= 0 || p.Renewal <= 0:
+		return xerrors.ErrSessionPolicy.New("timeouts must be positive")
+	case p.Idle > p.Absolute:
+		return xerrors.ErrSessionPolicy.New("idle timeout must not exceed the absolute timeout")
+	case p.Absolute > _MaxMaxAge:
+		return xerrors.ErrSessionTooLong.New(_MaxMaxAge)
+	case p.Renewal >= p.Idle:
+		return xerrors.ErrSessionPolicy.New("renewal interval must be shorter than the idle timeout")
+	}
+
+	return nil
+}
+
+// expiry returns when a session created at created and renewed at
+// now should expire, and whether that is the absolute limit.
+func (p Policy) expiry(created, now time.Time) (time.Time, bool) {
+	expires := now.Add(p.Idle)
+	if limit := created.Add(p.Absolute); !expires.Before(limit) {
+		return limit, true
+	}
+
+	return expires, false
+}
@@ -0,0 +1,101 @@
+package session_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/alan-b-lima/almodon/internal/domain/session"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+type stub struct {
+	entity  Entity
+	updates int
+}
+
+func (s *stub) Get(uuid.UUID) (Entity, error) { return s.entity, nil }
+
+func (s *stub) Update(_ uuid.UUID, maxAge time.Duration) (Entity, error) {
+	s.updates++
+	s.entity.Expires = time.Now().Add(maxAge)
+	return s.entity, nil
+}
+
+var policy = Policy{
+	Idle:     10 * time.Minute,
+	Absolute: time.Hour,
+	Renewal:  time.Minute,
+}
+
+func TestRenewSlidesExpiry(t *testing.T) {
+	now := time.Now()
+	repo := &stub{entity: Entity{Created: now.Add(-5 * time.Minute), Expires: now.Add(5 * time.Minute)}}
+
+	s, renewed, err := Renew(repo, policy, uuid.UUID{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !renewed {
+		t.Fatal("session should have been renewed")
+	}
+
+	if s.Expires.Before(now.Add(policy.Idle)) {
+		t.Errorf("expected the session to expire after %v, got %v", now.Add(policy.Idle), s.Expires)
+	}
+}
+
+func TestRenewIsThrottled(t *testing.T) {
+	now := time.Now()
+	repo := &stub{entity: Entity{Created: now, Expires: now.Add(policy.Idle - policy.Renewal/2)}}
+
+	_, renewed, err := Renew(repo, policy, uuid.UUID{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renewed || repo.updates != 0 {
+		t.Error("session should not be renewed before the renewal interval")
+	}
+}
+
+func TestRenewHonorsAbsoluteLimit(t *testing.T) {
+	now := time.Now()
+	created := now.Add(-policy.Absolute + 30*time.Second)
+	repo := &stub{entity: Entity{Created: created, Expires: now.Add(10 * time.Second)}}
+
+	s, renewed, err := Renew(repo, policy, uuid.UUID{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !renewed {
+		t.Fatal("session should be renewed up to its absolute limit, even if by less than the renewal interval")
+	}
+
+	limit := created.Add(policy.Absolute)
+	if diff := s.Expires.Sub(limit).Abs(); diff > time.Second {
+		t.Errorf("expected the session to expire at %v, got %v", limit, s.Expires)
+	}
+
+	if _, renewed, _ := Renew(repo, policy, uuid.UUID{}); renewed {
+		t.Error("session should not be renewed past its absolute limit")
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	tests := []struct {
+		policy Policy
+		valid  bool
+	}{
+		{DefaultPolicy(), true},
+		{policy, true},
+		{Policy{Idle: 0, Absolute: time.Hour, Renewal: time.Minute}, false},
+		{Policy{Idle: 2 * time.Hour, Absolute: time.Hour, Renewal: time.Minute}, false},
+		{Policy{Idle: time.Hour, Absolute: 30 * 24 * time.Hour, Renewal: time.Minute}, false},
+		{Policy{Idle: time.Minute, Absolute: time.Hour, Renewal: time.Minute}, false},
+	}
+
+	for _, test := range tests {
+		if err := test.policy.Validate(); (err == nil) != test.valid {
+			t.Errorf("%+v: expected valid to be %v, got %v", test.policy, test.valid, err)
+		}
+	}
+}
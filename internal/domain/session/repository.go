@@ -8,8 +8,13 @@ import (
 
 type Repository interface {
 	Getter
+	ListerByUser
 	Creater
 	Updater
+	Toucher
+	Revoker
+	Deleter
+	DeleterByUser
 }
 
 type (
@@ -17,19 +22,43 @@ type (
 		Get(uuid.UUID) (Entity, error)
 	}
 
+	ListerByUser interface {
+		ListByUser(user uuid.UUID) ([]Entity, error)
+	}
+
 	Creater interface {
-		Create(uuid.UUID, time.Duration) (Entity, error)
+		Create(user uuid.UUID, maxAge time.Duration, userAgent, address string) (Entity, error)
 	}
 
 	Updater interface {
 		Update(uuid.UUID, time.Duration) (Entity, error)
 	}
+
+	Toucher interface {
+		Touch(uuid.UUID) (Entity, error)
+	}
+
+	Revoker interface {
+		Revoke(user, session uuid.UUID) error
+	}
+
+	Deleter interface {
+		Delete(uuid.UUID) error
+	}
+
+	DeleterByUser interface {
+		DeleteByUser(user uuid.UUID) error
+	}
 )
 
 type (
 	Entity struct {
-		UUID    uuid.UUID
-		User    uuid.UUID
-		Expires time.Time
+		UUID      uuid.UUID
+		User      uuid.UUID
+		UserAgent string
+		Address   string
+		Created   time.Time
+		LastSeen  time.Time
+		Expires   time.Time
 	}
 )
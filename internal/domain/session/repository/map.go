@@ -1,6 +1,7 @@
 package sessionrepo
 
 import (
+	"slices"
 	"sync"
 	"time"
 
@@ -10,9 +11,13 @@ import (
 	"github.com/alan-b-lima/almodon/pkg/uuid"
 )
 
+// _MaxSessionsPerUser bounds the sessions of a single user, creating
+// one more revokes the least recently seen.
+const _MaxSessionsPerUser = 16
+
 type Map struct {
 	uuidIndex   map[uuid.UUID]int
-	userIndex   map[uuid.UUID]int
+	userIndex   map[uuid.UUID][]uuid.UUID
 	expiresHeap sleepqueue
 
 	repo []session.Session
@@ -22,7 +27,7 @@ type Map struct {
 func NewMap() session.Repository {
 	repo := Map{
 		uuidIndex: make(map[uuid.UUID]int),
-		userIndex: make(map[uuid.UUID]int),
+		userIndex: make(map[uuid.UUID][]uuid.UUID),
 		expiresHeap: sleepqueue{
 			new:    make(chan ess, 32),
 			update: make(chan ess, 32),
@@ -44,32 +49,52 @@ func (m *Map) Get(uuid uuid.UUID) (session.Entity, error) {
 		return session.Entity{}, xerrors.ErrSessionNotFound
 	}
 
-	s := m.repo[index]
+	s := &m.repo[index]
 	if time.Now().After(s.Expires()) {
-		m.delete(s.UUID())
 		return session.Entity{}, xerrors.ErrSessionNotFound
 	}
 
 	var res session.Entity
-	transform(&res, &m.repo[index])
+	transform(&res, s)
 	return res, nil
 }
 
-func (m *Map) Create(user uuid.UUID, maxAge time.Duration) (session.Entity, error) {
-	m.mu.Lock()
+func (m *Map) ListByUser(user uuid.UUID) ([]session.Entity, error) {
+	defer m.mu.RUnlock()
+	m.mu.RLock()
+
+	now := time.Now()
+
+	res := make([]session.Entity, 0, len(m.userIndex[user]))
+	for _, uuid := range m.userIndex[user] {
+		s := &m.repo[m.uuidIndex[uuid]]
+		if now.After(s.Expires()) {
+			continue
+		}
 
-	if index, in := m.userIndex[user]; in {
-		s := m.repo[index]
-		m.delete(s.UUID())
+		var e session.Entity
+		transform(&e, s)
+		res = append(res, e)
 	}
 
-	s, err := session.New(user, maxAge)
+	return res, nil
+}
+
+func (m *Map) Create(user uuid.UUID, maxAge time.Duration, userAgent, address string) (session.Entity, error) {
+	m.mu.Lock()
+
+	s, err := session.New(user, maxAge, userAgent, address)
 	if err != nil {
+		m.mu.Unlock()
 		return session.Entity{}, err
 	}
 
+	if sessions := m.userIndex[user]; len(sessions) >= _MaxSessionsPerUser {
+		m.delete(m.leastRecentlySeen(sessions))
+	}
+
 	m.uuidIndex[s.UUID()] = len(m.repo)
-	m.userIndex[s.User()] = len(m.repo)
+	m.userIndex[s.User()] = append(m.userIndex[s.User()], s.UUID())
 	m.repo = append(m.repo, s)
 
 	// unlock before channel send to avoid blocking resources
@@ -87,38 +112,97 @@ func (m *Map) Update(uuid uuid.UUID, maxAge time.Duration) (session.Entity, erro
 
 	index, in := m.uuidIndex[uuid]
 	if !in {
+		m.mu.Unlock()
 		return session.Entity{}, xerrors.ErrSessionNotFound
 	}
 
 	s := &m.repo[index]
+	if time.Now().After(s.Expires()) {
+		m.mu.Unlock()
+		return session.Entity{}, xerrors.ErrSessionNotFound
+	}
 
 	if err := s.SetMaxAge(maxAge); err != nil {
+		m.mu.Unlock()
 		return session.Entity{}, err
 	}
+	s.Touch()
+
+	var res session.Entity
+	transform(&res, s)
 
 	// unlock before channel send to avoid blocking resources
 	m.mu.Unlock()
 
-	m.expiresHeap.update <- ess{s.UUID(), s.Expires()}
+	m.expiresHeap.update <- ess{res.UUID, res.Expires}
+
+	return res, nil
+}
+
+func (m *Map) Touch(uuid uuid.UUID) (session.Entity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	index, in := m.uuidIndex[uuid]
+	if !in {
+		return session.Entity{}, xerrors.ErrSessionNotFound
+	}
+
+	s := &m.repo[index]
+	if time.Now().After(s.Expires()) {
+		return session.Entity{}, xerrors.ErrSessionNotFound
+	}
+	s.Touch()
 
 	var res session.Entity
 	transform(&res, s)
 	return res, nil
 }
 
+func (m *Map) Revoke(user, uuid uuid.UUID) error {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	index, in := m.uuidIndex[uuid]
+	if !in || m.repo[index].User() != user {
+		return xerrors.ErrSessionNotFound
+	}
+
+	return m.delete(uuid)
+}
+
+func (m *Map) Delete(uuid uuid.UUID) error {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	return m.delete(uuid)
+}
+
+func (m *Map) DeleteByUser(user uuid.UUID) error {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	for _, uuid := range slices.Clone(m.userIndex[user]) {
+		m.delete(uuid)
+	}
+
+	return nil
+}
+
+func (m *Map) leastRecentlySeen(sessions []uuid.UUID) uuid.UUID {
+	return slices.MinFunc(sessions, func(u0, u1 uuid.UUID) int {
+		s0, s1 := &m.repo[m.uuidIndex[u0]], &m.repo[m.uuidIndex[u1]]
+		return s0.LastSeen().Compare(s1.LastSeen())
+	})
+}
+
 func (m *Map) delete(uuid uuid.UUID) error {
 	index, in := m.uuidIndex[uuid]
 	if !in {
 		return nil
 	}
 
-	s := &m.repo[index]
-
-	delete(m.uuidIndex, s.UUID())
-	delete(m.userIndex, s.User())
-
-	m.repo[index] = m.repo[len(m.repo)-1]
-	m.repo = m.repo[:len(m.repo)-1]
+	m.remove(index)
 	return nil
 }
 
@@ -133,17 +217,42 @@ func (m *Map) tryDelete(uuid uuid.UUID) bool {
 		return false
 	}
 
+	m.remove(index)
+	return true
+}
+
+// remove removes the session at index by swapping it with the last
+// one, whose indexes are updated accordingly.
+func (m *Map) remove(index int) {
+	s := &m.repo[index]
+
 	delete(m.uuidIndex, s.UUID())
-	delete(m.userIndex, s.User())
 
-	m.repo[index] = m.repo[len(m.repo)-1]
-	m.repo = m.repo[:len(m.repo)-1]
-	return true
+	sessions := slices.DeleteFunc(m.userIndex[s.User()], func(uuid uuid.UUID) bool {
+		return uuid == s.UUID()
+	})
+	if len(sessions) == 0 {
+		delete(m.userIndex, s.User())
+	} else {
+		m.userIndex[s.User()] = sessions
+	}
+
+	last := len(m.repo) - 1
+	if index != last {
+		m.repo[index] = m.repo[last]
+		m.uuidIndex[m.repo[index].UUID()] = index
+	}
+
+	m.repo = m.repo[:last]
 }
 
 func transform(r *session.Entity, s *session.Session) {
 	r.UUID = s.UUID()
 	r.User = s.User()
+	r.UserAgent = s.UserAgent()
+	r.Address = s.Address()
+	r.Created = s.Created()
+	r.LastSeen = s.LastSeen()
 	r.Expires = s.Expires()
 }
 
@@ -183,6 +292,9 @@ func flush(m *Map) {
 			nheap := heap.Make[ess](len(m.repo))
 			nheap.PushMany(ss...)
 
+			h.heap = nheap
+			garbage = 0
+
 		case <-after:
 			es := h.heap.Pop()
 
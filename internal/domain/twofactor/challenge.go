@@ -0,0 +1,49 @@
+package twofactor
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"time"
+
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+// Challenge is a login halfway through: the password was verified,
+// but the session is only issued once the second factor is. If the
+// user has yet to enroll, as their role requires, the challenge also
+// allows enrolling. Only the hash of the secret is kept.
+type Challenge struct {
+	hash    [32]byte
+	user    uuid.UUID
+	enroll  bool
+	expires time.Time
+}
+
+// NewChallenge creates a challenge for the user, it returns the
+// challenge and its secret.
+func NewChallenge(user uuid.UUID, enroll bool, maxAge time.Duration) (Challenge, string) {
+	var secret [32]byte
+	rand.Read(secret[:])
+
+	str := base64.RawURLEncoding.EncodeToString(secret[:])
+
+	c := Challenge{
+		hash:    HashChallenge(str),
+		user:    user,
+		enroll:  enroll,
+		expires: time.Now().Add(maxAge),
+	}
+	return c, str
+}
+
+// HashChallenge returns the hash under which the challenge of the given
+// secret is stored.
+func HashChallenge(secret string) [32]byte {
+	return sha256.Sum256([]byte(secret))
+}
+
+func (c *Challenge) Hash() [32]byte     { return c.hash }
+func (c *Challenge) User() uuid.UUID    { return c.user }
+func (c *Challenge) Enroll() bool       { return c.enroll }
+func (c *Challenge) Expires() time.Time { return c.expires }
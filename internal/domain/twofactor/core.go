@@ -0,0 +1,62 @@
+package twofactor
+
+import (
+	"time"
+
+	"github.com/alan-b-lima/almodon/pkg/errors"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+const _ChallengeMaxAge = 5 * time.Minute
+
+func Get(repo Getter, user uuid.UUID) (Entity, error) {
+	return repo.Get(user)
+}
+
+// IsEnrolled returns whether the user has a confirmed enrollment.
+func IsEnrolled(repo Getter, user uuid.UUID) (bool, error) {
+	res, err := repo.Get(user)
+	if err, ok := errors.AsType[*errors.Error](err); ok && err.Kind == errors.NotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return res.Confirmed, nil
+}
+
+// Enroll starts the enrollment of the user, replacing any pending
+// one. The account labels the secret in the user's app.
+func Enroll(repo Enroller, user uuid.UUID, account string) (EnrollEntity, error) {
+	return repo.Enroll(user, account)
+}
+
+// Confirm confirms the pending enrollment of the user, returning their
+// recovery codes.
+func Confirm(repo Confirmer, user uuid.UUID, code string) ([]string, error) {
+	return repo.Confirm(user, code)
+}
+
+// Verify checks a TOTP or recovery code of the user.
+func Verify(repo Verifier, user uuid.UUID, code string) error {
+	return repo.Verify(user, code)
+}
+
+// Delete removes the enrollment of the user, pending or not.
+func Delete(repo Deleter, user uuid.UUID) error {
+	return repo.Delete(user)
+}
+
+// CreateChallenge creates a login challenge for the user.
+func CreateChallenge(repo Challenger, user uuid.UUID, enroll bool) (ChallengeEntity, error) {
+	return repo.CreateChallenge(user, enroll, _ChallengeMaxAge)
+}
+
+func GetChallenge(repo Challenger, secret string) (ChallengeEntity, error) {
+	return repo.GetChallenge(secret)
+}
+
+func DeleteChallenge(repo Challenger, secret string) error {
+	return repo.DeleteChallenge(secret)
+}
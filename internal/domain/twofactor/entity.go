@@ -0,0 +1,133 @@
+package twofactor
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/totp"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+const (
+	_Issuer = "Almodon"
+
+	_Skew = 1
+
+	_RecoveryCodes      = 10
+	_RecoveryCodeLength = 10
+	_RecoveryAlphabet   = "0123456789abcdefghjkmnpqrstvwxyz" // Crockford's base32
+)
+
+// Enrollment is the TOTP second factor of a user. It is pending until
+// the user proves, with a first code, to have set up their app, only
+// then it is enforced and recovery codes are issued.
+type Enrollment struct {
+	user      uuid.UUID
+	secret    []byte
+	confirmed bool
+	lastStep  int64
+	recovery  [][32]byte
+	created   time.Time
+}
+
+// New creates a pending enrollment for the user, with a new secret.
+func New(user uuid.UUID) Enrollment {
+	return Enrollment{
+		user:    user,
+		secret:  totp.NewSecret(),
+		created: time.Now(),
+	}
+}
+
+func (e *Enrollment) User() uuid.UUID    { return e.user }
+func (e *Enrollment) Secret() []byte     { return e.secret }
+func (e *Enrollment) Confirmed() bool    { return e.confirmed }
+func (e *Enrollment) Created() time.Time { return e.created }
+func (e *Enrollment) RecoveryLeft() int  { return len(e.recovery) }
+
+// URI returns the provisioning URI of the enrollment, labeled with the
+// given account name.
+func (e *Enrollment) URI(account string) string {
+	return totp.URI(_Issuer, account, e.secret)
+}
+
+// Confirm confirms the pending enrollment given a valid code, and
+// returns its recovery codes, which are only ever available here.
+func (e *Enrollment) Confirm(code string, now time.Time) ([]string, error) {
+	if e.confirmed {
+		return nil, xerrors.ErrTwoFactorEnrolled
+	}
+
+	if err := e.verifyTOTP(code, now); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, _RecoveryCodes)
+	e.recovery = make([][32]byte, _RecoveryCodes)
+	for i := range codes {
+		codes[i] = newRecoveryCode()
+		e.recovery[i] = hashRecoveryCode(codes[i])
+	}
+
+	e.confirmed = true
+	return codes, nil
+}
+
+// Verify checks a code of a confirmed enrollment, be it a TOTP code or
+// one of the recovery codes, the latter being consumed. A TOTP code is
+// only accepted once.
+func (e *Enrollment) Verify(code string, now time.Time) error {
+	if !e.confirmed {
+		return xerrors.ErrTwoFactorNotEnrolled
+	}
+
+	code = strings.Join(strings.Fields(code), "")
+	if len(code) == totp.Digits {
+		return e.verifyTOTP(code, now)
+	}
+
+	hash := hashRecoveryCode(code)
+	for i, h := range e.recovery {
+		if h == hash {
+			e.recovery = slices.Delete(e.recovery, i, i+1)
+			return nil
+		}
+	}
+
+	return xerrors.ErrTwoFactorCodeInvalid
+}
+
+func (e *Enrollment) verifyTOTP(code string, now time.Time) error {
+	step, ok := totp.Verify(e.secret, strings.TrimSpace(code), now, _Skew)
+	if !ok || step <= e.lastStep {
+		return xerrors.ErrTwoFactorCodeInvalid
+	}
+
+	e.lastStep = step
+	return nil
+}
+
+func newRecoveryCode() string {
+	var buf [_RecoveryCodeLength]byte
+	rand.Read(buf[:])
+
+	var b strings.Builder
+	for i, c := range buf {
+		if i == _RecoveryCodeLength/2 {
+			b.WriteByte('-')
+		}
+		b.WriteByte(_RecoveryAlphabet[c%32])
+	}
+
+	return b.String()
+}
+
+// hashRecoveryCode hashes a recovery code, ignoring case and dashes.
+func hashRecoveryCode(code string) [32]byte {
+	code = strings.ToLower(strings.ReplaceAll(code, "-", ""))
+	return sha256.Sum256([]byte(code))
+}
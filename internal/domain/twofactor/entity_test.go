@@ -0,0 +1,46 @@
+package twofactor_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/alan-b-lima/almodon/internal/domain/twofactor"
+	"github.com/alan-b-lima/almodon/pkg/totp"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+func TestEnrollment(t *testing.T) {
+	now := time.Now()
+	e := New(uuid.NewUUIDv7())
+
+	if err := e.Verify(totp.Code(e.Secret(), now), now); err == nil {
+		t.Fatal("pending enrollment should not verify codes")
+	}
+
+	codes, err := e.Confirm(totp.Code(e.Secret(), now), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) == 0 || e.RecoveryLeft() != len(codes) {
+		t.Fatalf("expected recovery codes, got %v", codes)
+	}
+
+	if err := e.Verify(totp.Code(e.Secret(), now), now); err == nil {
+		t.Error("code should not be accepted twice")
+	}
+
+	next := now.Add(totp.Period)
+	if err := e.Verify(totp.Code(e.Secret(), next), next); err != nil {
+		t.Errorf("code of the next step should be accepted, got %v", err)
+	}
+
+	if err := e.Verify(" "+codes[0]+" ", now); err != nil {
+		t.Errorf("recovery code should be accepted, got %v", err)
+	}
+	if err := e.Verify(codes[0], now); err == nil {
+		t.Error("recovery code should not be accepted twice")
+	}
+	if e.RecoveryLeft() != len(codes)-1 {
+		t.Errorf("expected %d recovery codes left, got %d", len(codes)-1, e.RecoveryLeft())
+	}
+}
@@ -0,0 +1,25 @@
+package twofactor
+
+import (
+	"slices"
+
+	"github.com/alan-b-lima/almodon/internal/auth"
+)
+
+// Policy tells which roles must authenticate with a second factor,
+// for every other role it is optional.
+type Policy struct {
+	Roles []auth.Role
+}
+
+// DefaultPolicy returns the policy used when none is given, which
+// requires a second factor of the roles allowed to manage users.
+func DefaultPolicy() Policy {
+	return Policy{Roles: []auth.Role{auth.Chief, auth.Promoted}}
+}
+
+// Requires returns whether the role must authenticate with a second
+// factor.
+func (p Policy) Requires(role auth.Role) bool {
+	return slices.Contains(p.Roles, role)
+}
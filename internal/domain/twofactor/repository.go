@@ -0,0 +1,66 @@
+package twofactor
+
+import (
+	"time"
+
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+type Repository interface {
+	Getter
+	Enroller
+	Confirmer
+	Verifier
+	Deleter
+	Challenger
+}
+
+type (
+	Getter interface {
+		Get(user uuid.UUID) (Entity, error)
+	}
+
+	Enroller interface {
+		Enroll(user uuid.UUID, account string) (EnrollEntity, error)
+	}
+
+	Confirmer interface {
+		Confirm(user uuid.UUID, code string) ([]string, error)
+	}
+
+	Verifier interface {
+		Verify(user uuid.UUID, code string) error
+	}
+
+	Deleter interface {
+		Delete(user uuid.UUID) error
+	}
+
+	Challenger interface {
+		CreateChallenge(user uuid.UUID, enroll bool, maxAge time.Duration) (ChallengeEntity, error)
+		GetChallenge(secret string) (ChallengeEntity, error)
+		DeleteChallenge(secret string) error
+	}
+)
+
+type (
+	Entity struct {
+		User         uuid.UUID
+		Confirmed    bool
+		RecoveryLeft int
+		Created      time.Time
+	}
+
+	EnrollEntity struct {
+		User   uuid.UUID
+		Secret string
+		URI    string
+	}
+
+	ChallengeEntity struct {
+		Secret  string
+		User    uuid.UUID
+		Enroll  bool
+		Expires time.Time
+	}
+)
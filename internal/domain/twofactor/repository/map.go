@@ -0,0 +1,169 @@
+package twofactorrepo
+
+import (
+	"sync"
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/domain/twofactor"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/totp"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+type Map struct {
+	enrollments map[uuid.UUID]twofactor.Enrollment
+	challenges  map[[32]byte]twofactor.Challenge
+
+	mu sync.Mutex
+}
+
+func NewMap() twofactor.Repository {
+	repo := Map{
+		enrollments: make(map[uuid.UUID]twofactor.Enrollment),
+		challenges:  make(map[[32]byte]twofactor.Challenge),
+	}
+
+	return &repo
+}
+
+func (m *Map) Get(user uuid.UUID) (twofactor.Entity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	e, in := m.enrollments[user]
+	if !in {
+		return twofactor.Entity{}, xerrors.ErrTwoFactorNotEnrolled
+	}
+
+	return transform(&e), nil
+}
+
+func (m *Map) Enroll(user uuid.UUID, account string) (twofactor.EnrollEntity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	if e, in := m.enrollments[user]; in && e.Confirmed() {
+		return twofactor.EnrollEntity{}, xerrors.ErrTwoFactorEnrolled
+	}
+
+	e := twofactor.New(user)
+	m.enrollments[user] = e
+
+	res := twofactor.EnrollEntity{
+		User:   e.User(),
+		Secret: totp.Encode(e.Secret()),
+		URI:    e.URI(account),
+	}
+	return res, nil
+}
+
+func (m *Map) Confirm(user uuid.UUID, code string) ([]string, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	e, in := m.enrollments[user]
+	if !in {
+		return nil, xerrors.ErrTwoFactorNotEnrolled
+	}
+
+	codes, err := e.Confirm(code, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	m.enrollments[user] = e
+	return codes, nil
+}
+
+func (m *Map) Verify(user uuid.UUID, code string) error {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	e, in := m.enrollments[user]
+	if !in {
+		return xerrors.ErrTwoFactorNotEnrolled
+	}
+
+	if err := e.Verify(code, time.Now()); err != nil {
+		return err
+	}
+
+	m.enrollments[user] = e
+	return nil
+}
+
+func (m *Map) Delete(user uuid.UUID) error {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	if _, in := m.enrollments[user]; !in {
+		return xerrors.ErrTwoFactorNotEnrolled
+	}
+
+	delete(m.enrollments, user)
+	return nil
+}
+
+func (m *Map) CreateChallenge(user uuid.UUID, enroll bool, maxAge time.Duration) (twofactor.ChallengeEntity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	m.purge()
+
+	c, secret := twofactor.NewChallenge(user, enroll, maxAge)
+	m.challenges[c.Hash()] = c
+
+	res := twofactor.ChallengeEntity{
+		Secret:  secret,
+		User:    c.User(),
+		Enroll:  c.Enroll(),
+		Expires: c.Expires(),
+	}
+	return res, nil
+}
+
+func (m *Map) GetChallenge(secret string) (twofactor.ChallengeEntity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	c, in := m.challenges[twofactor.HashChallenge(secret)]
+	if !in || time.Now().After(c.Expires()) {
+		return twofactor.ChallengeEntity{}, xerrors.ErrAuthChallengeInvalid
+	}
+
+	res := twofactor.ChallengeEntity{
+		Secret:  secret,
+		User:    c.User(),
+		Enroll:  c.Enroll(),
+		Expires: c.Expires(),
+	}
+	return res, nil
+}
+
+func (m *Map) DeleteChallenge(secret string) error {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	delete(m.challenges, twofactor.HashChallenge(secret))
+	return nil
+}
+
+// purge removes expired challenges, challenges are few and short-lived,
+// so a linear sweep on creation suffices.
+func (m *Map) purge() {
+	now := time.Now()
+	for hash, c := range m.challenges {
+		if now.After(c.Expires()) {
+			delete(m.challenges, hash)
+		}
+	}
+}
+
+func transform(e *twofactor.Enrollment) twofactor.Entity {
+	return twofactor.Entity{
+		User:         e.User(),
+		Confirmed:    e.Confirmed(),
+		RecoveryLeft: e.RecoveryLeft(),
+		Created:      e.Created(),
+	}
+}
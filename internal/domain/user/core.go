@@ -1,65 +1,585 @@
 package user
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/domain/apitoken"
+	"github.com/alan-b-lima/almodon/internal/domain/recovery"
 	sessionpkg "github.com/alan-b-lima/almodon/internal/domain/session"
+	"github.com/alan-b-lima/almodon/internal/domain/twofactor"
+	"github.com/alan-b-lima/almodon/internal/notify"
 	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/errors"
 	"github.com/alan-b-lima/almodon/pkg/hash"
 	"github.com/alan-b-lima/almodon/pkg/opt"
 	"github.com/alan-b-lima/almodon/pkg/uuid"
 )
 
-func List(users Lister, offset, limit int) (Entities, error) {
-	return users.List(offset, limit)
+func List(users Lister, filter Filter, sort Sort, offset, limit int) (Entities, error) {
+	return users.List(filter, sort, offset, limit)
 }
 
 func Get(users Getter, uuid uuid.UUID) (Entity, error) {
 	return users.Get(uuid)
 }
 
-func GetBySIAPE(users GetterBySIAPE, siape int) (Entity, error) {
-	return users.GetBySIAPE(siape)
+func GetBySIAPE(users GetterByIdentity, siape int) (Entity, error) {
+	return users.GetByIdentity(SIAPEIdentity(siape))
 }
 
-func Create(users Creater, siape int, name, email, password string, role auth.Role) (Entity, error) {
-	return users.Create(siape, name, email, password, role)
+// Create creates the user, whose password must satisfy the policy.
+func Create(users Creater, policy PasswordPolicy, ids Identities, name, email, password string, role auth.Role) (Entity, error) {
+	if err := policy.Check(password, ids, name, email); err != nil {
+		return Entity{}, err
+	}
+
+	return users.Create(ids, name, email, password, role)
+}
+
+func UpdateProfile(users Patcher, uuid uuid.UUID, name, email opt.Opt[string]) (Entity, error) {
+	return users.Patch(uuid, name, email, opt.None[string](), opt.None[auth.Role]())
+}
+
+func ChangePassword(users interface {
+	Getter
+	Patcher
+}, policy PasswordPolicy, uuid uuid.UUID, current, password string) (Entity, error) {
+	res, err := users.Get(uuid)
+	if err != nil {
+		return Entity{}, err
+	}
+
+	if !hash.Compare(res.Password, []byte(current)) {
+		return Entity{}, xerrors.ErrIncorrectPassword
+	}
+
+	if err := policy.Check(password, res.Identities, res.Name, res.Email); err != nil {
+		return Entity{}, err
+	}
+
+	return users.Patch(uuid, opt.None[string](), opt.None[string](), opt.Some(password), opt.None[auth.Role]())
+}
+
+func ResetPassword(users interface {
+	Getter
+	Patcher
+}, policy PasswordPolicy, uuid uuid.UUID, password string) (Entity, error) {
+	res, err := users.Get(uuid)
+	if err != nil {
+		return Entity{}, err
+	}
+
+	if err := policy.Check(password, res.Identities, res.Name, res.Email); err != nil {
+		return Entity{}, err
+	}
+
+	return users.Patch(uuid, opt.None[string](), opt.None[string](), opt.Some(password), opt.None[auth.Role]())
+}
+
+func ChangeRole(users Patcher, uuid uuid.UUID, role auth.Role) (Entity, error) {
+	return users.Patch(uuid, opt.None[string](), opt.None[string](), opt.None[string](), opt.Some(role))
+}
+
+// Deactivate keeps the user from logging in, revoking their sessions.
+// Their records are kept, and so is what refers to them.
+func Deactivate(users Activator, sessions sessionpkg.DeleterByUser, uuid uuid.UUID) error {
+	if _, err := users.SetActive(uuid, false); err != nil {
+		return err
+	}
+
+	return sessionpkg.DeleteByUser(sessions, uuid)
 }
 
-func Patch(users Patcher, uuid uuid.UUID, name, email, password opt.Opt[string], role opt.Opt[auth.Role]) (Entity, error) {
-	return users.Patch(uuid, name, email, password, role)
+func Reactivate(users Activator, uuid uuid.UUID) (Entity, error) {
+	return users.SetActive(uuid, true)
 }
 
-func Delete(users Deleter, uuid uuid.UUID) error {
-	return users.Delete(uuid)
+// Purge removes the user for good, along with their credentials,
+// recovery tokens included. Only deactivated users are purged, so that
+// it is never the first step.
+func Purge(users interface {
+	Getter
+	Deleter
+}, sessions sessionpkg.DeleterByUser, tokens apitoken.DeleterByUser, recoveries recovery.DeleterByUser, factors twofactor.Deleter, uuid uuid.UUID) error {
+	res, err := users.Get(uuid)
+	if err != nil {
+		return err
+	}
+
+	if res.Active {
+		return xerrors.ErrUserActive
+	}
+
+	if err := users.Delete(uuid); err != nil {
+		return err
+	}
+
+	// users are not always enrolled in two-factor authentication
+	errfactor := twofactor.Delete(factors, uuid)
+	if isNotFound(errfactor) {
+		errfactor = nil
+	}
+
+	return errors.Join(
+		sessionpkg.DeleteByUser(sessions, uuid),
+		apitoken.DeleteByUser(tokens, uuid),
+		recovery.DeleteByUser(recoveries, uuid),
+		errfactor,
+	)
 }
 
-func Authenticate(users GetterBySIAPE, sessions sessionpkg.Creater, siape int, password string) (AuthEntity, error) {
-	res, err := users.GetBySIAPE(siape)
+// Authenticate verifies the password of the user identified by the
+// login, any of their identities, and issues a session. The password
+// is verified by the provider, if any, whose accounts are provisioned
+// as users on their first login, and by the local password of the
+// user if the provider has no account for the login or cannot be
+// reached. If the user is enrolled in two-factor authentication, or
+// their role requires them to be, a challenge is issued instead, and
+// the session only comes out of [AuthenticateTwoFactor].
+func Authenticate(users interface {
+	GetterByIdentity
+	Creater
+	Rehasher
+}, sessions sessionpkg.Creater, factors interface {
+	twofactor.Getter
+	twofactor.Challenger
+}, guard Guard, provider Provider, policy sessionpkg.Policy, required twofactor.Policy, login, password, addr, userAgent string) (AuthEntity, error) {
+	id, err := ParseIdentity(login)
+	if err != nil {
+		return AuthEntity{}, err
+	}
+
+	res, errres := users.GetByIdentity(id)
+	if err, ok := errors.AsType[*errors.Error](errres); errres != nil && (!ok || err.Kind != errors.NotFound) {
+		return AuthEntity{}, errres
+	}
+
+	found := errres == nil
+
+	account := id.String()
+	if found {
+		account = res.UUID.String()
+	}
+
+	if err := guard.Check(account, addr); err != nil {
+		return AuthEntity{}, err
+	}
+
+	ext, decided, err := verifyExternal(users, provider, id, res, found, password)
 	if err != nil {
+		if err, ok := errors.AsType[*errors.Error](err); ok && err.Kind == errors.Unauthorized {
+			if err := guard.Fail(account, addr); err != nil {
+				return AuthEntity{}, err
+			}
+		}
+
 		return AuthEntity{}, err
 	}
 
-	if !hash.Compare(res.Password[:], []byte(password)) {
+	switch {
+	case decided:
+		res = ext
+
+	case !found:
+		if err := guard.Fail(account, addr); err != nil {
+			return AuthEntity{}, err
+		}
+
+		return AuthEntity{}, errres
+
+	case !hash.Compare(res.Password, []byte(password)):
+		if err := guard.Fail(account, addr); err != nil {
+			return AuthEntity{}, err
+		}
+
 		return AuthEntity{}, xerrors.ErrIncorrectPassword
 	}
 
-	s, err := sessions.Create(res.UUID, 10*time.Minute)
+	// told only to whoever knows the password, so as not to disclose
+	// the state of users
+	if !res.Active {
+		return AuthEntity{}, xerrors.ErrUserDeactivated
+	}
+
+	// hashes of earlier algorithms are replaced while the password is
+	// at hand, failing to is not worth failing the login, as the old
+	// hash still verifies and the next login tries again
+	if !decided && hash.NeedsRehash(res.Password) {
+		users.Rehash(res.UUID, password)
+	}
+
+	ares, err := logIn(sessions, factors, policy, required, &res, addr, userAgent)
+	if err != nil {
+		return AuthEntity{}, err
+	}
+
+	// failures are not forgiven until the second factor is verified,
+	// or else knowing the password would allow guessing codes forever
+	if ares.Challenge == "" {
+		guard.Succeed(account)
+	}
+
+	return ares, nil
+}
+
+// BeginSSO starts a login through the identity provider, returning
+// where the user is sent to log in.
+func BeginSSO(sso SSO) (url, state string, err error) {
+	if sso == nil {
+		return "", "", xerrors.ErrSSODisabled
+	}
+
+	return sso.Begin()
+}
+
+// FinishSSO completes a login through the identity provider and issues
+// a session for the user the account maps to, by SIAPE or else by
+// email. Accounts are not provisioned, only existing users log in this
+// way. Two-factor authentication is enforced as for passwords.
+func FinishSSO(users interface {
+	GetterByIdentity
+	GetterByEmail
+}, sessions sessionpkg.Creater, factors interface {
+	twofactor.Getter
+	twofactor.Challenger
+}, sso SSO, policy sessionpkg.Policy, required twofactor.Policy, state, code, addr, userAgent string) (AuthEntity, error) {
+	if sso == nil {
+		return AuthEntity{}, xerrors.ErrSSODisabled
+	}
+
+	acc, err := sso.Finish(state, code)
+	if err != nil {
+		return AuthEntity{}, err
+	}
+
+	res, err := mapAccount(users, &acc)
+	if err != nil {
+		return AuthEntity{}, err
+	}
+
+	if !res.Active {
+		return AuthEntity{}, xerrors.ErrUserDeactivated
+	}
+
+	return logIn(sessions, factors, policy, required, &res, addr, userAgent)
+}
+
+func mapAccount(users interface {
+	GetterByIdentity
+	GetterByEmail
+}, acc *Account) (Entity, error) {
+	if acc.SIAPE != 0 {
+		res, err := users.GetByIdentity(SIAPEIdentity(acc.SIAPE))
+		if !isNotFound(err) {
+			return res, err
+		}
+	}
+
+	if acc.Email != "" {
+		res, err := users.GetByEmail(acc.Email)
+		if !isNotFound(err) {
+			return res, err
+		}
+	}
+
+	return Entity{}, xerrors.ErrSSONoUser
+}
+
+func isNotFound(err error) bool {
+	e, ok := errors.AsType[*errors.Error](err)
+	return ok && e.Kind == errors.NotFound
+}
+
+// logIn issues the session of the authenticated user, or a challenge
+// if the user is enrolled in two-factor authentication, or their role
+// requires them to be.
+func logIn(sessions sessionpkg.Creater, factors interface {
+	twofactor.Getter
+	twofactor.Challenger
+}, policy sessionpkg.Policy, required twofactor.Policy, res *Entity, addr, userAgent string) (AuthEntity, error) {
+	enrolled, err := twofactor.IsEnrolled(factors, res.UUID)
+	if err != nil {
+		return AuthEntity{}, err
+	}
+
+	if enrolled || required.Requires(res.Role) {
+		c, err := twofactor.CreateChallenge(factors, res.UUID, !enrolled)
+		if err != nil {
+			return AuthEntity{}, err
+		}
+
+		ares := AuthEntity{
+			Expires:   c.Expires,
+			Challenge: c.Secret,
+			Enroll:    c.Enroll,
+		}
+		return ares, nil
+	}
+
+	return startSession(sessions, policy, res.UUID, addr, userAgent, nil)
+}
+
+// AuthenticateTwoFactor verifies the code of a login challenge and
+// issues the session. If the challenge was for enrolling, the code
+// confirms the enrollment, whose recovery codes are returned along
+// with the session.
+func AuthenticateTwoFactor(users Getter, sessions sessionpkg.Creater, factors interface {
+	twofactor.Challenger
+	twofactor.Confirmer
+	twofactor.Verifier
+}, guard Guard, policy sessionpkg.Policy, challenge, code, addr, userAgent string) (AuthEntity, error) {
+	c, err := twofactor.GetChallenge(factors, challenge)
+	if err != nil {
+		return AuthEntity{}, err
+	}
+
+	res, err := users.Get(c.User)
+	if err != nil {
+		return AuthEntity{}, xerrors.ErrAuthChallengeInvalid
+	}
+
+	if !res.Active {
+		return AuthEntity{}, xerrors.ErrUserDeactivated
+	}
+
+	account := res.UUID.String()
+	if err := guard.Check(account, addr); err != nil {
+		return AuthEntity{}, err
+	}
+
+	var codes []string
+	if c.Enroll {
+		codes, err = twofactor.Confirm(factors, c.User, code)
+	} else {
+		err = twofactor.Verify(factors, c.User, code)
+	}
+	if err != nil {
+		if err, ok := errors.AsType[*errors.Error](err); ok && err.Kind == errors.Unauthorized {
+			if err := guard.Fail(account, addr); err != nil {
+				return AuthEntity{}, err
+			}
+		}
+
+		return AuthEntity{}, err
+	}
+
+	guard.Succeed(account)
+	if err := twofactor.DeleteChallenge(factors, challenge); err != nil {
+		return AuthEntity{}, err
+	}
+
+	return startSession(sessions, policy, res.UUID, addr, userAgent, codes)
+}
+
+// EnrollByChallenge starts the enrollment of the user of a login
+// challenge, which must have been issued for enrolling.
+func EnrollByChallenge(users Getter, factors interface {
+	twofactor.Challenger
+	twofactor.Enroller
+}, challenge string) (twofactor.EnrollEntity, error) {
+	c, err := twofactor.GetChallenge(factors, challenge)
+	if err != nil {
+		return twofactor.EnrollEntity{}, err
+	}
+
+	if !c.Enroll {
+		return twofactor.EnrollEntity{}, xerrors.ErrAuthChallengeEnroll
+	}
+
+	return EnrollTwoFactor(users, factors, c.User)
+}
+
+func startSession(sessions sessionpkg.Creater, policy sessionpkg.Policy, user uuid.UUID, addr, userAgent string, codes []string) (AuthEntity, error) {
+	s, err := sessionpkg.CreateWithMaxAge(sessions, user, policy.Idle, userAgent, addr)
 	if err != nil {
 		return AuthEntity{}, err
 	}
 
 	ares := AuthEntity{
-		UUID:    s.UUID,
-		User:    res.UUID,
-		Expires: s.Expires,
+		UUID:          s.UUID,
+		User:          user,
+		Expires:       s.Expires,
+		RecoveryCodes: codes,
 	}
 	return ares, nil
 }
 
-func Actor(users Getter, sessions sessionpkg.Getter, session uuid.UUID) (auth.Actor, error) {
-	res, err := sessionpkg.Get(sessions, session)
+// EnrollTwoFactor starts the enrollment of the user, the secret is
+// labeled with their email in authenticator apps.
+func EnrollTwoFactor(users Getter, factors twofactor.Enroller, uuid uuid.UUID) (twofactor.EnrollEntity, error) {
+	res, err := users.Get(uuid)
+	if err != nil {
+		return twofactor.EnrollEntity{}, err
+	}
+
+	return twofactor.Enroll(factors, res.UUID, res.Email)
+}
+
+// DisableTwoFactor removes the enrollment of the user, given a valid
+// code, as long as their role does not require it.
+func DisableTwoFactor(users Getter, factors interface {
+	twofactor.Verifier
+	twofactor.Deleter
+}, required twofactor.Policy, uuid uuid.UUID, code string) error {
+	res, err := users.Get(uuid)
+	if err != nil {
+		return err
+	}
+
+	if required.Requires(res.Role) {
+		return xerrors.ErrTwoFactorRequired
+	}
+
+	if err := twofactor.Verify(factors, res.UUID, code); err != nil {
+		return err
+	}
+
+	return twofactor.Delete(factors, res.UUID)
+}
+
+// Unlock lifts the lockout of the user, caused by failed attempts to
+// authenticate.
+func Unlock(users Getter, guard Guard, uuid uuid.UUID) error {
+	res, err := users.Get(uuid)
+	if err != nil {
+		return err
+	}
+
+	guard.Unlock(res.UUID.String())
+	return nil
+}
+
+// ForgotPassword creates a recovery token for the user identified by
+// the login and delivers it through the notifier. Unknown identities,
+// deactivated users, and users sent too many tokens lately, as told by
+// the guard, are silently ignored, so that the existence of users is
+// not disclosed.
+func ForgotPassword(users GetterByIdentity, tokens recovery.Creater, notifier notify.Notifier, guard Guard, login string) error {
+	id, err := ParseIdentity(login)
+	if err != nil {
+		return err
+	}
+
+	res, err := users.GetByIdentity(id)
+	if err, ok := errors.AsType[*errors.Error](err); ok && err.Kind == errors.NotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !res.Active || !guard.AllowRecovery(res.Email) {
+		return nil
+	}
+
+	return notifyRecovery(tokens, notifier, &res, 0, "Almodon: redefinição de senha",
+		"Olá, %[1]s.\n\nUse o código a seguir para redefinir sua senha, ele é válido até %[2]s:\n\n%[3]s\n\nSe você não solicitou a redefinição, ignore esta mensagem.\n")
+}
+
+// notifyRecovery creates a recovery token for the user, lasting the
+// default if maxAge is zero, and delivers it. The body is formatted
+// with the name of the user, when the token expires and its secret.
+func notifyRecovery(tokens recovery.Creater, notifier notify.Notifier, res *Entity, maxAge time.Duration, subject, body string) error {
+	var (
+		tres recovery.CreateEntity
+		err  error
+	)
+	if maxAge == 0 {
+		tres, err = recovery.Create(tokens, res.UUID)
+	} else {
+		tres, err = recovery.CreateWithMaxAge(tokens, res.UUID, maxAge)
+	}
+	if err != nil {
+		return err
+	}
+
+	msg := notify.Message{
+		To:      res.Email,
+		Subject: subject,
+		Body:    fmt.Sprintf(body, res.Name, tres.Expires.Format("02/01/2006 15:04"), tres.Secret),
+	}
+
+	if err := notifier.Notify(msg); err != nil {
+		return xerrors.ErrRecoveryNotify.New(err)
+	}
+
+	return nil
+}
+
+// RecoverPassword sets a new password for the owner of the recovery
+// token, returned, and revokes all of their sessions. The password is
+// checked against the policy before the token is consumed, so a
+// rejected password does not waste the token. Tokens of deactivated
+// users are refused as invalid, even if issued before deactivation.
+func RecoverPassword(users interface {
+	Getter
+	Patcher
+}, tokens interface {
+	recovery.Getter
+	recovery.Consumer
+}, sessions sessionpkg.DeleterByUser, policy PasswordPolicy, token, password string) (Entity, error) {
+	tres, err := recovery.Get(tokens, token)
+	if err != nil {
+		return Entity{}, err
+	}
+
+	res, err := users.Get(tres.User)
+	if err != nil {
+		return Entity{}, err
+	}
+
+	if !res.Active {
+		return Entity{}, xerrors.ErrRecoveryTokenInvalid
+	}
+
+	if err := policy.Check(password, res.Identities, res.Name, res.Email); err != nil {
+		return Entity{}, err
+	}
+
+	tres, err = recovery.Consume(tokens, token)
+	if err != nil {
+		return Entity{}, err
+	}
+
+	res, err = users.Patch(tres.User, opt.None[string](), opt.None[string](), opt.Some(password), opt.None[auth.Role]())
+	if err != nil {
+		return Entity{}, err
+	}
+
+	if err := sessionpkg.DeleteByUser(sessions, tres.User); err != nil {
+		return Entity{}, err
+	}
+
+	return res, nil
+}
+
+// ActorByToken returns the actor on whose behalf the API token of the
+// given secret acts, as long as the token allows the scope.
+func ActorByToken(users Getter, tokens apitoken.Resolver, secret string, scope apitoken.Scope) (auth.Actor, error) {
+	res, err := apitoken.Resolve(tokens, secret)
+	if err != nil {
+		return auth.NewUnlogged(), err
+	}
+
+	if !res.Scope.Allows(scope) {
+		return auth.NewUnlogged(), xerrors.ErrTokenScope
+	}
+
+	ures, err := users.Get(res.User)
+	if err != nil || !ures.Active {
+		return auth.NewUnlogged(), xerrors.ErrTokenInvalid
+	}
+
+	return auth.NewLogged(
+		ures.UUID,
+		ures.Role,
+	), nil
+}
+
+func Actor(users Getter, sessions sessionpkg.Toucher, session uuid.UUID) (auth.Actor, error) {
+	res, err := sessionpkg.Touch(sessions, session)
 	if err != nil {
 		return auth.NewUnlogged(), xerrors.ErrUnauthenticatedUser.New(err)
 	}
@@ -69,6 +589,10 @@ func Actor(users Getter, sessions sessionpkg.Getter, session uuid.UUID) (auth.Ac
 		return auth.NewUnlogged(), xerrors.ErrUnauthenticatedUser.New(err)
 	}
 
+	if !ures.Active {
+		return auth.NewUnlogged(), xerrors.ErrUnauthenticatedUser.New(xerrors.ErrUserDeactivated)
+	}
+
 	return auth.NewLogged(
 		ures.UUID,
 		ures.Role,
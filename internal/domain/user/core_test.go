@@ -0,0 +1,151 @@
+package user_test
+
+import (
+	"testing"
+	"time"
+
+	apitokenrepo "github.com/alan-b-lima/almodon/internal/domain/apitoken/repository"
+	recoveryrepo "github.com/alan-b-lima/almodon/internal/domain/recovery/repository"
+	"github.com/alan-b-lima/almodon/internal/domain/session"
+	sessionrepo "github.com/alan-b-lima/almodon/internal/domain/session/repository"
+	"github.com/alan-b-lima/almodon/internal/domain/twofactor"
+	twofactorrepo "github.com/alan-b-lima/almodon/internal/domain/twofactor/repository"
+	. "github.com/alan-b-lima/almodon/internal/domain/user"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/hash"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// legacy stands for a repository whose users were hashed by BCrypt,
+// as in earlier versions, until rehashed.
+type legacy struct {
+	Repository
+	hash     []byte
+	rehashes int
+}
+
+func (l *legacy) GetByIdentity(id Identity) (Entity, error) {
+	res, err := l.Repository.GetByIdentity(id)
+	if err == nil && l.rehashes == 0 {
+		res.Password = l.hash
+	}
+
+	return res, err
+}
+
+func (l *legacy) Rehash(uuid uuid.UUID, password string) error {
+	l.rehashes++
+	return l.Repository.Rehash(uuid, password)
+}
+
+func TestAuthenticateRehash(t *testing.T) {
+	old, err := bcrypt.GenerateFromPassword([]byte("12345678"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &legacy{Repository: newRepo(t), hash: old}
+
+	login := func(password string) error {
+		_, err := Authenticate(repo, sessionrepo.NewMap(), twofactorrepo.NewMap(), NewThrottle(), nil, session.DefaultPolicy(), twofactor.Policy{}, "1", password, "127.0.0.1", "")
+		return err
+	}
+
+	if err := login("87654321"); err == nil {
+		t.Fatal("login should fail with a wrong password")
+	}
+	if repo.rehashes != 0 {
+		t.Error("failed logins should not rehash")
+	}
+
+	if err := login("12345678"); err != nil {
+		t.Fatal(err)
+	}
+	if repo.rehashes != 1 {
+		t.Fatalf("the BCrypt hash should have been rehashed once, got %d", repo.rehashes)
+	}
+
+	e, err := repo.GetByIdentity(SIAPEIdentity(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hash.NeedsRehash(e.Password) || !hash.Compare(e.Password, []byte("12345678")) {
+		t.Errorf("expected an Argon2id hash of the password, got %s", e.Password)
+	}
+
+	if err := login("12345678"); err != nil {
+		t.Fatal(err)
+	}
+	if repo.rehashes != 1 {
+		t.Error("current hashes should not be rehashed")
+	}
+}
+
+func TestForgotPasswordThrottled(t *testing.T) {
+	repo := newRepo(t)
+	guard := NewThrottle()
+	var in inbox
+
+	for range 5 {
+		if err := ForgotPassword(repo, recoveryrepo.NewMap(), &in, guard, "1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(in) != 3 {
+		t.Errorf("expected 3 messages before the address is throttled, got %d", len(in))
+	}
+}
+
+func TestRecoverPasswordInactive(t *testing.T) {
+	repo := newRepo(t)
+	tokens := recoveryrepo.NewMap()
+
+	e, err := repo.GetByIdentity(SIAPEIdentity(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tres, err := tokens.Create(e.UUID, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.SetActive(e.UUID, false); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = RecoverPassword(repo, tokens, sessionrepo.NewMap(), DefaultPasswordPolicy(), tres.Secret, "Correct-Horse-9")
+	if err != xerrors.ErrRecoveryTokenInvalid {
+		t.Errorf("deactivated users should not recover their password, got %v", err)
+	}
+}
+
+func TestPurgeRevokesRecovery(t *testing.T) {
+	repo := newRepo(t)
+	tokens := recoveryrepo.NewMap()
+
+	e, err := repo.GetByIdentity(SIAPEIdentity(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tres, err := tokens.Create(e.UUID, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.SetActive(e.UUID, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Purge(repo, sessionrepo.NewMap(), apitokenrepo.NewMap(), tokens, twofactorrepo.NewMap(), e.UUID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tokens.Consume(tres.Secret); err == nil {
+		t.Error("recovery tokens should not outlive the user")
+	}
+}
@@ -2,6 +2,7 @@ package user
 
 import (
 	"regexp"
+	"time"
 	"unicode/utf8"
 
 	"github.com/alan-b-lima/almodon/internal/auth"
@@ -13,14 +14,19 @@ import (
 
 type User struct {
 	uuid     uuid.UUID
-	siape    int
+	ids      Identities
 	name     string
 	email    string
-	password [60]byte
+	password []byte
 	role     auth.Role
+	created  time.Time
+
+	// deactivated users are kept, as history refers to them, but cannot
+	// log in until reactivated
+	active bool
 }
 
-func New(siape int, name, email, password string, role auth.Role) (User, error) {
+func New(ids Identities, name, email, password string, role auth.Role) (User, error) {
 	var u User
 
 	errpwd := u.SetPassword(password)
@@ -29,7 +35,7 @@ func New(siape int, name, email, password string, role auth.Role) (User, error)
 	}
 
 	err := errors.Join(
-		u.SetSIAPE(siape),
+		u.SetIdentities(ids),
 		u.SetName(name),
 		u.SetEmail(email),
 		errpwd,
@@ -40,25 +46,26 @@ func New(siape int, name, email, password string, role auth.Role) (User, error)
 	}
 
 	u.uuid = uuid.NewUUIDv7()
+	u.created = time.Now()
+	u.active = true
 	return u, nil
 }
 
-func (u *User) UUID() uuid.UUID    { return u.uuid }
-func (u *User) SIAPE() int         { return u.siape }
-func (u *User) Name() string       { return u.name }
-func (u *User) Email() string      { return u.email }
-func (u *User) Password() [60]byte { return u.password }
-func (u *User) Role() auth.Role    { return u.role }
-
-func (u *User) SetSIAPE(siape int) error          { return set(&u.siape, siape, ProcessSiape) }
-func (u *User) SetName(name string) error         { return set(&u.name, name, ProcessName) }
-func (u *User) SetEmail(email string) error       { return set(&u.email, email, ProcessEmail) }
-func (u *User) SetPassword(password string) error { return set(&u.password, password, ProcessPassword) }
-func (u *User) SetRole(role auth.Role) error      { return set(&u.role, role, ProcessRole) }
-
-func ProcessSiape(siape int) (int, error) {
-	return siape, nil
-}
+func (u *User) UUID() uuid.UUID        { return u.uuid }
+func (u *User) Identities() Identities { return u.ids }
+func (u *User) Name() string           { return u.name }
+func (u *User) Email() string          { return u.email }
+func (u *User) Password() []byte       { return u.password }
+func (u *User) Role() auth.Role        { return u.role }
+func (u *User) Created() time.Time     { return u.created }
+func (u *User) Active() bool           { return u.active }
+
+func (u *User) SetIdentities(ids Identities) error { return set(&u.ids, ids, ProcessIdentities) }
+func (u *User) SetName(name string) error          { return set(&u.name, name, ProcessName) }
+func (u *User) SetEmail(email string) error        { return set(&u.email, email, ProcessEmail) }
+func (u *User) SetPassword(password string) error  { return set(&u.password, password, ProcessPassword) }
+func (u *User) SetRole(role auth.Role) error       { return set(&u.role, role, ProcessRole) }
+func (u *User) SetActive(active bool)              { u.active = active }
 
 func ProcessName(name string) (string, error) {
 	if name == "" {
@@ -78,42 +85,67 @@ func ProcessEmail(email string) (string, error) {
 	return email, nil
 }
 
-func ProcessPassword(password string) ([60]byte, error) {
-	if len(password) < 8 {
-		return [60]byte{}, xerrors.ErrPasswordTooShort
+// ProcessPassword checks what any password must be and hashes it,
+// what users may choose is up to the [PasswordPolicy].
+func ProcessPassword(password string) ([]byte, error) {
+	if err := checkPassword(password); err != nil {
+		return nil, err
+	}
+
+	hash, err := hash.Hash([]byte(password))
+	if err != nil {
+		return nil, xerrors.ErrFailedToHashPassword.New(err)
+	}
+
+	return hash, nil
+}
+
+// checkPassword checks what any password must be, regardless of the
+// policy.
+func checkPassword(password string) error {
+	if password == "" {
+		return xerrors.ErrPasswordEmpty
 	}
 
-	if len(password) > 64 {
-		return [60]byte{}, xerrors.ErrPasswordTooLong
+	if len(password) > maxPasswordLength {
+		return xerrors.ErrPasswordTooLong.New(maxPasswordLength)
 	}
 
 	switch password[0] {
 	case ' ', '\t', '\n', '\r':
-		return [60]byte{}, xerrors.ErrPasswordLeadOrTrailWhitespace
+		return xerrors.ErrPasswordLeadOrTrailWhitespace
 	}
 
 	switch password[len(password)-1] {
 	case ' ', '\t', '\n', '\r':
-		return [60]byte{}, xerrors.ErrPasswordLeadOrTrailWhitespace
+		return xerrors.ErrPasswordLeadOrTrailWhitespace
 	}
 
 	for _, rune := range password {
 		if rune < ' ' || !utf8.ValidRune(rune) {
-			return [60]byte{}, xerrors.ErrPasswordIllegalCharacters
+			return xerrors.ErrPasswordIllegalCharacters
 		}
 	}
 
+	return nil
+}
+
+// Rehash hashes the password again, with the current algorithm and
+// parameters, without checking it, as it is meant for passwords that
+// already matched the hash of the user.
+func (u *User) Rehash(password string) error {
 	hash, err := hash.Hash([]byte(password))
 	if err != nil {
-		return [60]byte{}, xerrors.ErrFailedToHashPassword.New(err)
+		return xerrors.ErrFailedToHashPassword.New(err)
 	}
 
-	return hash, nil
+	u.password = hash
+	return nil
 }
 
 func ProcessRole(role auth.Role) (auth.Role, error) {
 	if !role.IsValid() {
-		return 0, nil
+		return 0, xerrors.ErrRoleInvalid
 	}
 
 	return role, nil
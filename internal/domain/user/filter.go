@@ -0,0 +1,144 @@
+package user
+
+import (
+	"cmp"
+	"strings"
+	"unicode"
+
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/opt"
+)
+
+// Filter selects the users of a listing, its zero value selects all
+// of them.
+type Filter struct {
+	Role   opt.Opt[auth.Role]
+	Active opt.Opt[bool]
+
+	// Search selects users whose name or email contains it, regardless
+	// of case and accents.
+	Search string
+}
+
+// Match returns whether the filter selects the user.
+func (f *Filter) Match(e *Entity) bool {
+	if role, ok := f.Role.Unwrap(); ok && e.Role != role {
+		return false
+	}
+
+	if active, ok := f.Active.Unwrap(); ok && e.Active != active {
+		return false
+	}
+
+	if f.Search == "" {
+		return true
+	}
+
+	search := Fold(f.Search)
+	return strings.Contains(Fold(e.Name), search) || strings.Contains(Fold(e.Email), search)
+}
+
+// SortKey is what users are ordered by.
+type SortKey int
+
+const (
+	ByCreated SortKey = iota
+	ByName
+	BySIAPE
+)
+
+// Sort orders the users of a listing, its zero value orders them by
+// creation, oldest first.
+type Sort struct {
+	Key  SortKey
+	Desc bool
+}
+
+// ParseSort parses orders as "name" or "-siape", the minus sign
+// reversing the order. The empty string orders by creation.
+func ParseSort(s string) (Sort, error) {
+	var sort Sort
+
+	key, desc := strings.CutPrefix(s, "-")
+	sort.Desc = desc
+
+	switch key {
+	case "created", "":
+		sort.Key = ByCreated
+	case "name":
+		sort.Key = ByName
+	case "siape":
+		sort.Key = BySIAPE
+	default:
+		return Sort{}, xerrors.ErrSortInvalid
+	}
+
+	return sort, nil
+}
+
+// Compare compares the users in the order, those equal in the key
+// being ordered by creation.
+func (s Sort) Compare(a, b *Entity) int {
+	var c int
+
+	switch s.Key {
+	case ByName:
+		c = cmp.Compare(Fold(a.Name), Fold(b.Name))
+	case BySIAPE:
+		c = cmp.Compare(a.SIAPE, b.SIAPE)
+	}
+
+	if c == 0 {
+		c = a.Created.Compare(b.Created)
+	}
+
+	if s.Desc {
+		return -c
+	}
+
+	return c
+}
+
+// Fold lowers the case of the string and strips the accents of its
+// latin letters, so "Otávio" and "OTAVIO" fold to the same string.
+func Fold(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for _, r := range s {
+		// combining marks, as in decomposed strings
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+
+		r = unicode.ToLower(r)
+		if base, in := unaccented[r]; in {
+			r = base
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+var unaccented = func() map[rune]rune {
+	m := make(map[rune]rune)
+	for base, accented := range map[rune]string{
+		'a': "áàâãäå",
+		'c': "ç",
+		'e': "éèêë",
+		'i': "íìîï",
+		'n': "ñ",
+		'o': "óòôõö",
+		'u': "úùûü",
+		'y': "ýÿ",
+	} {
+		for _, r := range accented {
+			m[r] = base
+		}
+	}
+
+	return m
+}()
@@ -0,0 +1,46 @@
+package user_test
+
+import (
+	"testing"
+
+	. "github.com/alan-b-lima/almodon/internal/domain/user"
+)
+
+func TestFold(t *testing.T) {
+	tests := map[string]string{
+		"Otávio Gomes Calazans": "otavio gomes calazans",
+		"JOÃO CONCEIÇÃO":        "joao conceicao",
+		"Ângela Müller":         "angela muller",
+		"Jose\u0301":            "jose",
+		"alan@ufvjm.edu.br":     "alan@ufvjm.edu.br",
+	}
+
+	for in, want := range tests {
+		if got := Fold(in); got != want {
+			t.Errorf("Fold(%q) = %q, expected %q", in, got, want)
+		}
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	tests := map[string]Sort{
+		"":         {Key: ByCreated},
+		"created":  {Key: ByCreated},
+		"-created": {Key: ByCreated, Desc: true},
+		"name":     {Key: ByName},
+		"-siape":   {Key: BySIAPE, Desc: true},
+	}
+
+	for in, want := range tests {
+		got, err := ParseSort(in)
+		if err != nil || got != want {
+			t.Errorf("ParseSort(%q) = %v, %v, expected %v", in, got, err, want)
+		}
+	}
+
+	for _, in := range []string{"email", "--name", "+name"} {
+		if _, err := ParseSort(in); err == nil {
+			t.Errorf("ParseSort(%q) should fail", in)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package user
+
+import (
+	"strings"
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/throttle"
+)
+
+// Guard protects authentication against brute-force attacks, by
+// locking accounts and client addresses out after too many failures,
+// and email addresses against being flooded with recovery tokens.
+//
+// Accounts are keyed by the UUID of the user, so failures count
+// against them whichever identity is tried, or by the identity tried,
+// if it belongs to no one.
+type Guard interface {
+	Check(account, addr string) error
+	Fail(account, addr string) error
+	Succeed(account string)
+	Unlock(account string)
+
+	// AllowRecovery returns whether a recovery token may be sent to
+	// the email address, counting it as sent if so.
+	AllowRecovery(email string) bool
+}
+
+var (
+	accountPolicy = throttle.Policy{
+		Threshold: 5,
+		Base:      time.Minute,
+		Max:       time.Hour,
+		Forget:    24 * time.Hour,
+	}
+
+	addrPolicy = throttle.Policy{
+		Threshold: 20,
+		Base:      time.Minute,
+		Max:       time.Hour,
+		Forget:    24 * time.Hour,
+	}
+
+	recoveryPolicy = throttle.Policy{
+		Threshold: 3,
+		Base:      15 * time.Minute,
+		Max:       24 * time.Hour,
+		Forget:    24 * time.Hour,
+	}
+)
+
+// Throttle is a [Guard] that locks accounts out after 5 consecutive
+// failures and client addresses after 20, for windows starting at one
+// minute, doubling at every further failure, up to an hour. Email
+// addresses are sent 3 recovery tokens, then one every 15 minutes,
+// the wait doubling at every further token, up to a day.
+type Throttle struct {
+	accounts   *throttle.Throttle[string]
+	addrs      *throttle.Throttle[string]
+	recoveries *throttle.Throttle[string]
+}
+
+func NewThrottle() Guard {
+	return &Throttle{
+		accounts:   throttle.New[string](accountPolicy),
+		addrs:      throttle.New[string](addrPolicy),
+		recoveries: throttle.New[string](recoveryPolicy),
+	}
+}
+
+func (t *Throttle) Check(account, addr string) error {
+	now := time.Now()
+
+	until, locked := t.accounts.Check(account, now)
+	if auntil, alocked := t.addrs.Check(addr, now); alocked && auntil.After(until) {
+		until, locked = auntil, true
+	}
+
+	if locked {
+		return xerrors.ErrLoginLocked.New(xerrors.RetryAfter(until.Sub(now)))
+	}
+
+	return nil
+}
+
+func (t *Throttle) Fail(account, addr string) error {
+	now := time.Now()
+
+	until, locked := t.accounts.Fail(account, now)
+	if auntil, alocked := t.addrs.Fail(addr, now); alocked && auntil.After(until) {
+		until, locked = auntil, true
+	}
+
+	if locked {
+		return xerrors.ErrLoginLocked.New(xerrors.RetryAfter(until.Sub(now)))
+	}
+
+	return nil
+}
+
+// Succeed resets the failures of the account. Failures of the address
+// are kept, so that a successful login does not cover a spray of
+// attempts against other accounts from the same address.
+func (t *Throttle) Succeed(account string) {
+	t.accounts.Reset(account)
+}
+
+func (t *Throttle) Unlock(account string) {
+	t.accounts.Reset(account)
+}
+
+func (t *Throttle) AllowRecovery(email string) bool {
+	now := time.Now()
+	email = strings.ToLower(email)
+
+	if _, locked := t.recoveries.Check(email, now); locked {
+		return false
+	}
+
+	t.recoveries.Fail(email, now)
+	return true
+}
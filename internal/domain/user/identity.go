@@ -0,0 +1,219 @@
+package user
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/errors"
+)
+
+// IdentityKind is the kind of document a user is identified by.
+type IdentityKind uint8
+
+const (
+	// KindSIAPE is the registration number of federal staff, up to 7
+	// digits.
+	KindSIAPE IdentityKind = iota + 1
+
+	// KindMatricula is the enrollment number of students, 8 to 10
+	// digits. Each institution numbers its students its own way, so
+	// no check digit is assumed, the length only keeps it apart from
+	// SIAPEs and CPFs.
+	KindMatricula
+
+	// KindCPF is the taxpayer number of individuals, 11 digits, the
+	// last two being check digits. It is the fallback for users with
+	// neither of the others.
+	KindCPF
+)
+
+// String returns the string representation of the IdentityKind.
+func (k IdentityKind) String() string {
+	switch k {
+	case KindSIAPE:
+		return "siape"
+	case KindMatricula:
+		return "matricula"
+	case KindCPF:
+		return "cpf"
+	}
+
+	return "unknown"
+}
+
+// Identity is a single document identifying a user, its value being
+// in canonical form, only digits and without leading zeros for SIAPEs.
+type Identity struct {
+	Kind  IdentityKind
+	Value string
+}
+
+// String returns the string representation of the Identity.
+func (id Identity) String() string {
+	return id.Kind.String() + ":" + id.Value
+}
+
+// Identities are the documents a user is identified by, those the
+// user does not hold are left zero. Each of them is unique among
+// users.
+type Identities struct {
+	SIAPE     int
+	Matricula string
+	CPF       string
+}
+
+// List returns the identities held.
+func (ids Identities) List() []Identity {
+	var list []Identity
+	if ids.SIAPE != 0 {
+		list = append(list, Identity{KindSIAPE, strconv.Itoa(ids.SIAPE)})
+	}
+	if ids.Matricula != "" {
+		list = append(list, Identity{KindMatricula, ids.Matricula})
+	}
+	if ids.CPF != "" {
+		list = append(list, Identity{KindCPF, ids.CPF})
+	}
+
+	return list
+}
+
+// SIAPEIdentity returns the identity of the SIAPE.
+func SIAPEIdentity(siape int) Identity {
+	return Identity{KindSIAPE, strconv.Itoa(siape)}
+}
+
+// ParseIdentity parses a login, telling the kind of identity apart by
+// its length, as their formats do not overlap. Dots, dashes and spaces
+// are ignored, so formatted CPFs are accepted.
+func ParseIdentity(login string) (Identity, error) {
+	value := digits(login)
+	if !isDigits(value) {
+		return Identity{}, xerrors.ErrLoginInvalid
+	}
+
+	switch n := len(value); {
+	case 0 < n && n <= 7:
+		siape, err := strconv.Atoi(value)
+		if err != nil {
+			return Identity{}, xerrors.ErrLoginInvalid
+		}
+
+		siape, err = ProcessSiape(siape)
+		if err != nil {
+			return Identity{}, xerrors.ErrLoginInvalid
+		}
+
+		return SIAPEIdentity(siape), nil
+
+	case 8 <= n && n <= 10:
+		value, err := ProcessMatricula(value)
+		if err != nil {
+			return Identity{}, xerrors.ErrLoginInvalid
+		}
+
+		return Identity{KindMatricula, value}, nil
+
+	case n == 11:
+		value, err := ProcessCPF(value)
+		if err != nil {
+			return Identity{}, xerrors.ErrLoginInvalid
+		}
+
+		return Identity{KindCPF, value}, nil
+	}
+
+	return Identity{}, xerrors.ErrLoginInvalid
+}
+
+func ProcessIdentities(ids Identities) (Identities, error) {
+	if ids == (Identities{}) {
+		return Identities{}, xerrors.ErrIdentityMissing
+	}
+
+	var errsiape, errmat, errcpf error
+	if ids.SIAPE != 0 {
+		ids.SIAPE, errsiape = ProcessSiape(ids.SIAPE)
+	}
+	if ids.Matricula != "" {
+		ids.Matricula, errmat = ProcessMatricula(ids.Matricula)
+	}
+	if ids.CPF != "" {
+		ids.CPF, errcpf = ProcessCPF(ids.CPF)
+	}
+
+	if err := errors.Join(errsiape, errmat, errcpf); err != nil {
+		return Identities{}, err
+	}
+
+	return ids, nil
+}
+
+func ProcessSiape(siape int) (int, error) {
+	if siape < 1 || siape > 9_999_999 {
+		return 0, xerrors.ErrSiapeInvalid
+	}
+
+	return siape, nil
+}
+
+func ProcessMatricula(matricula string) (string, error) {
+	value := digits(matricula)
+	if len(value) < 8 || len(value) > 10 || !isDigits(value) {
+		return "", xerrors.ErrMatriculaInvalid
+	}
+
+	return value, nil
+}
+
+func ProcessCPF(cpf string) (string, error) {
+	value := digits(cpf)
+	if len(value) != 11 || !isDigits(value) {
+		return "", xerrors.ErrCPFInvalid
+	}
+
+	// repeated digits pass the check, but are not issued
+	if strings.Count(value, value[:1]) == len(value) {
+		return "", xerrors.ErrCPFInvalid
+	}
+
+	if mod11(value[:9]) != value[9] || mod11(value[:10]) != value[10] {
+		return "", xerrors.ErrCPFInvalid
+	}
+
+	return value, nil
+}
+
+// digits drops the separators people type documents with.
+func digits(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '-', ' ':
+			return -1
+		}
+
+		return r
+	}, s)
+}
+
+func isDigits(s string) bool {
+	for i := range len(s) {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mod11 returns the check digit of the digits, as of the CPF, the
+// weights decreasing from len(s)+1 down to 2.
+func mod11(s string) byte {
+	sum := 0
+	for i := range len(s) {
+		sum += int(s[i]-'0') * (len(s) + 1 - i)
+	}
+
+	return byte('0' + sum*10%11%10)
+}
@@ -0,0 +1,63 @@
+package user_test
+
+import (
+	"testing"
+
+	. "github.com/alan-b-lima/almodon/internal/domain/user"
+)
+
+func TestParseIdentity(t *testing.T) {
+	tests := []struct {
+		login    string
+		expected Identity
+		ok       bool
+	}{
+		{"1234567", Identity{KindSIAPE, "1234567"}, true},
+		{"0012345", Identity{KindSIAPE, "12345"}, true},
+		{"2020123457", Identity{KindMatricula, "2020123457"}, true},
+		{"2020.12345-7", Identity{KindMatricula, "2020123457"}, true},
+		{"20201234", Identity{KindMatricula, "20201234"}, true},
+		{"529.982.247-25", Identity{KindCPF, "52998224725"}, true},
+		{"11144477735", Identity{KindCPF, "11144477735"}, true},
+
+		{"", Identity{}, false},
+		{"0", Identity{}, false},
+		{"123456789012", Identity{}, false},
+		{"52998224724", Identity{}, false},
+		{"11111111111", Identity{}, false},
+		{"12a4567", Identity{}, false},
+		{"+123456", Identity{}, false},
+	}
+
+	for _, test := range tests {
+		got, err := ParseIdentity(test.login)
+		if (err == nil) != test.ok {
+			t.Errorf("%q: expected ok %v, got error %v", test.login, test.ok, err)
+			continue
+		}
+
+		if got != test.expected {
+			t.Errorf("%q: expected %v, got %v", test.login, test.expected, got)
+		}
+	}
+}
+
+func TestProcessIdentities(t *testing.T) {
+	if _, err := ProcessIdentities(Identities{}); err == nil {
+		t.Error("users without identities should be refused")
+	}
+
+	ids, err := ProcessIdentities(Identities{Matricula: "2019.00001-3", CPF: "111.444.777-35"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Identities{Matricula: "2019000013", CPF: "11144477735"}
+	if ids != expected {
+		t.Errorf("expected %v, got %v", expected, ids)
+	}
+
+	if _, err := ProcessIdentities(Identities{SIAPE: 1, CPF: "11144477736"}); err == nil {
+		t.Error("an invalid identity should refuse all of them")
+	}
+}
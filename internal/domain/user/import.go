@@ -0,0 +1,277 @@
+package user
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/domain/recovery"
+	"github.com/alan-b-lima/almodon/internal/notify"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/errors"
+)
+
+// Credentials is how imported users come to know their passwords.
+type Credentials int
+
+const (
+	// CredentialsPassword generates passwords, handed back to whoever
+	// imports the users, and to no one else.
+	CredentialsPassword Credentials = iota
+
+	// CredentialsReset generates passwords no one knows and sends each
+	// user a recovery token to set their own.
+	CredentialsReset
+)
+
+// inviteMaxAge is how long new users have to set their passwords,
+// they may ask for another token afterwards.
+const inviteMaxAge = time.Hour
+
+func ParseCredentials(s string) (Credentials, error) {
+	switch s {
+	case "password", "":
+		return CredentialsPassword, nil
+	case "reset":
+		return CredentialsReset, nil
+	}
+
+	return 0, xerrors.ErrImportCredentialsInvalid
+}
+
+// Import reads users from a CSV file and creates them. The file must
+// have a header, in which the columns name, email and at least one of
+// siape, matricula and cpf are identified, role being optional and
+// user by default (their portuguese names are also accepted). Both
+// comma and semicolon are accepted as separators.
+//
+// The import is all-or-nothing: if any row is invalid, or holds an
+// identity already in use, none is imported and every row error is
+// reported. Rows whose roles fail grant, if given, are invalid as
+// well. On a dry run, rows are only validated.
+func Import(users interface {
+	GetterByIdentity
+	Importer
+}, tokens recovery.Creater, notifier notify.Notifier, r io.Reader, creds Credentials, grant func(auth.Role) error, dryRun bool) (ImportEntity, error) {
+	br := bufio.NewReader(r)
+
+	cr := csv.NewReader(br)
+	cr.Comma = sniffComma(br)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return ImportEntity{}, xerrors.ErrUserImportEmpty
+	}
+	if err != nil {
+		return ImportEntity{}, xerrors.ErrUserImportMalformed.New(err)
+	}
+
+	cols, err := columns(header)
+	if err != nil {
+		return ImportEntity{}, err
+	}
+
+	var (
+		rows []ImportRecord
+		errs []error
+
+		// lines of the identities seen so far, which must be unique
+		// within the file as well
+		seen = make(map[Identity]int)
+	)
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return ImportEntity{}, xerrors.ErrUserImportMalformed.New(err)
+		}
+
+		line, _ := cr.FieldPos(0)
+
+		row, err := parseRecord(record, cols)
+		if err != nil {
+			errs = append(errs, xerrors.ErrUserImportRow.New(line, err))
+			continue
+		}
+
+		if grant != nil {
+			if err := grant(row.Role); err != nil {
+				errs = append(errs, xerrors.ErrUserImportRow.New(line, err))
+				continue
+			}
+		}
+
+		for _, id := range row.Identities.List() {
+			if first, in := seen[id]; in {
+				errs = append(errs, xerrors.ErrUserImportRow.New(line, xerrors.ErrUserImportRepeated.New(id.Kind, first)))
+				continue
+			}
+			seen[id] = line
+
+			_, err := users.GetByIdentity(id)
+			if err == nil {
+				errs = append(errs, xerrors.ErrUserImportRow.New(line, xerrors.ErrIdentityTaken.New(id.Kind)))
+			} else if !isNotFound(err) {
+				return ImportEntity{}, err
+			}
+		}
+
+		row.Line = line
+		rows = append(rows, row)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return ImportEntity{}, xerrors.ErrUserImport.New(err)
+	}
+
+	if dryRun || len(rows) == 0 {
+		return ImportEntity{DryRun: dryRun, Records: rows}, nil
+	}
+
+	batch := make([]User, len(rows))
+	for i := range rows {
+		rows[i].Password = randomPassword()
+
+		u, err := New(rows[i].Identities, rows[i].Name, rows[i].Email, rows[i].Password, rows[i].Role)
+		if err != nil {
+			return ImportEntity{}, xerrors.ErrUserImport.New(xerrors.ErrUserImportRow.New(rows[i].Line, err))
+		}
+
+		batch[i] = u
+	}
+
+	res, err := users.Import(batch)
+	if err != nil {
+		return ImportEntity{}, err
+	}
+
+	for i := range rows {
+		rows[i].Entity = res[i]
+
+		if creds == CredentialsReset {
+			rows[i].Password = ""
+
+			err := notifyRecovery(tokens, notifier, &res[i], inviteMaxAge, "Almodon: sua conta foi criada",
+				"Olá, %[1]s.\n\nSua conta no Almodon foi criada. Use o código a seguir para definir sua senha, ele é válido até %[2]s:\n\n%[3]s\n\nDepois disso, peça um novo código em \"esqueci minha senha\".\n")
+			rows[i].Notified = err == nil
+		}
+	}
+
+	return ImportEntity{Records: rows}, nil
+}
+
+const (
+	colSIAPE = iota
+	colMatricula
+	colCPF
+	colName
+	colEmail
+	colRole
+
+	colCount
+)
+
+var headerAliases = map[string]int{
+	"siape":     colSIAPE,
+	"matricula": colMatricula,
+	"matrícula": colMatricula,
+	"cpf":       colCPF,
+	"name":      colName,
+	"nome":      colName,
+	"email":     colEmail,
+	"e-mail":    colEmail,
+	"role":      colRole,
+	"papel":     colRole,
+	"perfil":    colRole,
+}
+
+func columns(header []string) ([colCount]int, error) {
+	var cols [colCount]int
+	for i := range cols {
+		cols[i] = -1
+	}
+
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		name = strings.TrimPrefix(name, "\ufeff")
+
+		if col, in := headerAliases[name]; in && cols[col] < 0 {
+			cols[col] = i
+		}
+	}
+
+	if cols[colName] < 0 || cols[colEmail] < 0 || (cols[colSIAPE] < 0 && cols[colMatricula] < 0 && cols[colCPF] < 0) {
+		return cols, xerrors.ErrUserImportMissingColumns
+	}
+
+	return cols, nil
+}
+
+// parseRecord validates every field of the record, reporting all of
+// their errors.
+func parseRecord(record []string, cols [colCount]int) (ImportRecord, error) {
+	field := func(col int) string {
+		if cols[col] < 0 || cols[col] >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[cols[col]])
+	}
+
+	var (
+		row    ImportRecord
+		errsid error
+		errs   []error
+	)
+
+	ids := Identities{Matricula: field(colMatricula), CPF: field(colCPF)}
+	if siape := field(colSIAPE); siape != "" {
+		ids.SIAPE, errsid = strconv.Atoi(siape)
+		if errsid != nil {
+			errsid = xerrors.ErrSiapeInvalid
+		}
+	}
+
+	if errsid == nil {
+		row.Identities, errsid = ProcessIdentities(ids)
+	}
+	errs = append(errs, errsid)
+
+	var err error
+
+	row.Name, err = ProcessName(field(colName))
+	errs = append(errs, err)
+
+	row.Email, err = ProcessEmail(field(colEmail))
+	errs = append(errs, err)
+
+	row.Role, row.Active = auth.User, true
+	if role := field(colRole); role != "" {
+		var ok bool
+		if row.Role, ok = auth.FromString(strings.ToLower(role)); !ok || !row.Role.IsValid() {
+			errs = append(errs, xerrors.ErrRoleInvalid)
+		}
+	}
+
+	return row, errors.Join(errs...)
+}
+
+func sniffComma(br *bufio.Reader) rune {
+	line, _ := br.Peek(br.Size())
+	if i := strings.IndexByte(string(line), '\n'); i >= 0 {
+		line = line[:i]
+	}
+
+	if strings.Count(string(line), ";") > strings.Count(string(line), ",") {
+		return ';'
+	}
+
+	return ','
+}
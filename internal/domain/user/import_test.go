@@ -0,0 +1,150 @@
+package user_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alan-b-lima/almodon/internal/auth"
+	recoveryrepo "github.com/alan-b-lima/almodon/internal/domain/recovery/repository"
+	. "github.com/alan-b-lima/almodon/internal/domain/user"
+	userrepo "github.com/alan-b-lima/almodon/internal/domain/user/repository"
+	"github.com/alan-b-lima/almodon/internal/notify"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/errors"
+	"github.com/alan-b-lima/almodon/pkg/hash"
+)
+
+type inbox []notify.Message
+
+func (in *inbox) Notify(msg notify.Message) error {
+	*in = append(*in, msg)
+	return nil
+}
+
+func newRepo(t *testing.T) Repository {
+	repo := userrepo.NewMap()
+	if _, err := repo.Create(Identities{SIAPE: 1}, "Alan Barbosa Lima", "alan@ufvjm.edu.br", "12345678", auth.Chief); err != nil {
+		t.Fatal(err)
+	}
+
+	return repo
+}
+
+func TestImport(t *testing.T) {
+	repo := newRepo(t)
+	var in inbox
+
+	csv := "nome;e-mail;siape;matrícula;cpf;papel\n" +
+		"Breno Oliveira;b@ufvjm.edu.br;2;;;admin\n" +
+		"Rafael Silva;r@ufvjm.edu.br;;2020123457;529.982.247-25;\n"
+
+	res, err := Import(repo, recoveryrepo.NewMap(), &in, strings.NewReader(csv), CredentialsPassword, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !res.DryRun || len(res.Records) != 2 || res.Records[0].Password != "" {
+		t.Fatalf("unexpected dry run %+v", res)
+	}
+
+	if _, err := repo.GetByIdentity(SIAPEIdentity(2)); err == nil {
+		t.Fatal("dry run should not create users")
+	}
+
+	res, err = Import(repo, recoveryrepo.NewMap(), &in, strings.NewReader(csv), CredentialsPassword, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rafael := res.Records[1]
+	if rafael.Line != 3 || rafael.CPF != "52998224725" || rafael.Role != auth.User || rafael.Password == "" {
+		t.Errorf("unexpected record %+v", rafael)
+	}
+
+	e, err := repo.GetByIdentity(Identity{Kind: KindMatricula, Value: "2020123457"})
+	if err != nil || !hash.Compare(e.Password, []byte(rafael.Password)) {
+		t.Errorf("expected the generated password to be set, got %v", err)
+	}
+
+	e, err = repo.GetByIdentity(SIAPEIdentity(2))
+	if err != nil || e.Role != auth.Admin {
+		t.Errorf("expected admin by SIAPE 2, got %+v, %v", e, err)
+	}
+
+	if len(in) != 0 {
+		t.Errorf("generated passwords should not be notified, got %d messages", len(in))
+	}
+}
+
+func TestImportReset(t *testing.T) {
+	repo := newRepo(t)
+	var in inbox
+
+	csv := "name,email,siape\nBreno Oliveira,b@ufvjm.edu.br,2\n"
+
+	res, err := Import(repo, recoveryrepo.NewMap(), &in, strings.NewReader(csv), CredentialsReset, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Records[0].Password != "" || !res.Records[0].Notified {
+		t.Errorf("expected a notified record without password, got %+v", res.Records[0])
+	}
+
+	if len(in) != 1 || in[0].To != "b@ufvjm.edu.br" {
+		t.Errorf("expected a message to the user, got %+v", in)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	repo := newRepo(t)
+
+	csv := "name,email,siape,role\n" +
+		"Breno Oliveira,b@ufvjm.edu.br,2,admin\n" +
+		",not-an-email,x,god\n" +
+		"Alan Lima,a@ufvjm.edu.br,1,\n" +
+		"Breno Augusto,b2@ufvjm.edu.br,2,\n" +
+		"Otávio Calazans,o@ufvjm.edu.br,5,unlogged\n"
+
+	_, err := Import(repo, recoveryrepo.NewMap(), &inbox{}, strings.NewReader(csv), CredentialsPassword, nil, false)
+	if err == nil {
+		t.Fatal("import should fail")
+	}
+
+	msg := err.Error()
+	for _, want := range []string{"line 3", "name cannot be empty", "email must be valid", "SIAPE", "role must be", "line 4", "already in use", "line 5", "repeats the one of line 2", "line 6"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in the error, got:\n%s", want, msg)
+		}
+	}
+
+	if e, ok := errors.AsType[*errors.Error](err); !ok || e.Kind != errors.InvalidInput {
+		t.Errorf("expected invalid input, got %v", err)
+	}
+
+	if _, err := repo.GetByIdentity(SIAPEIdentity(2)); err == nil {
+		t.Error("no user should be created if any row fails")
+	}
+
+	unlogged := "name,email,siape,role\nOtávio Calazans,o@ufvjm.edu.br,5,unlogged\n"
+	if _, err := Import(repo, recoveryrepo.NewMap(), &inbox{}, strings.NewReader(unlogged), CredentialsPassword, nil, true); err == nil {
+		t.Error("dry run should reject a role users cannot have")
+	}
+
+	chief := "name,email,siape,role\nBreno Oliveira,b@ufvjm.edu.br,2,chief\n"
+	grant := func(role auth.Role) error {
+		if role == auth.Chief {
+			return xerrors.ErrUnpriviledUserPromotion.New(auth.Admin, role)
+		}
+
+		return nil
+	}
+	if _, err := Import(repo, recoveryrepo.NewMap(), &inbox{}, strings.NewReader(chief), CredentialsPassword, grant, true); err == nil {
+		t.Error("dry run should reject a role the importer cannot grant")
+	}
+
+	_, err = Import(repo, recoveryrepo.NewMap(), &inbox{}, strings.NewReader("name,email\nX,x@ufvjm.edu.br\n"), CredentialsPassword, nil, false)
+	if e, ok := errors.AsType[*errors.Error](err); !ok || e.Title != "user-import-missing-columns" {
+		t.Errorf("expected missing columns, got %v", err)
+	}
+}
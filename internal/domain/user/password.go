@@ -0,0 +1,180 @@
+package user
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/errors"
+)
+
+// maxPasswordLength bounds the passwords of any policy, in bytes, as
+// hashing longer ones is a waste of the server.
+const maxPasswordLength = 1024
+
+// minPersonal is the shortest part of the name or email of the user
+// passwords are checked not to contain, shorter ones, as "da" or "de",
+// would forbid too much.
+const minPersonal = 4
+
+// PasswordPolicy tells which passwords users may choose. It applies to
+// the passwords chosen by people, not to the ones generated for users
+// imported or provisioned.
+type PasswordPolicy struct {
+	// MinLength and MaxLength bound the length of passwords, in
+	// characters.
+	MinLength int
+	MaxLength int
+
+	// Classes is how many of the character classes, lowercase letters,
+	// uppercase letters, digits and symbols, passwords must mix.
+	Classes int
+
+	// Personal forbids passwords containing the identities of the
+	// user, the parts of their name or of their email.
+	Personal bool
+
+	// Breached is the list of passwords known to have leaked, which
+	// passwords are checked against if given.
+	Breached Breached
+}
+
+// Breached tells whether passwords are known to have leaked, as the
+// lists of package breach do.
+type Breached interface {
+	Contains(password string) (bool, error)
+}
+
+// DefaultPasswordPolicy returns the policy used when none is given.
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength: 8,
+		MaxLength: 64,
+		Personal:  true,
+	}
+}
+
+// Validate reports whether the policy is coherent: lengths must be
+// positive, not exceed what any password may be, and the minimum not
+// exceed the maximum, and there are only four character classes.
+func (p PasswordPolicy) Validate() error {
+	switch {
+	case p.MinLength <= 0:
+		return xerrors.ErrPasswordPolicy.New("minimum length must be positive")
+	case p.MinLength > p.MaxLength:
+		return xerrors.ErrPasswordPolicy.New("minimum length must not exceed the maximum length")
+	case p.MaxLength > maxPasswordLength/utf8.UTFMax:
+		return xerrors.ErrPasswordPolicy.New(fmt.Sprintf("maximum length must not exceed %d", maxPasswordLength/utf8.UTFMax))
+	case p.Classes < 0 || p.Classes > 4:
+		return xerrors.ErrPasswordPolicy.New("character classes must be between 0 and 4")
+	}
+
+	return nil
+}
+
+// Check reports every rule of the policy the password breaks, for a
+// user of the given identities, name and email, as the causes of a
+// single error. Passwords that could not be set regardless of the
+// policy, as empty ones, are reported as such. The breached list is
+// only looked up if the password is otherwise fine.
+func (p PasswordPolicy) Check(password string, ids Identities, name, email string) error {
+	if err := checkPassword(password); err != nil {
+		return err
+	}
+
+	var errs []error
+
+	switch length := utf8.RuneCountInString(password); {
+	case length < p.MinLength:
+		errs = append(errs, xerrors.ErrPasswordTooShort.New(p.MinLength))
+	case length > p.MaxLength:
+		errs = append(errs, xerrors.ErrPasswordTooLong.New(p.MaxLength))
+	}
+
+	if classes(password) < p.Classes {
+		errs = append(errs, xerrors.ErrPasswordClasses.New(p.Classes))
+	}
+
+	if p.Personal {
+		if field, ok := personal(password, ids, name, email); ok {
+			errs = append(errs, xerrors.ErrPasswordPersonal.New(field))
+		}
+	}
+
+	if len(errs) > 0 {
+		return xerrors.ErrPasswordRejected.New(errors.Join(errs...))
+	}
+
+	if p.Breached != nil {
+		breached, err := p.Breached.Contains(password)
+		if err != nil {
+			return xerrors.ErrBreachedUnavailable.New(err)
+		}
+
+		if breached {
+			return xerrors.ErrPasswordRejected.New(xerrors.ErrPasswordBreached)
+		}
+	}
+
+	return nil
+}
+
+func classes(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+
+	return lower + upper + digit + symbol
+}
+
+// personal returns the field of the user the password contains, if
+// any, regardless of case and accents.
+func personal(password string, ids Identities, name, email string) (string, bool) {
+	folded := Fold(password)
+
+	for _, id := range ids.List() {
+		values := []string{id.Value}
+		if id.Kind == KindSIAPE {
+			siape, _ := strconv.Atoi(id.Value)
+			values = append(values, fmt.Sprintf("%07d", siape))
+		}
+
+		for _, value := range values {
+			if len(value) >= minPersonal && strings.Contains(folded, value) {
+				return id.Kind.String(), true
+			}
+		}
+	}
+
+	for part := range strings.FieldsSeq(Fold(name)) {
+		if len(part) >= minPersonal && strings.Contains(folded, part) {
+			return "name", true
+		}
+	}
+
+	local, _, _ := strings.Cut(Fold(email), "@")
+	parts := strings.FieldsFunc(local, func(r rune) bool {
+		return r == '.' || r == '_' || r == '-' || r == '+'
+	})
+
+	for _, part := range append(parts, local) {
+		if len(part) >= minPersonal && strings.Contains(folded, part) {
+			return "email", true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,120 @@
+package user_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/alan-b-lima/almodon/internal/domain/user"
+	"github.com/alan-b-lima/almodon/pkg/errors"
+)
+
+type breached []string
+
+func (b breached) Contains(password string) (bool, error) {
+	for _, p := range b {
+		if p == password {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	policy := PasswordPolicy{
+		MinLength: 10,
+		MaxLength: 20,
+		Classes:   3,
+		Personal:  true,
+		Breached:  breached{"Senha@12345"},
+	}
+
+	if err := policy.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := Identities{SIAPE: 1234567, CPF: "52998224725"}
+	name, email := "Otávio Gomes Calazans", "otavio.calazans@ufvjm.edu.br"
+
+	tests := []struct {
+		password string
+		reasons  []string
+	}{
+		{"Correct-Horse-9", nil},
+		{"Pão-de-Queijo-7", nil},
+		{"Short-1", []string{"at least 10 characters"}},
+		{"a-Very-Long-Password-1", []string{"maximum of 20 characters"}},
+		{"onlylowercase", []string{"at least 3 of"}},
+		{"short", []string{"at least 10 characters", "at least 3 of"}},
+		{"Siape#1234567", []string{"the siape of the user"}},
+		{"Cpf#52998224725", []string{"the cpf of the user"}},
+		{"OTAVIO-rules-1", []string{"the name of the user"}},
+		{"Hi-Calazans-22", []string{"the name of the user"}},
+		{"Otavio.Calazans!", []string{"the name of the user"}},
+		{"Senha@12345", []string{"leaked"}},
+		{" Correct-Horse-9", []string{"begin or end with whitespaces"}},
+		{"", []string{"cannot be empty"}},
+	}
+
+	for _, test := range tests {
+		err := policy.Check(test.password, ids, name, email)
+		if len(test.reasons) == 0 {
+			if err != nil {
+				t.Errorf("%q should be accepted, got %v", test.password, err)
+			}
+			continue
+		}
+
+		if err == nil {
+			t.Errorf("%q should be rejected", test.password)
+			continue
+		}
+
+		lines := strings.Split(err.Error(), "\n")
+		if len(lines) != len(test.reasons) {
+			t.Errorf("%q should fail for %v, got %v", test.password, test.reasons, err)
+			continue
+		}
+
+		for i, reason := range test.reasons {
+			if !strings.Contains(lines[i], reason) {
+				t.Errorf("%q should fail for %q, got %q", test.password, reason, lines[i])
+			}
+		}
+	}
+}
+
+func TestPasswordPolicyPersonalField(t *testing.T) {
+	policy := DefaultPasswordPolicy()
+	ids := Identities{Matricula: "2020123457"}
+
+	err := policy.Check("mat2020123457", ids, "Rafael Gomes Silva", "r@ufvjm.edu.br")
+	if err == nil || !strings.Contains(err.Error(), "matricula") {
+		t.Errorf("expected the matrícula to be reported, got %v", err)
+	}
+
+	// short parts of the name, as "da", are not checked
+	if err := policy.Check("da-casa-velha", Identities{SIAPE: 1}, "Ana da Luz", "a@ufvjm.edu.br"); err != nil {
+		t.Errorf("short parts should not be checked, got %v", err)
+	}
+}
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	policies := []PasswordPolicy{
+		{MinLength: 0, MaxLength: 64},
+		{MinLength: 10, MaxLength: 8},
+		{MinLength: 8, MaxLength: 4096},
+		{MinLength: 8, MaxLength: 64, Classes: 5},
+	}
+
+	for _, policy := range policies {
+		err := policy.Validate()
+		if e, ok := errors.AsType[*errors.Error](err); !ok || e.Title != "password-policy" {
+			t.Errorf("%+v should be invalid, got %v", policy, err)
+		}
+	}
+
+	if err := DefaultPasswordPolicy().Validate(); err != nil {
+		t.Errorf("default policy should be valid, got %v", err)
+	}
+}
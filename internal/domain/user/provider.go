@@ -0,0 +1,83 @@
+package user
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/errors"
+)
+
+// Provider authenticates users against an external directory, such as
+// the institutional LDAP, in front of the local passwords.
+type Provider interface {
+	// Authenticate verifies the password of the account of the
+	// identity in the directory. It fails with
+	// [xerrors.ErrDirectoryNoAccount] if there is no such account, with
+	// [xerrors.ErrIncorrectPassword] if the password does not match,
+	// and with [xerrors.ErrDirectoryUnavailable] if the directory
+	// cannot tell.
+	Authenticate(id Identity, password string) (Account, error)
+}
+
+// SSO authenticates users through an identity provider they are
+// redirected to, such as the institutional OpenID provider, rather
+// than with a password.
+type SSO interface {
+	// Begin starts a login, returning the URL of the provider the user
+	// is sent to and the state the provider sends them back with.
+	Begin() (url, state string, err error)
+
+	// Finish completes the login of the state with the code the
+	// provider sent the user back with, returning their account. It
+	// fails with [xerrors.ErrSSOStateInvalid] if there is no such
+	// login in progress.
+	Finish(state, code string) (Account, error)
+}
+
+// Account is a user as known by a directory.
+type Account struct {
+	Identities
+	Name  string
+	Email string
+}
+
+// verifyExternal authenticates the identity against the provider,
+// provisioning a local user for the account on its first login, or
+// linking it to the local user of the same identity. It returns
+// whether the directory decided, either way, as when it did not, the
+// local password is checked instead.
+func verifyExternal(users Creater, provider Provider, id Identity, local Entity, found bool, password string) (Entity, bool, error) {
+	if provider == nil {
+		return Entity{}, false, nil
+	}
+
+	acc, err := provider.Authenticate(id, password)
+	if err, ok := errors.AsType[*errors.Error](err); ok && (err.Kind == errors.NotFound || err.Kind == errors.Unavailable) {
+		return Entity{}, false, nil
+	}
+	if err != nil {
+		return Entity{}, true, err
+	}
+
+	if found {
+		return local, true, nil
+	}
+
+	res, err := users.Create(acc.Identities, acc.Name, acc.Email, randomPassword(), auth.User)
+	if err != nil {
+		return Entity{}, true, xerrors.ErrDirectoryProvision.New(err)
+	}
+
+	return res, true, nil
+}
+
+// randomPassword generates a password no one knows, for users that
+// log in through a directory, until they set one of their own.
+func randomPassword() string {
+	var buf [24]byte
+	rand.Read(buf[:])
+
+	return base64.RawURLEncoding.EncodeToString(buf[:])
+}
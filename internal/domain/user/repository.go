@@ -11,33 +11,61 @@ import (
 type Repository interface {
 	Lister
 	Getter
-	GetterBySIAPE
+	GetterByIdentity
+	GetterByEmail
 	Creater
 	Patcher
+	Activator
+	Rehasher
+	Importer
 	Deleter
 }
 
 type (
 	Lister interface {
-		List(offset, limit int) (Entities, error)
+		List(filter Filter, sort Sort, offset, limit int) (Entities, error)
 	}
 
 	Getter interface {
 		Get(uuid uuid.UUID) (Entity, error)
 	}
 
-	GetterBySIAPE interface {
-		GetBySIAPE(siape int) (Entity, error)
+	GetterByIdentity interface {
+		GetByIdentity(id Identity) (Entity, error)
+	}
+
+	// GetterByEmail looks users up by email, case insensitively.
+	// Emails are not unique, so it fails if many users share it.
+	GetterByEmail interface {
+		GetByEmail(email string) (Entity, error)
 	}
 
 	Creater interface {
-		Create(siape int, name, email, password string, role auth.Role) (Entity, error)
+		Create(ids Identities, name, email, password string, role auth.Role) (Entity, error)
 	}
 
 	Patcher interface {
 		Patch(uuid uuid.UUID, name, email, password opt.Opt[string], role opt.Opt[auth.Role]) (Entity, error)
 	}
 
+	// Importer creates the users all at once, or none of them if any
+	// of their identities is in use.
+	Importer interface {
+		Import(users []User) ([]Entity, error)
+	}
+
+	// Rehasher replaces the hash of the password of the user by one of
+	// the current algorithm, see [User.Rehash].
+	Rehasher interface {
+		Rehash(uuid uuid.UUID, password string) error
+	}
+
+	Activator interface {
+		SetActive(uuid uuid.UUID, active bool) (Entity, error)
+	}
+
+	// Deleter removes users for good, which is only meant for users
+	// deactivated first, see [Purge].
 	Deleter interface {
 		Delete(uuid uuid.UUID) error
 	}
@@ -52,17 +80,38 @@ type (
 	}
 
 	Entity struct {
-		UUID     uuid.UUID
-		SIAPE    int
+		UUID uuid.UUID
+		Identities
 		Name     string
 		Email    string
-		Password [60]byte
+		Password []byte
 		Role     auth.Role
+		Created  time.Time
+		Active   bool
+	}
+
+	ImportEntity struct {
+		DryRun  bool
+		Records []ImportRecord
+	}
+
+	// ImportRecord is a row of an import, with the line it was read
+	// from. The password is only given to whoever imports, and only
+	// if it was generated for them to hand over.
+	ImportRecord struct {
+		Line int
+		Entity
+		Password string
+		Notified bool
 	}
 
 	AuthEntity struct {
 		UUID    uuid.UUID
 		User    uuid.UUID
 		Expires time.Time
+
+		Challenge     string
+		Enroll        bool
+		RecoveryCodes []string
 	}
 )
@@ -2,6 +2,8 @@ package userrepo
 
 import (
 	"cmp"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/alan-b-lima/almodon/internal/auth"
@@ -13,8 +15,10 @@ import (
 )
 
 type Map struct {
-	uuidIndex  map[uuid.UUID]int
-	siapeIndex map[int]int
+	uuidIndex map[uuid.UUID]int
+
+	// identities are unique per kind, the kind being part of the key
+	idIndex map[user.Identity]int
 
 	repo []user.User
 	mu   sync.RWMutex
@@ -22,37 +26,47 @@ type Map struct {
 
 func NewMap() user.Repository {
 	repo := Map{
-		uuidIndex:  make(map[uuid.UUID]int),
-		siapeIndex: make(map[int]int),
+		uuidIndex: make(map[uuid.UUID]int),
+		idIndex:   make(map[user.Identity]int),
 	}
 
 	return &repo
 }
 
-func (m *Map) List(offset, limit int) (user.Entities, error) {
+func (m *Map) List(filter user.Filter, sort user.Sort, offset, limit int) (user.Entities, error) {
 	defer m.mu.RUnlock()
 	m.mu.RLock()
 
-	lo := clamp(0, offset, len(m.repo))
-	hi := clamp(0, offset+limit, len(m.repo))
+	var matched []user.Entity
+	for i := range m.repo {
+		var e user.Entity
+		transform(&e, &m.repo[i])
+
+		if filter.Match(&e) {
+			matched = append(matched, e)
+		}
+	}
+
+	slices.SortStableFunc(matched, func(a, b user.Entity) int {
+		return sort.Compare(&a, &b)
+	})
+
+	lo := clamp(0, offset, len(matched))
+	hi := clamp(0, offset+limit, len(matched))
 
 	if lo >= hi {
 		return user.Entities{
 			Records:      []user.Entity{},
-			TotalRecords: len(m.repo),
+			TotalRecords: len(matched),
 		}, nil
 	}
 
-	res := make([]user.Entity, hi-lo)
-	for i := range m.repo[lo:hi] {
-		transform(&res[i], &m.repo[i])
-	}
-
+	res := matched[lo:hi]
 	return user.Entities{
 		Offset:       lo,
 		Length:       len(res),
 		Records:      res,
-		TotalRecords: len(m.repo),
+		TotalRecords: len(matched),
 	}, nil
 }
 
@@ -70,11 +84,11 @@ func (m *Map) Get(uuid uuid.UUID) (user.Entity, error) {
 	return res, nil
 }
 
-func (m *Map) GetBySIAPE(siape int) (user.Entity, error) {
+func (m *Map) GetByIdentity(id user.Identity) (user.Entity, error) {
 	defer m.mu.RUnlock()
 	m.mu.RLock()
 
-	index, in := m.siapeIndex[siape]
+	index, in := m.idIndex[id]
 	if !in {
 		return user.Entity{}, xerrors.ErrUserNotFound
 	}
@@ -84,21 +98,50 @@ func (m *Map) GetBySIAPE(siape int) (user.Entity, error) {
 	return res, nil
 }
 
-func (m *Map) Create(siape int, name, email, password string, role auth.Role) (user.Entity, error) {
+func (m *Map) GetByEmail(email string) (user.Entity, error) {
+	defer m.mu.RUnlock()
+	m.mu.RLock()
+
+	index := -1
+	for i := range m.repo {
+		if !strings.EqualFold(m.repo[i].Email(), email) {
+			continue
+		}
+
+		if index >= 0 {
+			return user.Entity{}, xerrors.ErrEmailAmbiguous
+		}
+		index = i
+	}
+
+	if index < 0 {
+		return user.Entity{}, xerrors.ErrUserNotFound
+	}
+
+	var res user.Entity
+	transform(&res, &m.repo[index])
+	return res, nil
+}
+
+func (m *Map) Create(ids user.Identities, name, email, password string, role auth.Role) (user.Entity, error) {
 	defer m.mu.Unlock()
 	m.mu.Lock()
 
-	u, err := user.New(siape, name, email, password, role)
+	u, err := user.New(ids, name, email, password, role)
 	if err != nil {
 		return user.Entity{}, err
 	}
 
-	if _, in := m.siapeIndex[u.SIAPE()]; in {
-		return user.Entity{}, xerrors.ErrSiapeTaken
+	for _, id := range u.Identities().List() {
+		if _, in := m.idIndex[id]; in {
+			return user.Entity{}, xerrors.ErrIdentityTaken.New(id.Kind)
+		}
 	}
 
 	m.uuidIndex[u.UUID()] = len(m.repo)
-	m.siapeIndex[u.SIAPE()] = len(m.repo)
+	for _, id := range u.Identities().List() {
+		m.idIndex[id] = len(m.repo)
+	}
 	m.repo = append(m.repo, u)
 
 	var res user.Entity
@@ -106,6 +149,39 @@ func (m *Map) Create(siape int, name, email, password string, role auth.Role) (u
 	return res, nil
 }
 
+func (m *Map) Import(users []user.User) ([]user.Entity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	seen := make(map[user.Identity]struct{})
+	for i := range users {
+		for _, id := range users[i].Identities().List() {
+			_, taken := m.idIndex[id]
+			_, repeated := seen[id]
+			if taken || repeated {
+				return nil, xerrors.ErrIdentityTaken.New(id.Kind)
+			}
+
+			seen[id] = struct{}{}
+		}
+	}
+
+	res := make([]user.Entity, len(users))
+	for i := range users {
+		u := &users[i]
+
+		m.uuidIndex[u.UUID()] = len(m.repo)
+		for _, id := range u.Identities().List() {
+			m.idIndex[id] = len(m.repo)
+		}
+		m.repo = append(m.repo, *u)
+
+		transform(&res[i], u)
+	}
+
+	return res, nil
+}
+
 func (m *Map) Patch(uuid uuid.UUID, name, email, password opt.Opt[string], role opt.Opt[auth.Role]) (user.Entity, error) {
 	defer m.mu.Unlock()
 	m.mu.Lock()
@@ -134,6 +210,35 @@ func (m *Map) Patch(uuid uuid.UUID, name, email, password opt.Opt[string], role
 	return res, nil
 }
 
+func (m *Map) Rehash(uuid uuid.UUID, password string) error {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	index, in := m.uuidIndex[uuid]
+	if !in {
+		return xerrors.ErrUserNotFound
+	}
+
+	return m.repo[index].Rehash(password)
+}
+
+func (m *Map) SetActive(uuid uuid.UUID, active bool) (user.Entity, error) {
+	defer m.mu.Unlock()
+	m.mu.Lock()
+
+	index, in := m.uuidIndex[uuid]
+	if !in {
+		return user.Entity{}, xerrors.ErrUserNotFound
+	}
+
+	u := &m.repo[index]
+	u.SetActive(active)
+
+	var res user.Entity
+	transform(&res, u)
+	return res, nil
+}
+
 func (m *Map) Delete(uuid uuid.UUID) error {
 	defer m.mu.Unlock()
 	m.mu.Lock()
@@ -146,10 +251,19 @@ func (m *Map) Delete(uuid uuid.UUID) error {
 	u := &m.repo[index]
 
 	delete(m.uuidIndex, u.UUID())
-	delete(m.siapeIndex, u.SIAPE())
+	for _, id := range u.Identities().List() {
+		delete(m.idIndex, id)
+	}
 
-	m.repo[index] = m.repo[len(m.repo)-1]
-	m.repo = m.repo[:len(m.repo)-1]
+	// the users after the removed one shift back, keeping the order of
+	// creation, so their indexes must follow
+	m.repo = slices.Delete(m.repo, index, index+1)
+	for i := index; i < len(m.repo); i++ {
+		m.uuidIndex[m.repo[i].UUID()] = i
+		for _, id := range m.repo[i].Identities().List() {
+			m.idIndex[id] = i
+		}
+	}
 
 	return nil
 }
@@ -165,10 +279,12 @@ func some_then[F any](src opt.Opt[F], fn func(F) error) error {
 func transform(r *user.Entity, u *user.User) {
 	r.UUID = u.UUID()
 	r.Name = u.Name()
-	r.SIAPE = u.SIAPE()
+	r.Identities = u.Identities()
 	r.Email = u.Email()
 	r.Password = u.Password()
 	r.Role = u.Role()
+	r.Created = u.Created()
+	r.Active = u.Active()
 }
 
 func clamp[T cmp.Ordered](mn, val, mx T) T {
@@ -0,0 +1,146 @@
+package userrepo_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/domain/user"
+	. "github.com/alan-b-lima/almodon/internal/domain/user/repository"
+	"github.com/alan-b-lima/almodon/pkg/opt"
+)
+
+func TestDelete(t *testing.T) {
+	repo := NewMap()
+
+	var created []user.Entity
+	for siape := 1; siape <= 4; siape++ {
+		res, err := repo.Create(user.Identities{SIAPE: siape}, "Alan Barbosa Lima", "alan@ufvjm.edu.br", "12345678", auth.User)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		created = append(created, res)
+	}
+
+	if err := repo.Delete(created[1].UUID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.Get(created[1].UUID); err == nil {
+		t.Error("deleted user should not be found")
+	}
+
+	for _, want := range []user.Entity{created[0], created[2], created[3]} {
+		res, err := repo.Get(want.UUID)
+		if err != nil || res.SIAPE != want.SIAPE {
+			t.Errorf("expected SIAPE %d by UUID, got %d, %v", want.SIAPE, res.SIAPE, err)
+		}
+
+		res, err = repo.GetByIdentity(user.SIAPEIdentity(want.SIAPE))
+		if err != nil || res.UUID != want.UUID {
+			t.Errorf("expected %v by SIAPE %d, got %v, %v", want.UUID, want.SIAPE, res.UUID, err)
+		}
+	}
+
+	list, err := repo.List(user.Filter{}, user.Sort{}, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if list.TotalRecords != 3 {
+		t.Errorf("expected 3 users left, got %d", list.TotalRecords)
+	}
+}
+
+func TestSetActive(t *testing.T) {
+	repo := NewMap()
+
+	res, err := repo.Create(user.Identities{SIAPE: 1}, "Alan Barbosa Lima", "alan@ufvjm.edu.br", "12345678", auth.User)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !res.Active {
+		t.Fatal("users should be created active")
+	}
+
+	if res, err = repo.SetActive(res.UUID, false); err != nil || res.Active {
+		t.Fatalf("expected deactivated user, got %v, %v", res.Active, err)
+	}
+
+	if res, err = repo.Get(res.UUID); err != nil || res.Active {
+		t.Errorf("deactivation should persist, got %v, %v", res.Active, err)
+	}
+}
+
+func TestList(t *testing.T) {
+	repo := NewMap()
+
+	users := []struct {
+		siape int
+		name  string
+		email string
+		role  auth.Role
+	}{
+		{3, "Otávio Gomes Calazans", "o@ufvjm.edu.br", auth.User},
+		{1, "Alan Barbosa Lima", "alan@ufvjm.edu.br", auth.Chief},
+		{2, "Breno Augusto Braga Oliveira", "b@ufvjm.edu.br", auth.Admin},
+		{4, "Luiz Felipe Melo Oliveira", "otavio.l@ufvjm.edu.br", auth.User},
+	}
+
+	for _, u := range users {
+		if _, err := repo.Create(user.Identities{SIAPE: u.siape}, u.name, u.email, "12345678", u.role); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, _ := repo.GetByIdentity(user.SIAPEIdentity(2))
+	repo.SetActive(res.UUID, false)
+
+	siapes := func(filter user.Filter, sort string, offset, limit int) ([]int, int) {
+		s, err := user.ParseSort(sort)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		list, err := repo.List(filter, s, offset, limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var res []int
+		for _, e := range list.Records {
+			res = append(res, e.SIAPE)
+		}
+
+		return res, list.TotalRecords
+	}
+
+	tests := []struct {
+		name   string
+		filter user.Filter
+		sort   string
+		offset int
+		limit  int
+		want   []int
+		total  int
+	}{
+		{"created", user.Filter{}, "", 0, 10, []int{3, 1, 2, 4}, 4},
+		{"page", user.Filter{}, "created", 1, 2, []int{1, 2}, 4},
+		{"name", user.Filter{}, "name", 0, 10, []int{1, 2, 4, 3}, 4},
+		{"-siape", user.Filter{}, "-siape", 0, 10, []int{4, 3, 2, 1}, 4},
+		{"role", user.Filter{Role: opt.Some(auth.User)}, "siape", 0, 10, []int{3, 4}, 2},
+		{"inactive", user.Filter{Active: opt.Some(false)}, "", 0, 10, []int{2}, 1},
+		{"search accents", user.Filter{Search: "OTAVIO"}, "siape", 0, 10, []int{3, 4}, 2},
+		{"search page", user.Filter{Search: "oliveira"}, "name", 1, 1, []int{4}, 2},
+		{"beyond", user.Filter{}, "", 10, 10, nil, 4},
+	}
+
+	for _, test := range tests {
+		got, total := siapes(test.filter, test.sort, test.offset, test.limit)
+		if !slices.Equal(got, test.want) || total != test.total {
+			t.Errorf("%s: expected %v of %d, got %v of %d", test.name, test.want, test.total, got, total)
+		}
+	}
+}
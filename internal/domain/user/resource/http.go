@@ -1,8 +1,10 @@
 package users
 
 import (
+	"crypto/subtle"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/alan-b-lima/almodon/internal/auth"
 	"github.com/alan-b-lima/almodon/internal/domain/user"
@@ -20,14 +22,39 @@ func New(users user.Service) *Resource {
 	rc := Resource{Users: users}
 
 	routes := map[string]http.HandlerFunc{
-		"GET /users/":              rc.List,
-		"GET /users/{uuid}":        rc.Get,
-		"GET /users/siape/{siape}": rc.GetBySIAPE,
-		"POST /users/":             rc.Create,
-		"PATCH /users/{uuid}":      rc.Patch,
-		"DELETE /users/{uuid}":     rc.Delete,
-		"POST /users/auth/":        rc.Authenticate,
-		"GET /users/me/":           rc.Me,
+		"GET /users/":                      rc.List,
+		"GET /users/{uuid}":                rc.Get,
+		"GET /users/siape/{siape}":         rc.GetBySIAPE,
+		"POST /users/":                     rc.Create,
+		"PATCH /users/{uuid}":              rc.UpdateProfile,
+		"PUT /users/me/password":           rc.ChangePassword,
+		"PUT /users/{uuid}/password":       rc.ResetPassword,
+		"PUT /users/{uuid}/role":           rc.ChangeRole,
+		"POST /users/import":               rc.Import,
+		"DELETE /users/{uuid}":             rc.Deactivate,
+		"PUT /users/{uuid}/active":         rc.Reactivate,
+		"DELETE /users/{uuid}/purge":       rc.Purge,
+		"DELETE /users/{uuid}/lockout":     rc.Unlock,
+		"POST /users/auth/":                rc.Authenticate,
+		"DELETE /users/auth/{$}":           rc.Logout,
+		"POST /users/auth/totp":            rc.AuthenticateTwoFactor,
+		"POST /users/auth/totp/enroll":     rc.EnrollByChallenge,
+		"GET /users/auth/oidc":             rc.BeginSSO,
+		"GET /users/auth/oidc/callback":    rc.FinishSSO,
+		"GET /users/me/totp":               rc.GetTwoFactor,
+		"POST /users/me/totp":              rc.EnrollTwoFactor,
+		"POST /users/me/totp/confirm":      rc.ConfirmTwoFactor,
+		"DELETE /users/me/totp":            rc.DisableTwoFactor,
+		"DELETE /users/{uuid}/totp":        rc.ResetTwoFactor,
+		"POST /users/password/forgot":      rc.ForgotPassword,
+		"POST /users/password/reset":       rc.RecoverPassword,
+		"GET /users/me/":                   rc.Me,
+		"GET /users/me/sessions":           rc.ListSessions,
+		"DELETE /users/me/sessions/{uuid}": rc.RevokeSession,
+		"DELETE /users/{uuid}/sessions":    rc.RevokeSessions,
+		"GET /users/me/tokens":             rc.ListTokens,
+		"POST /users/me/tokens":            rc.CreateToken,
+		"DELETE /users/me/tokens/{uuid}":   rc.RevokeToken,
 	}
 
 	for route, handler := range routes {
@@ -115,7 +142,7 @@ func (rc *Resource) GetBySIAPE(w http.ResponseWriter, r *http.Request) {
 }
 
 func (rc *Resource) Create(w http.ResponseWriter, r *http.Request) {
-	act, err := resource.Session(rc.Users, r)
+	act, err := resource.CookieSession(rc.Users, r)
 	if err != nil {
 		resource.WriteJsonError(w, err)
 		return
@@ -140,7 +167,7 @@ func (rc *Resource) Create(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (rc *Resource) Patch(w http.ResponseWriter, r *http.Request) {
+func (rc *Resource) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 	act, err := resource.Session(rc.Users, r)
 	if err != nil {
 		resource.WriteJsonError(w, err)
@@ -152,14 +179,14 @@ func (rc *Resource) Patch(w http.ResponseWriter, r *http.Request) {
 		resource.WriteJsonError(w, xerrors.ErrBadUUID)
 		return
 	}
-	req := user.PatchRequest{UUID: uuid}
+	req := user.UpdateProfileRequest{UUID: uuid}
 
 	if err := resource.DecodeJSON(&req, r); err != nil {
 		resource.WriteJsonError(w, err)
 		return
 	}
 
-	res, err := rc.Users.Patch(act, req)
+	res, err := rc.Users.UpdateProfile(act, req)
 	if err != nil {
 		resource.WriteJsonError(w, err)
 		return
@@ -171,8 +198,183 @@ func (rc *Resource) Patch(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (rc *Resource) Delete(w http.ResponseWriter, r *http.Request) {
-	act, err := resource.Session(rc.Users, r)
+func (rc *Resource) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.CookieSession(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	req := user.ChangePasswordRequest{UUID: act.User()}
+	if err := resource.DecodeJSON(&req, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	res, err := rc.Users.ChangePassword(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusOK, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
+
+func (rc *Resource) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.CookieSession(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	uuid, err := uuid.FromString(r.PathValue("uuid"))
+	if err != nil {
+		resource.WriteJsonError(w, xerrors.ErrBadUUID)
+		return
+	}
+	req := user.ResetPasswordRequest{UUID: uuid}
+
+	if err := resource.DecodeJSON(&req, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	res, err := rc.Users.ResetPassword(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusOK, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
+
+func (rc *Resource) ChangeRole(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.CookieSession(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	uuid, err := uuid.FromString(r.PathValue("uuid"))
+	if err != nil {
+		resource.WriteJsonError(w, xerrors.ErrBadUUID)
+		return
+	}
+	req := user.ChangeRoleRequest{UUID: uuid}
+
+	if err := resource.DecodeJSON(&req, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	res, err := rc.Users.ChangeRole(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusOK, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
+
+// Import creates users from a CSV file, or only validates it on a dry
+// run, reporting the errors of all rows at once.
+func (rc *Resource) Import(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.CookieSession(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	var req user.ImportRequest
+	if err := resource.QueryParams(r.URL.Query(), &req); err != nil {
+		resource.WriteJsonError(w, xerrors.ErrBadQueryParams.New(err))
+		return
+	}
+
+	req.Body, err = resource.CSVBody(w, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	res, err := rc.Users.Import(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if res.DryRun {
+		status = http.StatusOK
+	}
+
+	if err := resource.EncodeJSON(&res, status, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
+
+// Deactivate keeps the user from logging in, their records are kept.
+func (rc *Resource) Deactivate(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.CookieSession(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	uuid, err := uuid.FromString(r.PathValue("uuid"))
+	if err != nil {
+		resource.WriteJsonError(w, xerrors.ErrBadUUID)
+		return
+	}
+
+	req := user.DeactivateRequest{UUID: uuid}
+	if err := rc.Users.Deactivate(act, req); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rc *Resource) Reactivate(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.CookieSession(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	uuid, err := uuid.FromString(r.PathValue("uuid"))
+	if err != nil {
+		resource.WriteJsonError(w, xerrors.ErrBadUUID)
+		return
+	}
+
+	req := user.ReactivateRequest{UUID: uuid}
+	res, err := rc.Users.Reactivate(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusOK, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
+
+// Purge removes a deactivated user for good.
+func (rc *Resource) Purge(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.CookieSession(rc.Users, r)
 	if err != nil {
 		resource.WriteJsonError(w, err)
 		return
@@ -184,8 +386,30 @@ func (rc *Resource) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	req := user.DeleteRequest{UUID: uuid}
-	if err := rc.Users.Delete(act, req); err != nil {
+	req := user.PurgeRequest{UUID: uuid}
+	if err := rc.Users.Purge(act, req); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rc *Resource) Unlock(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.CookieSession(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	uuid, err := uuid.FromString(r.PathValue("uuid"))
+	if err != nil {
+		resource.WriteJsonError(w, xerrors.ErrBadUUID)
+		return
+	}
+
+	req := user.UnlockRequest{UUID: uuid}
+	if err := rc.Users.Unlock(act, req); err != nil {
 		resource.WriteJsonError(w, err)
 		return
 	}
@@ -194,7 +418,11 @@ func (rc *Resource) Delete(w http.ResponseWriter, r *http.Request) {
 }
 
 func (rc *Resource) Authenticate(w http.ResponseWriter, r *http.Request) {
-	var req user.AuthRequest
+	req := user.AuthRequest{
+		Address:   resource.ClientAddress(r),
+		UserAgent: r.UserAgent(),
+		Request:   resource.RequestID(r),
+	}
 	if err := resource.DecodeJSON(&req, r); err != nil {
 		resource.WriteJsonError(w, err)
 		return
@@ -206,22 +434,187 @@ func (rc *Resource) Authenticate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	writeAuth(w, r, &res)
+}
+
+// AuthenticateTwoFactor answers the challenge of a login with the
+// second factor, issuing the session.
+func (rc *Resource) AuthenticateTwoFactor(w http.ResponseWriter, r *http.Request) {
+	req := user.AuthTwoFactorRequest{
+		Address:   resource.ClientAddress(r),
+		UserAgent: r.UserAgent(),
+		Request:   resource.RequestID(r),
+	}
+	if err := resource.DecodeJSON(&req, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	res, err := rc.Users.AuthenticateTwoFactor(req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	writeAuth(w, r, &res)
+}
+
+// writeAuth sets the session cookie and responds with the session, or,
+// if a second factor is still due, responds with the challenge alone.
+func writeAuth(w http.ResponseWriter, r *http.Request, res *user.AuthResponse) {
+	status := http.StatusAccepted
+	if res.Challenge == "" {
+		resource.SetSessionCookie(w, res.UUID, res.Expires)
+		status = http.StatusCreated
+	}
+
+	if err := resource.EncodeJSON(res, status, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
+
+// ssoStateCookieName binds the callback of a single sign-on login to
+// the browser that started it, against login CSRF.
+const ssoStateCookieName = "sso_state"
+
+// BeginSSO redirects to the identity provider to log in.
+func (rc *Resource) BeginSSO(w http.ResponseWriter, r *http.Request) {
+	res, err := rc.Users.BeginSSO(user.BeginSSORequest{})
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	// Lax, as the provider redirects back with a top-level navigation
 	http.SetCookie(w, &http.Cookie{
-		Name:     resource.SessionCookieName,
-		Value:    res.UUID.String(),
-		Expires:  res.Expires,
+		Name:     ssoStateCookieName,
+		Value:    res.State,
+		MaxAge:   int((10 * time.Minute).Seconds()),
 		Path:     "/",
 		HttpOnly: true,
 		Secure:   true,
 		SameSite: http.SameSiteLaxMode,
 	})
 
-	if err := resource.EncodeJSON(&res, http.StatusCreated, w, r); err != nil {
+	http.Redirect(w, r, res.URL, http.StatusFound)
+}
+
+// FinishSSO is where the identity provider redirects back to, issuing
+// the session as a login with password would.
+func (rc *Resource) FinishSSO(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	cookie, err := r.Cookie(ssoStateCookieName)
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoStateCookieName,
+		Value:    "",
+		MaxAge:   -1,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	state := query.Get("state")
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		resource.WriteJsonError(w, xerrors.ErrSSOStateInvalid)
+		return
+	}
+
+	req := user.FinishSSORequest{
+		State:     state,
+		Code:      query.Get("code"),
+		Address:   resource.ClientAddress(r),
+		UserAgent: r.UserAgent(),
+		Request:   resource.RequestID(r),
+	}
+
+	res, err := rc.Users.FinishSSO(req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	writeAuth(w, r, &res)
+}
+
+// EnrollByChallenge starts the enrollment of users logging in whose
+// role requires a second factor they do not have yet.
+func (rc *Resource) EnrollByChallenge(w http.ResponseWriter, r *http.Request) {
+	var req user.EnrollByChallengeRequest
+
+	if err := resource.DecodeJSON(&req, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	res, err := rc.Users.EnrollByChallenge(req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusOK, w, r); err != nil {
 		resource.WriteJsonError(w, err)
 		return
 	}
 }
 
+// Logout revokes the session of the request and clears its cookie.
+// It is idempotent: logging out without a session, or with one that
+// is already gone, succeeds all the same.
+func (rc *Resource) Logout(w http.ResponseWriter, r *http.Request) {
+	session, err := resource.SessionCookie(resource.SessionCookieName, r)
+	if err == nil {
+		req := user.LogoutRequest{
+			Session: session,
+			Address: resource.ClientAddress(r),
+			Request: resource.RequestID(r),
+		}
+		if err := rc.Users.Logout(req); err != nil {
+			resource.WriteJsonError(w, err)
+			return
+		}
+	}
+
+	resource.ClearSessionCookie(w)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rc *Resource) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req user.ForgotPasswordRequest
+	if err := resource.DecodeJSON(&req, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := rc.Users.ForgotPassword(req); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (rc *Resource) RecoverPassword(w http.ResponseWriter, r *http.Request) {
+	req := user.RecoverPasswordRequest{
+		Address: resource.ClientAddress(r),
+		Request: resource.RequestID(r),
+	}
+	if err := resource.DecodeJSON(&req, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if _, err := rc.Users.RecoverPassword(req); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (rc *Resource) Me(w http.ResponseWriter, r *http.Request) {
 	act, err := resource.Session(rc.Users, r)
 	if err != nil {
@@ -242,6 +635,72 @@ func (rc *Resource) Me(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (rc *Resource) ListSessions(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.CookieSession(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	current, _ := resource.SessionCookie(resource.SessionCookieName, r)
+	req := user.ListSessionsRequest{User: act.User(), Current: current}
+
+	res, err := rc.Users.ListSessions(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusOK, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
+
+func (rc *Resource) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.CookieSession(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	uuid, err := uuid.FromString(r.PathValue("uuid"))
+	if err != nil {
+		resource.WriteJsonError(w, xerrors.ErrBadUUID)
+		return
+	}
+
+	req := user.RevokeSessionRequest{User: act.User(), Session: uuid}
+	if err := rc.Users.RevokeSession(act, req); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rc *Resource) RevokeSessions(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.CookieSession(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	uuid, err := uuid.FromString(r.PathValue("uuid"))
+	if err != nil {
+		resource.WriteJsonError(w, xerrors.ErrBadUUID)
+		return
+	}
+
+	req := user.RevokeSessionsRequest{UUID: uuid}
+	if err := rc.Users.RevokeSessions(act, req); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func n[Res, Req any](u user.Service, req Req, fn func(auth.Actor, Req) (Res, error), w http.ResponseWriter, r *http.Request) error {
 	act, err := resource.Session(u, r)
 	if err != nil {
@@ -263,3 +722,187 @@ func n[Res, Req any](u user.Service, req Req, fn func(auth.Actor, Req) (Res, err
 
 	return nil
 }
+
+func (rc *Resource) ListTokens(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.CookieSession(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	req := user.ListTokensRequest{User: act.User()}
+
+	res, err := rc.Users.ListTokens(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusOK, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
+
+func (rc *Resource) CreateToken(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.CookieSession(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	var req user.CreateTokenRequest
+
+	if err := resource.DecodeJSON(&req, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+	req.User = act.User()
+
+	res, err := rc.Users.CreateToken(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusCreated, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
+
+func (rc *Resource) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.CookieSession(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	uuid, err := uuid.FromString(r.PathValue("uuid"))
+	if err != nil {
+		resource.WriteJsonError(w, xerrors.ErrBadUUID)
+		return
+	}
+
+	req := user.RevokeTokenRequest{User: act.User(), Token: uuid}
+	if err := rc.Users.RevokeToken(act, req); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rc *Resource) GetTwoFactor(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.Session(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	req := user.GetTwoFactorRequest{UUID: act.User()}
+
+	res, err := rc.Users.GetTwoFactor(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusOK, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
+
+func (rc *Resource) EnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.CookieSession(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	req := user.EnrollTwoFactorRequest{UUID: act.User()}
+
+	res, err := rc.Users.EnrollTwoFactor(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusOK, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
+
+func (rc *Resource) ConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.CookieSession(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	var req user.ConfirmTwoFactorRequest
+
+	if err := resource.DecodeJSON(&req, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+	req.UUID = act.User()
+
+	res, err := rc.Users.ConfirmTwoFactor(act, req)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	if err := resource.EncodeJSON(&res, http.StatusOK, w, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+}
+
+func (rc *Resource) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.CookieSession(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	var req user.DisableTwoFactorRequest
+
+	if err := resource.DecodeJSON(&req, r); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+	req.UUID = act.User()
+
+	if err := rc.Users.DisableTwoFactor(act, req); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rc *Resource) ResetTwoFactor(w http.ResponseWriter, r *http.Request) {
+	act, err := resource.CookieSession(rc.Users, r)
+	if err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	uuid, err := uuid.FromString(r.PathValue("uuid"))
+	if err != nil {
+		resource.WriteJsonError(w, xerrors.ErrBadUUID)
+		return
+	}
+
+	req := user.ResetTwoFactorRequest{UUID: uuid}
+	if err := rc.Users.ResetTwoFactor(act, req); err != nil {
+		resource.WriteJsonError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
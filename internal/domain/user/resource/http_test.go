@@ -0,0 +1,107 @@
+package users_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/domain/user"
+	. "github.com/alan-b-lima/almodon/internal/domain/user/resource"
+	"github.com/alan-b-lima/almodon/internal/support/resource"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+// tokens stands for a service whose every session and API token
+// belongs to the same user, and which records the tokens created.
+type tokens struct {
+	user.Service
+	owner   uuid.UUID
+	created int
+}
+
+func (s *tokens) Actor(req user.ActorRequest) (auth.Actor, error) {
+	return auth.NewLogged(s.owner, auth.User), nil
+}
+
+func (s *tokens) CreateToken(act auth.Actor, req user.CreateTokenRequest) (user.CreateTokenResponse, error) {
+	s.created++
+	return user.CreateTokenResponse{}, nil
+}
+
+func (s *tokens) ListTokens(act auth.Actor, req user.ListTokensRequest) (user.TokensResponse, error) {
+	return user.TokensResponse{}, nil
+}
+
+func TestTokensRequireSession(t *testing.T) {
+	svc := &tokens{owner: uuid.NewUUIDv7()}
+	rc := New(svc)
+
+	request := func(method, body string, bearer bool) int {
+		r := httptest.NewRequest(method, "/users/me/tokens", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("Accept", "application/json")
+		if bearer {
+			r.Header.Set("Authorization", "Bearer almodon_secret")
+		} else {
+			r.AddCookie(&http.Cookie{Name: resource.SessionCookieName, Value: uuid.NewUUIDv7().String()})
+		}
+
+		w := httptest.NewRecorder()
+		rc.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	body := `{"name":"script","scopes":["read","write"]}`
+
+	if code := request(http.MethodPost, body, true); code != http.StatusForbidden {
+		t.Errorf("bearer POST got %d, expected %d", code, http.StatusForbidden)
+	}
+
+	if code := request(http.MethodGet, "", true); code != http.StatusForbidden {
+		t.Errorf("bearer GET got %d, expected %d", code, http.StatusForbidden)
+	}
+
+	if svc.created != 0 {
+		t.Fatalf("API tokens should not create tokens, %d were", svc.created)
+	}
+
+	if code := request(http.MethodPost, body, false); code != http.StatusCreated {
+		t.Errorf("session POST got %d, expected %d", code, http.StatusCreated)
+	}
+}
+
+func TestCredentialsRequireSession(t *testing.T) {
+	svc := &tokens{owner: uuid.NewUUIDv7()}
+	rc := New(svc)
+
+	other := uuid.NewUUIDv7().String()
+	routes := []struct{ method, path string }{
+		{http.MethodPost, "/users/"},
+		{http.MethodPut, "/users/me/password"},
+		{http.MethodPut, "/users/" + other + "/password"},
+		{http.MethodPut, "/users/" + other + "/role"},
+		{http.MethodPost, "/users/import"},
+		{http.MethodDelete, "/users/" + other + "/purge"},
+		{http.MethodGet, "/users/me/sessions"},
+		{http.MethodDelete, "/users/me/sessions/" + other},
+		{http.MethodDelete, "/users/" + other + "/sessions"},
+		{http.MethodPost, "/users/me/totp"},
+		{http.MethodDelete, "/users/me/totp"},
+		{http.MethodDelete, "/users/" + other + "/totp"},
+	}
+
+	for _, route := range routes {
+		r := httptest.NewRequest(route.method, route.path, strings.NewReader("{}"))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("Accept", "application/json")
+		r.Header.Set("Authorization", "Bearer almodon_secret")
+
+		w := httptest.NewRecorder()
+		rc.ServeHTTP(w, r)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("bearer %s %s got %d, expected %d", route.method, route.path, w.Code, http.StatusForbidden)
+		}
+	}
+}
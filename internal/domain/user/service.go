@@ -9,8 +9,33 @@ type Service interface {
 	Get(act auth.Actor, req GetRequest) (Response, error)
 	GetBySIAPE(act auth.Actor, req GetBySIAPERequest) (Response, error)
 	Create(act auth.Actor, req CreateRequest) (Response, error)
-	Patch(act auth.Actor, req PatchRequest) (Response, error)
-	Delete(act auth.Actor, req DeleteRequest) error
+	UpdateProfile(act auth.Actor, req UpdateProfileRequest) (Response, error)
+	ChangePassword(act auth.Actor, req ChangePasswordRequest) (Response, error)
+	ResetPassword(act auth.Actor, req ResetPasswordRequest) (Response, error)
+	ChangeRole(act auth.Actor, req ChangeRoleRequest) (Response, error)
+	Import(act auth.Actor, req ImportRequest) (ImportResponse, error)
+	Deactivate(act auth.Actor, req DeactivateRequest) error
+	Reactivate(act auth.Actor, req ReactivateRequest) (Response, error)
+	Purge(act auth.Actor, req PurgeRequest) error
+	Unlock(act auth.Actor, req UnlockRequest) error
+	ListSessions(act auth.Actor, req ListSessionsRequest) (SessionsResponse, error)
+	RevokeSession(act auth.Actor, req RevokeSessionRequest) error
+	RevokeSessions(act auth.Actor, req RevokeSessionsRequest) error
+	GetTwoFactor(act auth.Actor, req GetTwoFactorRequest) (TwoFactorResponse, error)
+	EnrollTwoFactor(act auth.Actor, req EnrollTwoFactorRequest) (EnrollTwoFactorResponse, error)
+	ConfirmTwoFactor(act auth.Actor, req ConfirmTwoFactorRequest) (RecoveryCodesResponse, error)
+	DisableTwoFactor(act auth.Actor, req DisableTwoFactorRequest) error
+	ResetTwoFactor(act auth.Actor, req ResetTwoFactorRequest) error
+	ListTokens(act auth.Actor, req ListTokensRequest) (TokensResponse, error)
+	CreateToken(act auth.Actor, req CreateTokenRequest) (CreateTokenResponse, error)
+	RevokeToken(act auth.Actor, req RevokeTokenRequest) error
 	Authenticate(req AuthRequest) (AuthResponse, error)
+	AuthenticateTwoFactor(req AuthTwoFactorRequest) (AuthResponse, error)
+	EnrollByChallenge(req EnrollByChallengeRequest) (EnrollTwoFactorResponse, error)
+	BeginSSO(req BeginSSORequest) (BeginSSOResponse, error)
+	FinishSSO(req FinishSSORequest) (AuthResponse, error)
+	Logout(req LogoutRequest) error
+	ForgotPassword(req ForgotPasswordRequest) error
+	RecoverPassword(req RecoverPasswordRequest) (Response, error)
 	Actor(req ActorRequest) (auth.Actor, error)
 }
@@ -0,0 +1,357 @@
+package userserve
+
+import (
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/domain/audit"
+	"github.com/alan-b-lima/almodon/internal/domain/user"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+// AuditService records what is changed through the service in the
+// audit log, along with the fields of users before and after. It is
+// meant to sit below the [AuthService], so that only what was allowed
+// and done is recorded.
+//
+// Requests for recovery tokens are not recorded, as they change no
+// account and anyone could flood the log with them, neither are
+// enrollments in two-factor authentication until confirmed. Secrets,
+// as passwords, tokens and recovery codes, are never recorded.
+type AuditService struct {
+	service user.Service
+	records audit.Appender
+}
+
+func NewAudited(service user.Service, records audit.Appender) user.Service {
+	return &AuditService{service: service, records: records}
+}
+
+// Actions recorded besides those checked by the [AuthService].
+const (
+	actLogin            auth.Action = "user.login"
+	actLoginSSO         auth.Action = "user.login-sso"
+	actLogout           auth.Action = "user.logout"
+	actRecoverPassword  auth.Action = "user.recover-password"
+	actEnableTwoFactor  auth.Action = "user.enable-two-factor"
+	actDisableTwoFactor auth.Action = "user.disable-two-factor"
+	actCreateToken      auth.Action = "user.create-token"
+	actRevokeToken      auth.Action = "user.revoke-token"
+)
+
+func userTarget(uuid uuid.UUID) audit.Target {
+	return audit.Target{Kind: "user", ID: uuid.String()}
+}
+
+func sessionTarget(uuid uuid.UUID) audit.Target {
+	return audit.Target{Kind: "session", ID: uuid.String()}
+}
+
+func tokenTarget(uuid uuid.UUID) audit.Target {
+	return audit.Target{Kind: "token", ID: uuid.String()}
+}
+
+// log records the action, failing as internal, since by then the
+// action was already taken.
+func (s *AuditService) log(act auth.Actor, action auth.Action, target audit.Target, before, after any) error {
+	if _, err := audit.Log(s.records, act, action, target, before, after); err != nil {
+		return xerrors.ErrAuditRecord.New(err)
+	}
+
+	return nil
+}
+
+// before returns the user as they are before the action, nil if they
+// cannot be found, in which case the action is bound to fail anyway.
+func (s *AuditService) before(act auth.Actor, uuid uuid.UUID) any {
+	res, err := s.service.Get(act, user.GetRequest{UUID: uuid})
+	if err != nil {
+		return nil
+	}
+
+	return res
+}
+
+// login records the session issued, if any, as opened by its user.
+func (s *AuditService) login(action auth.Action, res *user.AuthResponse, origin auth.Origin) error {
+	if res.UUID == (uuid.UUID{}) {
+		return nil
+	}
+
+	act, err := s.service.Actor(user.ActorRequest{Session: res.UUID})
+	if err != nil {
+		return xerrors.ErrAuditRecord.New(err)
+	}
+	act = act.From(origin)
+
+	if err := s.log(act, action, sessionTarget(res.UUID), nil, nil); err != nil {
+		return err
+	}
+
+	if len(res.RecoveryCodes) > 0 {
+		return s.log(act, actEnableTwoFactor, userTarget(res.User), nil, nil)
+	}
+
+	return nil
+}
+
+func (s *AuditService) List(act auth.Actor, req user.ListRequest) (user.ListResponse, error) {
+	return s.service.List(act, req)
+}
+
+func (s *AuditService) Get(act auth.Actor, req user.GetRequest) (user.Response, error) {
+	return s.service.Get(act, req)
+}
+
+func (s *AuditService) GetBySIAPE(act auth.Actor, req user.GetBySIAPERequest) (user.Response, error) {
+	return s.service.GetBySIAPE(act, req)
+}
+
+func (s *AuditService) Create(act auth.Actor, req user.CreateRequest) (user.Response, error) {
+	res, err := s.service.Create(act, req)
+	if err != nil {
+		return user.Response{}, err
+	}
+
+	return res, s.log(act, actCreate, userTarget(res.UUID), nil, res)
+}
+
+func (s *AuditService) UpdateProfile(act auth.Actor, req user.UpdateProfileRequest) (user.Response, error) {
+	before := s.before(act, req.UUID)
+
+	res, err := s.service.UpdateProfile(act, req)
+	if err != nil {
+		return user.Response{}, err
+	}
+
+	return res, s.log(act, actUpdateProfile, userTarget(res.UUID), before, res)
+}
+
+func (s *AuditService) ChangePassword(act auth.Actor, req user.ChangePasswordRequest) (user.Response, error) {
+	res, err := s.service.ChangePassword(act, req)
+	if err != nil {
+		return user.Response{}, err
+	}
+
+	return res, s.log(act, actChangePassword, userTarget(res.UUID), nil, nil)
+}
+
+func (s *AuditService) ResetPassword(act auth.Actor, req user.ResetPasswordRequest) (user.Response, error) {
+	res, err := s.service.ResetPassword(act, req)
+	if err != nil {
+		return user.Response{}, err
+	}
+
+	return res, s.log(act, actResetPassword, userTarget(res.UUID), nil, nil)
+}
+
+func (s *AuditService) ChangeRole(act auth.Actor, req user.ChangeRoleRequest) (user.Response, error) {
+	before := s.before(act, req.UUID)
+
+	res, err := s.service.ChangeRole(act, req)
+	if err != nil {
+		return user.Response{}, err
+	}
+
+	return res, s.log(act, actChangeRole, userTarget(res.UUID), before, res)
+}
+
+func (s *AuditService) Import(act auth.Actor, req user.ImportRequest) (user.ImportResponse, error) {
+	res, err := s.service.Import(act, req)
+	if err != nil || res.DryRun {
+		return res, err
+	}
+
+	for _, r := range res.Records {
+		if err := s.log(act, actImport, userTarget(r.UUID), nil, r.Response); err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
+
+func (s *AuditService) Deactivate(act auth.Actor, req user.DeactivateRequest) error {
+	before := s.before(act, req.UUID)
+
+	if err := s.service.Deactivate(act, req); err != nil {
+		return err
+	}
+
+	return s.log(act, actDeactivate, userTarget(req.UUID), before, s.before(act, req.UUID))
+}
+
+func (s *AuditService) Reactivate(act auth.Actor, req user.ReactivateRequest) (user.Response, error) {
+	before := s.before(act, req.UUID)
+
+	res, err := s.service.Reactivate(act, req)
+	if err != nil {
+		return user.Response{}, err
+	}
+
+	return res, s.log(act, actReactivate, userTarget(res.UUID), before, res)
+}
+
+func (s *AuditService) Purge(act auth.Actor, req user.PurgeRequest) error {
+	before := s.before(act, req.UUID)
+
+	if err := s.service.Purge(act, req); err != nil {
+		return err
+	}
+
+	return s.log(act, actPurge, userTarget(req.UUID), before, nil)
+}
+
+func (s *AuditService) Unlock(act auth.Actor, req user.UnlockRequest) error {
+	if err := s.service.Unlock(act, req); err != nil {
+		return err
+	}
+
+	return s.log(act, actUnlock, userTarget(req.UUID), nil, nil)
+}
+
+func (s *AuditService) ListSessions(act auth.Actor, req user.ListSessionsRequest) (user.SessionsResponse, error) {
+	return s.service.ListSessions(act, req)
+}
+
+func (s *AuditService) RevokeSession(act auth.Actor, req user.RevokeSessionRequest) error {
+	if err := s.service.RevokeSession(act, req); err != nil {
+		return err
+	}
+
+	return s.log(act, actRevokeSession, sessionTarget(req.Session), nil, nil)
+}
+
+func (s *AuditService) RevokeSessions(act auth.Actor, req user.RevokeSessionsRequest) error {
+	if err := s.service.RevokeSessions(act, req); err != nil {
+		return err
+	}
+
+	return s.log(act, actRevokeSessions, userTarget(req.UUID), nil, nil)
+}
+
+func (s *AuditService) GetTwoFactor(act auth.Actor, req user.GetTwoFactorRequest) (user.TwoFactorResponse, error) {
+	return s.service.GetTwoFactor(act, req)
+}
+
+func (s *AuditService) EnrollTwoFactor(act auth.Actor, req user.EnrollTwoFactorRequest) (user.EnrollTwoFactorResponse, error) {
+	return s.service.EnrollTwoFactor(act, req)
+}
+
+func (s *AuditService) ConfirmTwoFactor(act auth.Actor, req user.ConfirmTwoFactorRequest) (user.RecoveryCodesResponse, error) {
+	res, err := s.service.ConfirmTwoFactor(act, req)
+	if err != nil {
+		return user.RecoveryCodesResponse{}, err
+	}
+
+	return res, s.log(act, actEnableTwoFactor, userTarget(req.UUID), nil, nil)
+}
+
+func (s *AuditService) DisableTwoFactor(act auth.Actor, req user.DisableTwoFactorRequest) error {
+	if err := s.service.DisableTwoFactor(act, req); err != nil {
+		return err
+	}
+
+	return s.log(act, actDisableTwoFactor, userTarget(req.UUID), nil, nil)
+}
+
+func (s *AuditService) ResetTwoFactor(act auth.Actor, req user.ResetTwoFactorRequest) error {
+	if err := s.service.ResetTwoFactor(act, req); err != nil {
+		return err
+	}
+
+	return s.log(act, actResetTwoFactor, userTarget(req.UUID), nil, nil)
+}
+
+func (s *AuditService) ListTokens(act auth.Actor, req user.ListTokensRequest) (user.TokensResponse, error) {
+	return s.service.ListTokens(act, req)
+}
+
+func (s *AuditService) CreateToken(act auth.Actor, req user.CreateTokenRequest) (user.CreateTokenResponse, error) {
+	res, err := s.service.CreateToken(act, req)
+	if err != nil {
+		return user.CreateTokenResponse{}, err
+	}
+
+	return res, s.log(act, actCreateToken, tokenTarget(res.UUID), nil, res.TokenResponse)
+}
+
+func (s *AuditService) RevokeToken(act auth.Actor, req user.RevokeTokenRequest) error {
+	if err := s.service.RevokeToken(act, req); err != nil {
+		return err
+	}
+
+	return s.log(act, actRevokeToken, tokenTarget(req.Token), nil, nil)
+}
+
+func (s *AuditService) Authenticate(req user.AuthRequest) (user.AuthResponse, error) {
+	res, err := s.service.Authenticate(req)
+	if err != nil {
+		return user.AuthResponse{}, err
+	}
+
+	return res, s.login(actLogin, &res, auth.Origin{Addr: req.Address, Request: req.Request})
+}
+
+func (s *AuditService) AuthenticateTwoFactor(req user.AuthTwoFactorRequest) (user.AuthResponse, error) {
+	res, err := s.service.AuthenticateTwoFactor(req)
+	if err != nil {
+		return user.AuthResponse{}, err
+	}
+
+	return res, s.login(actLogin, &res, auth.Origin{Addr: req.Address, Request: req.Request})
+}
+
+func (s *AuditService) EnrollByChallenge(req user.EnrollByChallengeRequest) (user.EnrollTwoFactorResponse, error) {
+	return s.service.EnrollByChallenge(req)
+}
+
+func (s *AuditService) BeginSSO(req user.BeginSSORequest) (user.BeginSSOResponse, error) {
+	return s.service.BeginSSO(req)
+}
+
+func (s *AuditService) FinishSSO(req user.FinishSSORequest) (user.AuthResponse, error) {
+	res, err := s.service.FinishSSO(req)
+	if err != nil {
+		return user.AuthResponse{}, err
+	}
+
+	return res, s.login(actLoginSSO, &res, auth.Origin{Addr: req.Address, Request: req.Request})
+}
+
+// Logout records the logout as done by the user of the session, which
+// is looked up before it is gone. Sessions already gone are logged out
+// of without a record, as nothing changes.
+func (s *AuditService) Logout(req user.LogoutRequest) error {
+	act, erract := s.service.Actor(user.ActorRequest{Session: req.Session})
+
+	if err := s.service.Logout(req); err != nil {
+		return err
+	}
+
+	if erract != nil {
+		return nil
+	}
+
+	act = act.From(auth.Origin{Addr: req.Address, Request: req.Request})
+	return s.log(act, actLogout, sessionTarget(req.Session), nil, nil)
+}
+
+func (s *AuditService) ForgotPassword(req user.ForgotPasswordRequest) error {
+	return s.service.ForgotPassword(req)
+}
+
+func (s *AuditService) RecoverPassword(req user.RecoverPasswordRequest) (user.Response, error) {
+	res, err := s.service.RecoverPassword(req)
+	if err != nil {
+		return user.Response{}, err
+	}
+
+	role, _ := auth.FromString(res.Role)
+	act := auth.NewLogged(res.UUID, role).From(auth.Origin{Addr: req.Address, Request: req.Request})
+
+	return res, s.log(act, actRecoverPassword, userTarget(res.UUID), nil, nil)
+}
+
+func (s *AuditService) Actor(req user.ActorRequest) (auth.Actor, error) {
+	return s.service.Actor(req)
+}
@@ -9,26 +9,84 @@ import (
 
 type AuthService struct {
 	service   user.Service
+	policy    *auth.Policy
 	hierarchy auth.Hierarchy
 }
 
-func New(service user.Service) user.Service {
+func New(service user.Service, policy *auth.Policy) user.Service {
 	return &AuthService{
 		service:   service,
+		policy:    policy,
 		hierarchy: auth.DefaultHierarchy,
 	}
 }
 
-var (
-	permStrictChief = auth.Permit(auth.Chief)
-	permChief       = auth.Permit(auth.Promoted)
-	permAdmin       = auth.Permit(auth.Admin)
-	permLogged      = auth.Permit(auth.User)
-	permPermissive  = auth.Permit(auth.Unlogged)
+const (
+	actList           auth.Action = "user.list"
+	actGet            auth.Action = "user.get"
+	actCreate         auth.Action = "user.create"
+	actUpdateProfile  auth.Action = "user.update-profile"
+	actChangePassword auth.Action = "user.change-password"
+	actResetPassword  auth.Action = "user.reset-password"
+	actChangeRole     auth.Action = "user.change-role"
+	actImport         auth.Action = "user.import"
+	actDeactivate     auth.Action = "user.deactivate"
+	actReactivate     auth.Action = "user.reactivate"
+	actPurge          auth.Action = "user.purge"
+	actUnlock         auth.Action = "user.unlock"
+	actListSessions   auth.Action = "user.list-sessions"
+	actRevokeSession  auth.Action = "user.revoke-session"
+	actRevokeSessions auth.Action = "user.revoke-sessions"
+	actTwoFactor      auth.Action = "user.two-factor"
+	actResetTwoFactor auth.Action = "user.reset-two-factor"
+	actManageTokens   auth.Action = "user.manage-tokens"
 )
 
+// Actions returns the actions checked by the service, along with their
+// default permissions. Reading or changing oneself is granted
+// regardless of them, see [Grants], the permissions apply to other
+// users.
+func Actions() map[auth.Action]auth.Permission {
+	var (
+		chief  = auth.Permit(auth.Chief)
+		logged = auth.Permit(auth.User)
+	)
+
+	return map[auth.Action]auth.Permission{
+		actList:           chief,
+		actGet:            chief,
+		actCreate:         chief,
+		actUpdateProfile:  chief,
+		actChangePassword: logged,
+		actResetPassword:  chief,
+		actChangeRole:     chief,
+		actImport:         chief,
+		actDeactivate:     chief,
+		actReactivate:     chief,
+		actPurge:          chief,
+		actUnlock:         chief,
+		actListSessions:   chief,
+		actRevokeSession:  logged,
+		actRevokeSessions: chief,
+		actTwoFactor:      logged,
+		actResetTwoFactor: chief,
+		actManageTokens:   logged,
+	}
+}
+
+// Grants returns the conditions under which actions are allowed
+// regardless of the role of the actor.
+func Grants() map[auth.Action]auth.Predicate {
+	return map[auth.Action]auth.Predicate{
+		actGet:           auth.Owner(),
+		actUpdateProfile: auth.Owner(),
+		actDeactivate:    auth.Owner(),
+		actListSessions:  auth.Owner(),
+	}
+}
+
 func (s *AuthService) List(act auth.Actor, req user.ListRequest) (user.ListResponse, error) {
-	if err := service.Authorize(permStrictChief, act); err != nil {
+	if err := service.Allow(s.policy, actList, act); err != nil {
 		return user.ListResponse{}, err
 	}
 
@@ -36,15 +94,10 @@ func (s *AuthService) List(act auth.Actor, req user.ListRequest) (user.ListRespo
 }
 
 func (s *AuthService) Get(act auth.Actor, req user.GetRequest) (user.Response, error) {
-	if act.User() == req.UUID {
-		goto Do
-	}
-
-	if err := service.Authorize(permStrictChief, act); err != nil {
+	if err := service.AllowOn(s.policy, actGet, act, auth.Resource{Owner: req.UUID}); err != nil {
 		return user.Response{}, err
 	}
 
-Do:
 	return s.service.Get(act, req)
 }
 
@@ -54,69 +107,298 @@ func (s *AuthService) GetBySIAPE(act auth.Actor, req user.GetBySIAPERequest) (us
 		return user.Response{}, err
 	}
 
-	if act.User() == res.UUID {
-		goto Do
-	}
-
-	if err := service.Authorize(permStrictChief, act); err != nil {
+	if err := service.AllowOn(s.policy, actGet, act, auth.Resource{Owner: res.UUID}); err != nil {
 		return user.Response{}, err
 	}
 
-Do:
 	return res, nil
 }
 
 func (s *AuthService) Create(act auth.Actor, req user.CreateRequest) (user.Response, error) {
-	if err := service.Authorize(permStrictChief, act); err != nil {
+	if err := service.Allow(s.policy, actCreate, act); err != nil {
 		return user.Response{}, err
 	}
 
+	role, ok := auth.FromString(req.Role)
+	if !ok || !role.IsValid() {
+		return user.Response{}, xerrors.ErrRoleInvalid
+	}
+
+	if !s.hierarchy(role, act.Role()) {
+		return user.Response{}, xerrors.ErrUnpriviledUserPromotion.New(act.Role(), role)
+	}
+
 	return s.service.Create(act, req)
 }
 
-// TODO: Patch has serious security ploblems, rationalize and break them into smaller use cases
-func (s *AuthService) Patch(act auth.Actor, req user.PatchRequest) (user.Response, error) {
-	if r, ok := req.Role.Unwrap(); ok {
-		role, ok := auth.FromString(r)
-		if !ok {
-			goto Continue
-		}
+func (s *AuthService) UpdateProfile(act auth.Actor, req user.UpdateProfileRequest) (user.Response, error) {
+	if err := service.AllowOn(s.policy, actUpdateProfile, act, auth.Resource{Owner: req.UUID}); err != nil {
+		return user.Response{}, err
+	}
+
+	return s.service.UpdateProfile(act, req)
+}
+
+func (s *AuthService) ChangePassword(act auth.Actor, req user.ChangePasswordRequest) (user.Response, error) {
+	if err := service.Allow(s.policy, actChangePassword, act); err != nil {
+		return user.Response{}, err
+	}
+
+	if act.User() != req.UUID {
+		return user.Response{}, xerrors.ErrChangeOthersPassword
+	}
+
+	return s.service.ChangePassword(act, req)
+}
+
+func (s *AuthService) ResetPassword(act auth.Actor, req user.ResetPasswordRequest) (user.Response, error) {
+	if err := service.Allow(s.policy, actResetPassword, act); err != nil {
+		return user.Response{}, err
+	}
+
+	if act.User() == req.UUID {
+		return user.Response{}, xerrors.ErrResetOwnPassword
+	}
+
+	return s.service.ResetPassword(act, req)
+}
+
+func (s *AuthService) ChangeRole(act auth.Actor, req user.ChangeRoleRequest) (user.Response, error) {
+	if err := service.Allow(s.policy, actChangeRole, act); err != nil {
+		return user.Response{}, err
+	}
+
+	if act.User() == req.UUID {
+		return user.Response{}, xerrors.ErrChangeOwnRole
+	}
+
+	role, ok := auth.FromString(req.Role)
+	if !ok || !role.IsValid() {
+		return user.Response{}, xerrors.ErrRoleInvalid
+	}
+
+	if !s.hierarchy(role, act.Role()) {
+		return user.Response{}, xerrors.ErrUnpriviledUserPromotion.New(act.Role(), role)
+	}
+
+	// nor may users be demoted by whoever they outrank
+	target, err := s.service.Get(act, user.GetRequest{UUID: req.UUID})
+	if err != nil {
+		return user.Response{}, err
+	}
+
+	current, _ := auth.FromString(target.Role)
+	if !s.hierarchy(current, act.Role()) {
+		return user.Response{}, xerrors.ErrUnpriviledUserPromotion.New(act.Role(), current)
+	}
+
+	return s.service.ChangeRole(act, req)
+}
+
+func (s *AuthService) Import(act auth.Actor, req user.ImportRequest) (user.ImportResponse, error) {
+	if err := service.Allow(s.policy, actImport, act); err != nil {
+		return user.ImportResponse{}, err
+	}
 
+	req.Grant = func(role auth.Role) error {
 		if !s.hierarchy(role, act.Role()) {
-			return user.Response{}, xerrors.ErrUnpriviledUserPromotion.New(act.Role(), role)
+			return xerrors.ErrUnpriviledUserPromotion.New(act.Role(), role)
 		}
+
+		return nil
 	}
 
-Continue:
-	if act.User() == req.UUID {
-		goto Do
+	return s.service.Import(act, req)
+}
+
+func (s *AuthService) Deactivate(act auth.Actor, req user.DeactivateRequest) error {
+	if err := service.AllowOn(s.policy, actDeactivate, act, auth.Resource{Owner: req.UUID}); err != nil {
+		return err
 	}
 
-	if err := service.Authorize(permStrictChief, act); err != nil {
+	return s.service.Deactivate(act, req)
+}
+
+func (s *AuthService) Reactivate(act auth.Actor, req user.ReactivateRequest) (user.Response, error) {
+	if err := service.Allow(s.policy, actReactivate, act); err != nil {
 		return user.Response{}, err
 	}
 
-Do:
-	return s.service.Patch(act, req)
+	return s.service.Reactivate(act, req)
+}
+
+func (s *AuthService) Purge(act auth.Actor, req user.PurgeRequest) error {
+	if err := service.Allow(s.policy, actPurge, act); err != nil {
+		return err
+	}
+
+	return s.service.Purge(act, req)
 }
 
-func (s *AuthService) Delete(act auth.Actor, req user.DeleteRequest) error {
+func (s *AuthService) Unlock(act auth.Actor, req user.UnlockRequest) error {
+	if err := service.Allow(s.policy, actUnlock, act); err != nil {
+		return err
+	}
+
+	return s.service.Unlock(act, req)
+}
+
+func (s *AuthService) ListSessions(act auth.Actor, req user.ListSessionsRequest) (user.SessionsResponse, error) {
+	if err := service.AllowOn(s.policy, actListSessions, act, auth.Resource{Owner: req.User}); err != nil {
+		return user.SessionsResponse{}, err
+	}
+
+	return s.service.ListSessions(act, req)
+}
+
+func (s *AuthService) RevokeSession(act auth.Actor, req user.RevokeSessionRequest) error {
+	if err := service.Allow(s.policy, actRevokeSession, act); err != nil {
+		return err
+	}
+
+	if act.User() != req.User {
+		return xerrors.ErrSessionNotFound
+	}
+
+	return s.service.RevokeSession(act, req)
+}
+
+func (s *AuthService) RevokeSessions(act auth.Actor, req user.RevokeSessionsRequest) error {
+	if err := service.Allow(s.policy, actRevokeSessions, act); err != nil {
+		return err
+	}
+
+	return s.service.RevokeSessions(act, req)
+}
+
+func (s *AuthService) GetTwoFactor(act auth.Actor, req user.GetTwoFactorRequest) (user.TwoFactorResponse, error) {
+	if err := service.Allow(s.policy, actTwoFactor, act); err != nil {
+		return user.TwoFactorResponse{}, err
+	}
+
+	if act.User() != req.UUID {
+		return user.TwoFactorResponse{}, xerrors.ErrTwoFactorNotEnrolled
+	}
+
+	return s.service.GetTwoFactor(act, req)
+}
+
+func (s *AuthService) EnrollTwoFactor(act auth.Actor, req user.EnrollTwoFactorRequest) (user.EnrollTwoFactorResponse, error) {
+	if err := service.Allow(s.policy, actTwoFactor, act); err != nil {
+		return user.EnrollTwoFactorResponse{}, err
+	}
+
+	if act.User() != req.UUID {
+		return user.EnrollTwoFactorResponse{}, xerrors.ErrTwoFactorNotEnrolled
+	}
+
+	return s.service.EnrollTwoFactor(act, req)
+}
+
+func (s *AuthService) ConfirmTwoFactor(act auth.Actor, req user.ConfirmTwoFactorRequest) (user.RecoveryCodesResponse, error) {
+	if err := service.Allow(s.policy, actTwoFactor, act); err != nil {
+		return user.RecoveryCodesResponse{}, err
+	}
+
+	if act.User() != req.UUID {
+		return user.RecoveryCodesResponse{}, xerrors.ErrTwoFactorNotEnrolled
+	}
+
+	return s.service.ConfirmTwoFactor(act, req)
+}
+
+func (s *AuthService) DisableTwoFactor(act auth.Actor, req user.DisableTwoFactorRequest) error {
+	if err := service.Allow(s.policy, actTwoFactor, act); err != nil {
+		return err
+	}
+
+	if act.User() != req.UUID {
+		return xerrors.ErrTwoFactorNotEnrolled
+	}
+
+	return s.service.DisableTwoFactor(act, req)
+}
+
+func (s *AuthService) ResetTwoFactor(act auth.Actor, req user.ResetTwoFactorRequest) error {
+	if err := service.Allow(s.policy, actResetTwoFactor, act); err != nil {
+		return err
+	}
+
 	if act.User() == req.UUID {
-		goto Do
+		return xerrors.ErrResetOwnTwoFactor
+	}
+
+	return s.service.ResetTwoFactor(act, req)
+}
+
+func (s *AuthService) ListTokens(act auth.Actor, req user.ListTokensRequest) (user.TokensResponse, error) {
+	if err := service.Allow(s.policy, actManageTokens, act); err != nil {
+		return user.TokensResponse{}, err
+	}
+
+	if act.User() != req.User {
+		return user.TokensResponse{}, xerrors.ErrTokenNotFound
+	}
+
+	return s.service.ListTokens(act, req)
+}
+
+func (s *AuthService) CreateToken(act auth.Actor, req user.CreateTokenRequest) (user.CreateTokenResponse, error) {
+	if err := service.Allow(s.policy, actManageTokens, act); err != nil {
+		return user.CreateTokenResponse{}, err
+	}
+
+	if act.User() != req.User {
+		return user.CreateTokenResponse{}, xerrors.ErrTokenNotFound
 	}
 
-	if err := service.Authorize(permStrictChief, act); err != nil {
+	return s.service.CreateToken(act, req)
+}
+
+func (s *AuthService) RevokeToken(act auth.Actor, req user.RevokeTokenRequest) error {
+	if err := service.Allow(s.policy, actManageTokens, act); err != nil {
 		return err
 	}
 
-Do:
-	return s.service.Delete(act, req)
+	if act.User() != req.User {
+		return xerrors.ErrTokenNotFound
+	}
+
+	return s.service.RevokeToken(act, req)
 }
 
 func (s *AuthService) Authenticate(req user.AuthRequest) (user.AuthResponse, error) {
 	return s.service.Authenticate(req)
 }
 
+func (s *AuthService) AuthenticateTwoFactor(req user.AuthTwoFactorRequest) (user.AuthResponse, error) {
+	return s.service.AuthenticateTwoFactor(req)
+}
+
+func (s *AuthService) EnrollByChallenge(req user.EnrollByChallengeRequest) (user.EnrollTwoFactorResponse, error) {
+	return s.service.EnrollByChallenge(req)
+}
+
+func (s *AuthService) BeginSSO(req user.BeginSSORequest) (user.BeginSSOResponse, error) {
+	return s.service.BeginSSO(req)
+}
+
+func (s *AuthService) FinishSSO(req user.FinishSSORequest) (user.AuthResponse, error) {
+	return s.service.FinishSSO(req)
+}
+
+func (s *AuthService) Logout(req user.LogoutRequest) error {
+	return s.service.Logout(req)
+}
+
+func (s *AuthService) ForgotPassword(req user.ForgotPasswordRequest) error {
+	return s.service.ForgotPassword(req)
+}
+
+func (s *AuthService) RecoverPassword(req user.RecoverPasswordRequest) (user.Response, error) {
+	return s.service.RecoverPassword(req)
+}
+
 func (s *AuthService) Actor(req user.ActorRequest) (auth.Actor, error) {
 	return s.service.Actor(req)
 }
@@ -1,27 +1,81 @@
 package userserve
 
 import (
+	"strconv"
+
 	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/domain/apitoken"
+	"github.com/alan-b-lima/almodon/internal/domain/recovery"
 	"github.com/alan-b-lima/almodon/internal/domain/session"
+	"github.com/alan-b-lima/almodon/internal/domain/twofactor"
 	"github.com/alan-b-lima/almodon/internal/domain/user"
+	"github.com/alan-b-lima/almodon/internal/notify"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
 	"github.com/alan-b-lima/almodon/pkg/errors"
 	"github.com/alan-b-lima/almodon/pkg/opt"
 )
 
 type Service struct {
-	Repo     user.Repository
-	Sessions session.Repository
+	Repo      user.Repository
+	Sessions  session.Repository
+	Policy    session.Policy
+	Tokens    recovery.Repository
+	APITokens apitoken.Repository
+	Factors   twofactor.Repository
+	TwoFactor twofactor.Policy
+	Provider  user.Provider
+	SSO       user.SSO
+	Notifier  notify.Notifier
+	Guard     user.Guard
+	Passwords user.PasswordPolicy
 }
 
-func NewService(users user.Repository, sessions session.Repository) user.Service {
+func NewService(users user.Repository, sessions session.Repository, policy session.Policy, tokens recovery.Repository, apiTokens apitoken.Repository, factors twofactor.Repository, required twofactor.Policy, provider user.Provider, sso user.SSO, notifier notify.Notifier, guard user.Guard, passwords user.PasswordPolicy) user.Service {
 	return &Service{
-		Repo:     users,
-		Sessions: sessions,
+		Repo:      users,
+		Sessions:  sessions,
+		Policy:    policy,
+		Tokens:    tokens,
+		APITokens: apiTokens,
+		Factors:   factors,
+		TwoFactor: required,
+		Provider:  provider,
+		SSO:       sso,
+		Notifier:  notifier,
+		Guard:     guard,
+		Passwords: passwords,
 	}
 }
 
 func (s *Service) List(act auth.Actor, req user.ListRequest) (user.ListResponse, error) {
-	res, err := user.List(s.Repo, req.Offset, req.Limit)
+	var filter user.Filter
+
+	if req.Role != "" {
+		role, ok := auth.FromString(req.Role)
+		if !ok {
+			return user.ListResponse{}, xerrors.ErrRoleInvalid
+		}
+
+		filter.Role = opt.Some(role)
+	}
+
+	if req.Active != "" {
+		active, err := strconv.ParseBool(req.Active)
+		if err != nil {
+			return user.ListResponse{}, xerrors.ErrActiveInvalid
+		}
+
+		filter.Active = opt.Some(active)
+	}
+
+	filter.Search = req.Search
+
+	sort, err := user.ParseSort(req.Sort)
+	if err != nil {
+		return user.ListResponse{}, err
+	}
+
+	res, err := user.List(s.Repo, filter, sort, req.Offset, req.Limit)
 	if err != nil {
 		return user.ListResponse{}, err
 	}
@@ -60,10 +114,12 @@ func (s *Service) GetBySIAPE(act auth.Actor, req user.GetBySIAPERequest) (user.R
 func (s *Service) Create(act auth.Actor, req user.CreateRequest) (user.Response, error) {
 	role, ok := auth.FromString(req.Role)
 	if !ok {
-		return user.Response{}, &errors.Error{}
+		return user.Response{}, xerrors.ErrRoleInvalid
 	}
 
-	res, err := user.Create(s.Repo, req.SIAPE, req.Name, req.Email, req.Password, role)
+	ids := user.Identities{SIAPE: req.SIAPE, Matricula: req.Matricula, CPF: req.CPF}
+
+	res, err := user.Create(s.Repo, s.Passwords, ids, req.Name, req.Email, req.Password, role)
 	if err != nil {
 		return user.Response{}, err
 	}
@@ -71,15 +127,80 @@ func (s *Service) Create(act auth.Actor, req user.CreateRequest) (user.Response,
 	return transform(&res), nil
 }
 
-func (s *Service) Patch(act auth.Actor, req user.PatchRequest) (user.Response, error) {
-	var role opt.Opt[auth.Role]
-	if val, ok := req.Role.Unwrap(); ok {
-		if l, ok := auth.FromString(val); ok {
-			role = opt.Some(l)
+func (s *Service) UpdateProfile(act auth.Actor, req user.UpdateProfileRequest) (user.Response, error) {
+	res, err := user.UpdateProfile(s.Repo, req.UUID, req.Name, req.Email)
+	if err != nil {
+		return user.Response{}, err
+	}
+
+	return transform(&res), nil
+}
+
+func (s *Service) ChangePassword(act auth.Actor, req user.ChangePasswordRequest) (user.Response, error) {
+	res, err := user.ChangePassword(s.Repo, s.Passwords, req.UUID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		return user.Response{}, err
+	}
+
+	return transform(&res), nil
+}
+
+func (s *Service) ResetPassword(act auth.Actor, req user.ResetPasswordRequest) (user.Response, error) {
+	res, err := user.ResetPassword(s.Repo, s.Passwords, req.UUID, req.Password)
+	if err != nil {
+		return user.Response{}, err
+	}
+
+	return transform(&res), nil
+}
+
+func (s *Service) ChangeRole(act auth.Actor, req user.ChangeRoleRequest) (user.Response, error) {
+	role, ok := auth.FromString(req.Role)
+	if !ok {
+		return user.Response{}, xerrors.ErrRoleInvalid
+	}
+
+	res, err := user.ChangeRole(s.Repo, req.UUID, role)
+	if err != nil {
+		return user.Response{}, err
+	}
+
+	return transform(&res), nil
+}
+
+func (s *Service) Import(act auth.Actor, req user.ImportRequest) (user.ImportResponse, error) {
+	creds, err := user.ParseCredentials(req.Credentials)
+	if err != nil {
+		return user.ImportResponse{}, err
+	}
+
+	res, err := user.Import(s.Repo, s.Tokens, s.Notifier, req.Body, creds, req.Grant, req.DryRun)
+	if err != nil {
+		return user.ImportResponse{}, err
+	}
+
+	ires := user.ImportResponse{
+		DryRun:  res.DryRun,
+		Records: make([]user.ImportedResponse, len(res.Records)),
+	}
+	for i, e := range res.Records {
+		ires.Records[i] = user.ImportedResponse{
+			Line:     e.Line,
+			Response: transform(&e.Entity),
+			Password: e.Password,
+			Notified: e.Notified,
 		}
 	}
 
-	res, err := user.Patch(s.Repo, req.UUID, req.Name, req.Email, req.Password, role)
+	return ires, nil
+}
+
+func (s *Service) Deactivate(act auth.Actor, req user.DeactivateRequest) error {
+	return user.Deactivate(s.Repo, s.Sessions, req.UUID)
+}
+
+func (s *Service) Reactivate(act auth.Actor, req user.ReactivateRequest) (user.Response, error) {
+	res, err := user.Reactivate(s.Repo, req.UUID)
 	if err != nil {
 		return user.Response{}, err
 	}
@@ -87,12 +208,138 @@ func (s *Service) Patch(act auth.Actor, req user.PatchRequest) (user.Response, e
 	return transform(&res), nil
 }
 
-func (s *Service) Delete(act auth.Actor, req user.DeleteRequest) error {
-	return user.Delete(s.Repo, req.UUID)
+func (s *Service) Purge(act auth.Actor, req user.PurgeRequest) error {
+	return user.Purge(s.Repo, s.Sessions, s.APITokens, s.Tokens, s.Factors, req.UUID)
+}
+
+func (s *Service) Unlock(act auth.Actor, req user.UnlockRequest) error {
+	return user.Unlock(s.Repo, s.Guard, req.UUID)
+}
+
+func (s *Service) ListSessions(act auth.Actor, req user.ListSessionsRequest) (user.SessionsResponse, error) {
+	res, err := session.ListByUser(s.Sessions, req.User)
+	if err != nil {
+		return user.SessionsResponse{}, err
+	}
+
+	lres := user.SessionsResponse{Records: make([]user.SessionResponse, len(res))}
+	for i, e := range res {
+		lres.Records[i] = user.SessionResponse{
+			UUID:      e.UUID,
+			UserAgent: e.UserAgent,
+			Address:   e.Address,
+			Created:   e.Created,
+			LastSeen:  e.LastSeen,
+			Expires:   e.Expires,
+			Current:   e.UUID == req.Current,
+		}
+	}
+
+	return lres, nil
+}
+
+func (s *Service) RevokeSession(act auth.Actor, req user.RevokeSessionRequest) error {
+	return session.Revoke(s.Sessions, req.User, req.Session)
+}
+
+func (s *Service) RevokeSessions(act auth.Actor, req user.RevokeSessionsRequest) error {
+	if _, err := user.Get(s.Repo, req.UUID); err != nil {
+		return err
+	}
+
+	return session.DeleteByUser(s.Sessions, req.UUID)
+}
+
+func (s *Service) GetTwoFactor(act auth.Actor, req user.GetTwoFactorRequest) (user.TwoFactorResponse, error) {
+	ures, err := user.Get(s.Repo, req.UUID)
+	if err != nil {
+		return user.TwoFactorResponse{}, err
+	}
+
+	tres := user.TwoFactorResponse{Required: s.TwoFactor.Requires(ures.Role)}
+
+	res, err := twofactor.Get(s.Factors, req.UUID)
+	if err, ok := errors.AsType[*errors.Error](err); ok && err.Kind == errors.NotFound {
+		return tres, nil
+	}
+	if err != nil {
+		return user.TwoFactorResponse{}, err
+	}
+
+	tres.Enrolled = res.Confirmed
+	tres.Pending = !res.Confirmed
+	tres.RecoveryLeft = res.RecoveryLeft
+	return tres, nil
+}
+
+func (s *Service) EnrollTwoFactor(act auth.Actor, req user.EnrollTwoFactorRequest) (user.EnrollTwoFactorResponse, error) {
+	res, err := user.EnrollTwoFactor(s.Repo, s.Factors, req.UUID)
+	if err != nil {
+		return user.EnrollTwoFactorResponse{}, err
+	}
+
+	return user.EnrollTwoFactorResponse{Secret: res.Secret, URI: res.URI}, nil
+}
+
+func (s *Service) ConfirmTwoFactor(act auth.Actor, req user.ConfirmTwoFactorRequest) (user.RecoveryCodesResponse, error) {
+	codes, err := twofactor.Confirm(s.Factors, req.UUID, req.Code)
+	if err != nil {
+		return user.RecoveryCodesResponse{}, err
+	}
+
+	return user.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (s *Service) DisableTwoFactor(act auth.Actor, req user.DisableTwoFactorRequest) error {
+	return user.DisableTwoFactor(s.Repo, s.Factors, s.TwoFactor, req.UUID, req.Code)
+}
+
+func (s *Service) ResetTwoFactor(act auth.Actor, req user.ResetTwoFactorRequest) error {
+	if _, err := user.Get(s.Repo, req.UUID); err != nil {
+		return err
+	}
+
+	return twofactor.Delete(s.Factors, req.UUID)
+}
+
+func (s *Service) ListTokens(act auth.Actor, req user.ListTokensRequest) (user.TokensResponse, error) {
+	res, err := apitoken.ListByUser(s.APITokens, req.User)
+	if err != nil {
+		return user.TokensResponse{}, err
+	}
+
+	lres := user.TokensResponse{Records: make([]user.TokenResponse, len(res))}
+	for i := range res {
+		lres.Records[i] = transformToken(&res[i])
+	}
+
+	return lres, nil
+}
+
+func (s *Service) CreateToken(act auth.Actor, req user.CreateTokenRequest) (user.CreateTokenResponse, error) {
+	scope, err := apitoken.ParseScope(req.Scopes)
+	if err != nil {
+		return user.CreateTokenResponse{}, err
+	}
+
+	res, err := apitoken.Create(s.APITokens, req.User, req.Name, scope, req.Expires)
+	if err != nil {
+		return user.CreateTokenResponse{}, err
+	}
+
+	cres := user.CreateTokenResponse{
+		TokenResponse: transformToken(&res.Entity),
+		Secret:        res.Secret,
+	}
+	return cres, nil
+}
+
+func (s *Service) RevokeToken(act auth.Actor, req user.RevokeTokenRequest) error {
+	return apitoken.Revoke(s.APITokens, req.User, req.Token)
 }
 
 func (s *Service) Authenticate(req user.AuthRequest) (user.AuthResponse, error) {
-	res, err := user.Authenticate(s.Repo, s.Sessions, req.SIAPE, req.Password)
+	res, err := user.Authenticate(s.Repo, s.Sessions, s.Factors, s.Guard, s.Provider, s.Policy, s.TwoFactor, req.Login, req.Password, req.Address, req.UserAgent)
 	if err != nil {
 		return user.AuthResponse{}, err
 	}
@@ -100,24 +347,100 @@ func (s *Service) Authenticate(req user.AuthRequest) (user.AuthResponse, error)
 	return user.AuthResponse(res), nil
 }
 
+func (s *Service) AuthenticateTwoFactor(req user.AuthTwoFactorRequest) (user.AuthResponse, error) {
+	res, err := user.AuthenticateTwoFactor(s.Repo, s.Sessions, s.Factors, s.Guard, s.Policy, req.Challenge, req.Code, req.Address, req.UserAgent)
+	if err != nil {
+		return user.AuthResponse{}, err
+	}
+
+	return user.AuthResponse(res), nil
+}
+
+func (s *Service) EnrollByChallenge(req user.EnrollByChallengeRequest) (user.EnrollTwoFactorResponse, error) {
+	res, err := user.EnrollByChallenge(s.Repo, s.Factors, req.Challenge)
+	if err != nil {
+		return user.EnrollTwoFactorResponse{}, err
+	}
+
+	return user.EnrollTwoFactorResponse{Secret: res.Secret, URI: res.URI}, nil
+}
+
+func (s *Service) BeginSSO(req user.BeginSSORequest) (user.BeginSSOResponse, error) {
+	url, state, err := user.BeginSSO(s.SSO)
+	if err != nil {
+		return user.BeginSSOResponse{}, err
+	}
+
+	return user.BeginSSOResponse{URL: url, State: state}, nil
+}
+
+func (s *Service) FinishSSO(req user.FinishSSORequest) (user.AuthResponse, error) {
+	res, err := user.FinishSSO(s.Repo, s.Sessions, s.Factors, s.SSO, s.Policy, s.TwoFactor, req.State, req.Code, req.Address, req.UserAgent)
+	if err != nil {
+		return user.AuthResponse{}, err
+	}
+
+	return user.AuthResponse(res), nil
+}
+
+func (s *Service) Logout(req user.LogoutRequest) error {
+	return session.Delete(s.Sessions, req.Session)
+}
+
+func (s *Service) ForgotPassword(req user.ForgotPasswordRequest) error {
+	return user.ForgotPassword(s.Repo, s.Tokens, s.Notifier, s.Guard, req.Login)
+}
+
+func (s *Service) RecoverPassword(req user.RecoverPasswordRequest) (user.Response, error) {
+	res, err := user.RecoverPassword(s.Repo, s.Tokens, s.Sessions, s.Passwords, req.Token, req.Password)
+	if err != nil {
+		return user.Response{}, err
+	}
+
+	return transform(&res), nil
+}
+
 func (s *Service) Actor(req user.ActorRequest) (auth.Actor, error) {
+	if req.Token != "" {
+		return user.ActorByToken(s.Repo, s.APITokens, req.Token, req.Scope)
+	}
+
 	return user.Actor(s.Repo, s.Sessions, req.Session)
 }
 
 func transform(e *user.Entity) user.Response {
 	return user.Response{
-		UUID:  e.UUID,
-		SIAPE: e.SIAPE,
-		Name:  e.Name,
-		Email: e.Email,
-		Role:  e.Role.String(),
+		UUID:      e.UUID,
+		SIAPE:     e.SIAPE,
+		Matricula: e.Matricula,
+		CPF:       e.CPF,
+		Name:      e.Name,
+		Email:     e.Email,
+		Role:      e.Role.String(),
+		Active:    e.Active,
+		Created:   e.Created,
 	}
 }
 
 func transformP(r *user.Response, e *user.Entity) {
 	r.UUID = e.UUID
 	r.SIAPE = e.SIAPE
+	r.Matricula = e.Matricula
+	r.CPF = e.CPF
 	r.Name = e.Name
 	r.Email = e.Email
 	r.Role = e.Role.String()
+	r.Active = e.Active
+	r.Created = e.Created
+}
+
+func transformToken(e *apitoken.Entity) user.TokenResponse {
+	return user.TokenResponse{
+		UUID:     e.UUID,
+		Name:     e.Name,
+		Scopes:   e.Scope.Strings(),
+		Created:  e.Created,
+		Expires:  e.Expires,
+		LastUsed: e.LastUsed,
+	}
 }
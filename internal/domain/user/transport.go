@@ -1,16 +1,26 @@
 package user
 
 import (
+	"io"
 	"time"
 
+	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/domain/apitoken"
 	"github.com/alan-b-lima/almodon/pkg/opt"
 	"github.com/alan-b-lima/almodon/pkg/uuid"
 )
 
 type (
+	// ListRequest filters by role, by whether users are active, given
+	// as true or false, and by a search on names and emails. Sort is
+	// one of name, siape or created, reversed if prefixed by a minus.
 	ListRequest struct {
-		Offset int `query:"offset"`
-		Limit  int `query:"limit"`
+		Role   string `query:"role"`
+		Active string `query:"active"`
+		Search string `query:"q"`
+		Sort   string `query:"sort"`
+		Offset int    `query:"offset"`
+		Limit  int    `query:"limit"`
 	}
 
 	GetRequest struct {
@@ -22,32 +32,167 @@ type (
 	}
 
 	CreateRequest struct {
-		SIAPE    int    `json:"siape"`
-		Name     string `json:"name"`
-		Email    string `json:"email"`
-		Password string `json:"password"`
-		Role     string `json:"role"`
+		SIAPE     int    `json:"siape"`
+		Matricula string `json:"matricula"`
+		CPF       string `json:"cpf"`
+		Name      string `json:"name"`
+		Email     string `json:"email"`
+		Password  string `json:"password"`
+		Role      string `json:"role"`
+	}
+
+	UpdateProfileRequest struct {
+		UUID  uuid.UUID       `json:"-"`
+		Name  opt.Opt[string] `json:"name"`
+		Email opt.Opt[string] `json:"email"`
+	}
+
+	ChangePasswordRequest struct {
+		UUID            uuid.UUID `json:"-"`
+		CurrentPassword string    `json:"current_password"`
+		NewPassword     string    `json:"new_password"`
+	}
+
+	ResetPasswordRequest struct {
+		UUID     uuid.UUID `json:"-"`
+		Password string    `json:"password"`
+	}
+
+	ChangeRoleRequest struct {
+		UUID uuid.UUID `json:"-"`
+		Role string    `json:"role"`
+	}
+
+	// ImportRequest carries a CSV file of users. Credentials is either
+	// password, for generated passwords to be handed back, or reset,
+	// for users to be sent recovery tokens instead. Grant, if set,
+	// rejects the roles the importer cannot grant.
+	ImportRequest struct {
+		Body        io.Reader             `json:"-"`
+		DryRun      bool                  `query:"dry_run"`
+		Credentials string                `query:"credentials"`
+		Grant       func(auth.Role) error `json:"-"`
+	}
+
+	DeactivateRequest struct {
+		UUID uuid.UUID `json:"-"`
+	}
+
+	ReactivateRequest struct {
+		UUID uuid.UUID `json:"-"`
+	}
+
+	PurgeRequest struct {
+		UUID uuid.UUID `json:"-"`
+	}
+
+	UnlockRequest struct {
+		UUID uuid.UUID `json:"-"`
+	}
+
+	ListSessionsRequest struct {
+		User    uuid.UUID `json:"-"`
+		Current uuid.UUID `json:"-"`
+	}
+
+	RevokeSessionRequest struct {
+		User    uuid.UUID `json:"-"`
+		Session uuid.UUID `json:"-"`
+	}
+
+	RevokeSessionsRequest struct {
+		UUID uuid.UUID `json:"-"`
+	}
+
+	GetTwoFactorRequest struct {
+		UUID uuid.UUID `json:"-"`
+	}
+
+	EnrollTwoFactorRequest struct {
+		UUID uuid.UUID `json:"-"`
 	}
 
-	PatchRequest struct {
-		UUID     uuid.UUID       `json:"-"`
-		Name     opt.Opt[string] `json:"name"`
-		Email    opt.Opt[string] `json:"email"`
-		Password opt.Opt[string] `json:"password"`
-		Role     opt.Opt[string] `json:"role"`
+	ConfirmTwoFactorRequest struct {
+		UUID uuid.UUID `json:"-"`
+		Code string    `json:"code"`
 	}
 
-	DeleteRequest struct {
+	DisableTwoFactorRequest struct {
 		UUID uuid.UUID `json:"-"`
+		Code string    `json:"code"`
+	}
+
+	ResetTwoFactorRequest struct {
+		UUID uuid.UUID `json:"-"`
+	}
+
+	ListTokensRequest struct {
+		User uuid.UUID `json:"-"`
+	}
+
+	CreateTokenRequest struct {
+		User    uuid.UUID `json:"-"`
+		Name    string    `json:"name"`
+		Scopes  []string  `json:"scopes"`
+		Expires time.Time `json:"expires"`
+	}
+
+	RevokeTokenRequest struct {
+		User  uuid.UUID `json:"-"`
+		Token uuid.UUID `json:"-"`
 	}
 
 	AuthRequest struct {
-		SIAPE    int    `json:"siape"`
+		Login     string `json:"login"`
+		Password  string `json:"password"`
+		Address   string `json:"-"`
+		UserAgent string `json:"-"`
+		Request   string `json:"-"`
+	}
+
+	AuthTwoFactorRequest struct {
+		Challenge string `json:"challenge"`
+		Code      string `json:"code"`
+		Address   string `json:"-"`
+		UserAgent string `json:"-"`
+		Request   string `json:"-"`
+	}
+
+	EnrollByChallengeRequest struct {
+		Challenge string `json:"challenge"`
+	}
+
+	BeginSSORequest struct{}
+
+	FinishSSORequest struct {
+		State     string `json:"-"`
+		Code      string `json:"-"`
+		Address   string `json:"-"`
+		UserAgent string `json:"-"`
+		Request   string `json:"-"`
+	}
+
+	LogoutRequest struct {
+		Session uuid.UUID `json:"-"`
+		Address string    `json:"-"`
+		Request string    `json:"-"`
+	}
+
+	ForgotPasswordRequest struct {
+		Login string `json:"login"`
+	}
+
+	RecoverPasswordRequest struct {
+		Token    string `json:"token"`
 		Password string `json:"password"`
+		Address  string `json:"-"`
+		Request  string `json:"-"`
 	}
 
 	ActorRequest struct {
-		Session uuid.UUID `json:"-"`
+		Session uuid.UUID      `json:"-"`
+		Token   string         `json:"-"`
+		Scope   apitoken.Scope `json:"-"`
 	}
 )
 
@@ -60,16 +205,91 @@ type (
 	}
 
 	Response struct {
-		UUID  uuid.UUID `json:"uuid"`
-		SIAPE int       `json:"siape"`
-		Name  string    `json:"name"`
-		Email string    `json:"email"`
-		Role  string    `json:"role"`
+		UUID      uuid.UUID `json:"uuid"`
+		SIAPE     int       `json:"siape,omitempty"`
+		Matricula string    `json:"matricula,omitempty"`
+		CPF       string    `json:"cpf,omitempty"`
+		Name      string    `json:"name"`
+		Email     string    `json:"email"`
+		Role      string    `json:"role"`
+		Active    bool      `json:"active"`
+		Created   time.Time `json:"created"`
+	}
+
+	ImportResponse struct {
+		DryRun  bool               `json:"dry_run"`
+		Records []ImportedResponse `json:"records"`
 	}
 
+	ImportedResponse struct {
+		Line int `json:"line"`
+		Response
+		Password string `json:"password,omitempty"`
+		Notified bool   `json:"notified,omitempty"`
+	}
+
+	SessionsResponse struct {
+		Records []SessionResponse `json:"records"`
+	}
+
+	SessionResponse struct {
+		UUID      uuid.UUID `json:"uuid"`
+		UserAgent string    `json:"user_agent"`
+		Address   string    `json:"address"`
+		Created   time.Time `json:"created"`
+		LastSeen  time.Time `json:"last_seen"`
+		Expires   time.Time `json:"expires"`
+		Current   bool      `json:"current"`
+	}
+
+	TokensResponse struct {
+		Records []TokenResponse `json:"records"`
+	}
+
+	TokenResponse struct {
+		UUID     uuid.UUID `json:"uuid"`
+		Name     string    `json:"name"`
+		Scopes   []string  `json:"scopes"`
+		Created  time.Time `json:"created"`
+		Expires  time.Time `json:"expires"`
+		LastUsed time.Time `json:"last_used,omitzero"`
+	}
+
+	CreateTokenResponse struct {
+		TokenResponse
+		Secret string `json:"secret"`
+	}
+
+	TwoFactorResponse struct {
+		Enrolled     bool `json:"enrolled"`
+		Pending      bool `json:"pending"`
+		Required     bool `json:"required"`
+		RecoveryLeft int  `json:"recovery_left"`
+	}
+
+	EnrollTwoFactorResponse struct {
+		Secret string `json:"secret"`
+		URI    string `json:"uri"`
+	}
+
+	BeginSSOResponse struct {
+		URL   string `json:"url"`
+		State string `json:"-"`
+	}
+
+	RecoveryCodesResponse struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+
+	// AuthResponse is either a session or, if a second factor is due,
+	// a challenge to be answered with it.
 	AuthResponse struct {
-		UUID    uuid.UUID `json:"uuid"`
-		User    uuid.UUID `json:"user"`
+		UUID    uuid.UUID `json:"uuid,omitzero"`
+		User    uuid.UUID `json:"user,omitzero"`
 		Expires time.Time `json:"expires"`
+
+		Challenge     string   `json:"challenge,omitempty"`
+		Enroll        bool     `json:"enroll,omitempty"`
+		RecoveryCodes []string `json:"recovery_codes,omitempty"`
 	}
 )
@@ -0,0 +1,176 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+
+	"github.com/alan-b-lima/almodon/internal/support/resource"
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	uuidpkg "github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+const (
+	CSRFCookieName = "csrf"
+	CSRFHeaderName = "X-CSRF-Token"
+)
+
+// CSRF guards cookie authenticated requests against cross-site request
+// forgery.
+//
+// Unsafe requests are refused if the browser tells, through either
+// Sec-Fetch-Site or Origin, that they were issued by another site.
+// Those carrying a session cookie must also echo the CSRF cookie in
+// the X-CSRF-Token header, the token being a MAC of the session, so
+// it cannot be planted by someone without the key.
+type CSRF struct {
+	key []byte
+}
+
+// NewCSRF creates a CSRF guard with a random key, tokens do not
+// survive a restart, just as sessions do not.
+func NewCSRF() *CSRF {
+	key := make([]byte, sha256.Size)
+	rand.Read(key)
+
+	return &CSRF{key: key}
+}
+
+// Token returns the CSRF token bound to the session.
+func (c *CSRF) Token(session uuidpkg.UUID) string {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(session[:])
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (c *CSRF) verify(session uuidpkg.UUID, token string) bool {
+	return hmac.Equal([]byte(token), []byte(c.Token(session)))
+}
+
+// Protect enforces the CSRF defense on the unsafe methods of the
+// handler, and issues the CSRF cookie along with every session cookie
+// the handler sets.
+func (c *CSRF) Protect(handler http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !resource.IsSafeMethod(r.Method) {
+			if err := c.check(r); err != nil {
+				resource.WriteJsonError(w, err)
+				return
+			}
+		}
+
+		// sessions predating the CSRF cookie get one on any request
+		if session, err := resource.SessionCookie(resource.SessionCookieName, r); err == nil {
+			if cookie, err := r.Cookie(CSRFCookieName); err != nil || !c.verify(session, cookie.Value) {
+				c.setCookie(w, session, &http.Cookie{})
+			}
+		}
+
+		cw := &csrfWriter{ResponseWriter: w, csrf: c}
+		handler.ServeHTTP(cw, r)
+		cw.issue()
+	}
+}
+
+func (c *CSRF) check(r *http.Request) error {
+	switch r.Header.Get("Sec-Fetch-Site") {
+	case "", "same-origin", "none":
+	default:
+		return xerrors.ErrCrossSiteRequest
+	}
+
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || u.Host != r.Host {
+			return xerrors.ErrCrossSiteRequest
+		}
+	}
+
+	session, err := resource.SessionCookie(resource.SessionCookieName, r)
+	if err != nil {
+		// not authenticated by cookie, nothing to forge
+		return nil
+	}
+
+	cookie, err := r.Cookie(CSRFCookieName)
+	if err != nil || r.Header.Get(CSRFHeaderName) != cookie.Value || !c.verify(session, cookie.Value) {
+		return xerrors.ErrCSRFToken
+	}
+
+	return nil
+}
+
+// setCookie sets the CSRF cookie of the session, lasting as long as the
+// given session cookie. It is readable by scripts, so they can echo it.
+func (c *CSRF) setCookie(w http.ResponseWriter, session uuidpkg.UUID, sc *http.Cookie) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     CSRFCookieName,
+		Value:    c.Token(session),
+		Expires:  sc.Expires,
+		Path:     "/",
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (c *CSRF) clearCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     CSRFCookieName,
+		Value:    "",
+		MaxAge:   -1,
+		Path:     "/",
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// csrfWriter follows the session cookie set in the response, right
+// before the header is written, with the matching CSRF cookie.
+type csrfWriter struct {
+	http.ResponseWriter
+	csrf   *CSRF
+	issued bool
+}
+
+func (w *csrfWriter) WriteHeader(status int) {
+	w.issue()
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *csrfWriter) Write(buf []byte) (int, error) {
+	w.issue()
+	return w.ResponseWriter.Write(buf)
+}
+
+func (w *csrfWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+func (w *csrfWriter) issue() {
+	if w.issued {
+		return
+	}
+	w.issued = true
+
+	var session *http.Cookie
+	for _, line := range w.Header().Values("Set-Cookie") {
+		cookie, err := http.ParseSetCookie(line)
+		if err == nil && cookie.Name == resource.SessionCookieName {
+			session = cookie
+		}
+	}
+
+	if session == nil {
+		return
+	}
+
+	uuid, err := uuidpkg.FromString(session.Value)
+	if session.MaxAge < 0 || err != nil {
+		w.csrf.clearCookie(w.ResponseWriter)
+		return
+	}
+
+	w.csrf.setCookie(w.ResponseWriter, uuid, session)
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/alan-b-lima/almodon/internal/support/resource"
+	"github.com/alan-b-lima/almodon/pkg/uuid"
+)
+
+// RequestIDHeader carries the ID of a request, both ways.
+const RequestIDHeader = "X-Request-Id"
+
+// maxRequestID bounds the IDs taken from clients.
+const maxRequestID = 64
+
+// RequestID gives each request an ID, echoed in the response, so what
+// the request did can be traced back to it. The ID given by the
+// client, or by a proxy in front of the server, is kept if it is
+// short and plain enough to be logged as is, otherwise a new one is
+// generated.
+func RequestID(handler http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !isPlainID(id) {
+			id = uuid.NewUUIDv7().String()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		handler.ServeHTTP(w, resource.WithRequestID(r, id))
+	}
+}
+
+func isPlainID(id string) bool {
+	if id == "" || len(id) > maxRequestID {
+		return false
+	}
+
+	for _, c := range []byte(id) {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '-' || c == '_' || c == '.':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
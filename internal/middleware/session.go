@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/alan-b-lima/almodon/internal/domain/session"
+	"github.com/alan-b-lima/almodon/internal/support/resource"
+)
+
+type renewer interface {
+	session.Getter
+	session.Updater
+}
+
+// RenewSession slides the expiry of the session of each request as
+// the policy allows, reissuing the session cookie with the new expiry
+// whenever it is renewed. Requests without a valid session pass
+// through untouched, it is up to the handler to refuse them.
+func RenewSession(sessions renewer, policy session.Policy, handler http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		uuid, err := resource.SessionCookie(resource.SessionCookieName, r)
+		if err == nil {
+			s, renewed, err := session.Renew(sessions, policy, uuid)
+			if err == nil && renewed {
+				resource.SetSessionCookie(w, s.UUID, s.Expires)
+			}
+		}
+
+		handler.ServeHTTP(w, r)
+	}
+}
@@ -0,0 +1,132 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// SMTP is a transport that delivers messages to an SMTP server.
+type SMTP struct {
+	addr string
+	from mail.Address
+	auth smtp.Auth
+}
+
+// NewSMTP creates a transport that delivers messages through the SMTP
+// server at addr, as in "host:port", sent by from. The auth may be
+// nil for servers that require no authentication.
+func NewSMTP(addr string, from mail.Address, auth smtp.Auth) *SMTP {
+	return &SMTP{addr: addr, from: from, auth: auth}
+}
+
+// Send implements the [Transport] interface.
+func (t *SMTP) Send(msg Message) error {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return err
+	}
+
+	host, _, err := net.SplitHostPort(t.addr)
+	if err != nil {
+		return err
+	}
+
+	data, err := compose(t.from, *to, host, msg)
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(t.addr, t.auth, t.from.Address, []string{to.Address}, data)
+}
+
+// Maildir is a transport that writes each message as a file in a
+// maildir, so messages can be inspected with any mail client during
+// development.
+type Maildir struct {
+	dir  string
+	from mail.Address
+}
+
+// NewMaildir creates a transport that writes to the maildir at dir,
+// creating it if needed.
+func NewMaildir(dir string, from mail.Address) (*Maildir, error) {
+	for _, sub := range [...]string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o700); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Maildir{dir: dir, from: from}, nil
+}
+
+var maildirCount atomic.Uint64
+
+// Send implements the [Transport] interface. The message is written
+// to tmp and then moved to new, as the maildir format requires.
+func (t *Maildir) Send(msg Message) error {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return err
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		host = "localhost"
+	}
+
+	data, err := compose(t.from, *to, host, msg)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d.P%dQ%d.%s", time.Now().Unix(), os.Getpid(), maildirCount.Add(1), strings.ReplaceAll(host, "/", "_"))
+
+	tmp := filepath.Join(t.dir, "tmp", name)
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, filepath.Join(t.dir, "new", name))
+}
+
+// compose formats a message as a plain text, UTF-8, quoted-printable
+// encoded RFC 5322 message.
+func compose(from, to mail.Address, host string, msg Message) ([]byte, error) {
+	var id [16]byte
+	rand.Read(id[:])
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from.String())
+	fmt.Fprintf(&b, "To: %s\r\n", to.String())
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(id[:]), host)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	b.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&b)
+	body := strings.ReplaceAll(msg.Body, "\r\n", "\n")
+	if _, err := qp.Write([]byte(strings.ReplaceAll(body, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
@@ -0,0 +1,48 @@
+// Package notify defines how the system reaches users outside of the
+// HTTP response, such as by email.
+package notify
+
+// Message is a notification addressed to a user.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notifier accepts messages for delivery. Implementations may deliver
+// them right away or enqueue them for later delivery.
+type Notifier interface {
+	Notify(msg Message) error
+}
+
+// Transport delivers a message right away, to a mail server or to
+// some other medium.
+type Transport interface {
+	Send(msg Message) error
+}
+
+type printer interface {
+	Printf(format string, v ...any)
+}
+
+// Log is a transport that only prints the messages, meant for
+// development.
+type Log struct{ l printer }
+
+// NewLog creates a transport that prints messages to the given
+// logger.
+func NewLog(l printer) *Log {
+	return &Log{l: l}
+}
+
+// Send implements the [Transport] interface.
+func (t *Log) Send(msg Message) error {
+	t.l.Printf("to: %s, subject: %s\n%s\n", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// Notify implements the [Notifier] interface, by sending the message
+// right away.
+func (t *Log) Notify(msg Message) error {
+	return t.Send(msg)
+}
@@ -0,0 +1,263 @@
+package notify_test
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	outboxrepo "github.com/alan-b-lima/almodon/internal/domain/outbox/repository"
+	. "github.com/alan-b-lima/almodon/internal/notify"
+)
+
+var from = mail.Address{Name: "Almodon", Address: "almodon@localhost"}
+
+var message = Message{
+	To:      "alan-lima.al@ufvjm.edu.br",
+	Subject: "Redefinição de senha",
+	Body:    "Olá.\nSeu código é: 1234\n",
+}
+
+// smtpStandIn is a minimal SMTP server that accepts every message.
+type smtpStandIn struct {
+	ln net.Listener
+
+	mu    sync.Mutex
+	rcpts []string
+	data  []string
+}
+
+func newSMTPStandIn(t *testing.T) *smtpStandIn {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &smtpStandIn{ln: ln}
+	go s.serve()
+	t.Cleanup(func() { ln.Close() })
+
+	return s
+}
+
+func (s *smtpStandIn) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.handle(conn)
+	}
+}
+
+func (s *smtpStandIn) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP stand-in")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.mu.Lock()
+			s.rcpts = append(s.rcpts, strings.TrimSpace(line[len("RCPT TO:"):]))
+			s.mu.Unlock()
+			reply("250 OK")
+
+		case cmd == "DATA":
+			reply("354 end with <CRLF>.<CRLF>")
+
+			var b strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				b.WriteString(line)
+			}
+
+			s.mu.Lock()
+			s.data = append(s.data, b.String())
+			s.mu.Unlock()
+			reply("250 OK")
+
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTPTransport(t *testing.T) {
+	srv := newSMTPStandIn(t)
+
+	if err := NewSMTP(srv.ln.Addr().String(), from, nil).Send(message); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	if len(srv.data) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(srv.data))
+	}
+
+	if srv.rcpts[0] != "<"+message.To+">" {
+		t.Errorf("unexpected recipient %s", srv.rcpts[0])
+	}
+
+	checkMessage(t, srv.data[0])
+}
+
+func TestMaildirTransport(t *testing.T) {
+	dir := t.TempDir()
+
+	md, err := NewMaildir(dir, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := md.Send(message); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := os.ReadDir(filepath.Join(dir, "new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expected 1 message in new, got %d", len(files))
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "new", files[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkMessage(t, string(data))
+}
+
+func checkMessage(t *testing.T, data string) {
+	msg, err := mail.ReadMessage(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != message.Subject {
+		t.Errorf("expected subject %q, got %q", message.Subject, subject)
+	}
+
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.ReplaceAll(string(body), "\r\n", "\n"); got != message.Body {
+		t.Errorf("expected body %q, got %q", message.Body, got)
+	}
+}
+
+type flaky struct {
+	mu       sync.Mutex
+	failures int
+	sent     []Message
+	done     chan struct{}
+}
+
+func (f *flaky) Send(msg Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.failures > 0 {
+		f.failures--
+		return errors.New("transport unavailable")
+	}
+
+	f.sent = append(f.sent, msg)
+	close(f.done)
+	return nil
+}
+
+func TestOutboxDelivers(t *testing.T) {
+	transport := &flaky{done: make(chan struct{})}
+
+	o := NewOutbox(outboxrepo.NewMap(), transport, nil)
+	defer o.Close()
+
+	if err := o.Notify(message); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-transport.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("message was not delivered")
+	}
+
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+
+	if len(transport.sent) != 1 || transport.sent[0] != message {
+		t.Errorf("unexpected deliveries %v", transport.sent)
+	}
+}
+
+func TestOutboxRetries(t *testing.T) {
+	repo := outboxrepo.NewMap()
+	transport := &flaky{failures: 1, done: make(chan struct{})}
+
+	o := NewOutbox(repo, transport, nil)
+	defer o.Close()
+
+	if err := o.Notify(message); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		next, ok, err := repo.NextDue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok && next.After(time.Now()) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("failed delivery was not rescheduled")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if due, _ := repo.Due(time.Now(), 10); len(due) != 0 {
+		t.Errorf("rescheduled message should not be due yet")
+	}
+}
@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/domain/outbox"
+)
+
+const _DispatchBatch = 32
+
+// Outbox is a notifier that persists messages in an outbox repository
+// and delivers them in the background through a transport, retrying
+// failed deliveries with exponential backoff.
+type Outbox struct {
+	repo      outbox.Repository
+	transport Transport
+	log       printer
+
+	wake   chan struct{}
+	cancel chan struct{}
+	done   chan struct{}
+}
+
+// NewOutbox creates an outbox and starts its dispatcher. Delivery
+// failures are reported to log, which may be nil.
+func NewOutbox(repo outbox.Repository, transport Transport, log printer) *Outbox {
+	o := Outbox{
+		repo:      repo,
+		transport: transport,
+		log:       log,
+		wake:      make(chan struct{}, 1),
+		cancel:    make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	go o.run()
+
+	return &o
+}
+
+// Notify implements the [Notifier] interface, the message is only
+// enqueued, and delivered as soon as possible.
+func (o *Outbox) Notify(msg Message) error {
+	if _, err := outbox.Enqueue(o.repo, msg.To, msg.Subject, msg.Body); err != nil {
+		return err
+	}
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// Close stops the dispatcher, messages still pending remain in the
+// repository.
+func (o *Outbox) Close() {
+	close(o.cancel)
+	<-o.done
+}
+
+func (o *Outbox) run() {
+	defer close(o.done)
+
+	for {
+		o.dispatch()
+
+		var after <-chan time.Time
+		if next, ok, err := outbox.NextDue(o.repo); err == nil && ok {
+			after = time.After(time.Until(next))
+		}
+
+		select {
+		case <-o.cancel:
+			return
+
+		case <-o.wake:
+		case <-after:
+		}
+	}
+}
+
+func (o *Outbox) dispatch() {
+	for {
+		due, err := outbox.Due(o.repo, time.Now(), _DispatchBatch)
+		if err != nil {
+			o.printf("outbox: %v\n", err)
+			return
+		}
+
+		for _, e := range due {
+			o.deliver(&e)
+		}
+
+		if len(due) < _DispatchBatch {
+			return
+		}
+	}
+}
+
+func (o *Outbox) deliver(e *outbox.Entity) {
+	msg := Message{To: e.To, Subject: e.Subject, Body: e.Body}
+
+	if err := o.transport.Send(msg); err != nil {
+		res, rerr := outbox.Retry(o.repo, e.UUID, err, time.Now())
+		if rerr != nil {
+			o.printf("outbox: %v\n", rerr)
+			return
+		}
+
+		o.printf("outbox: delivery of %v to %s failed (attempt %d, %v): %v\n", res.UUID, res.To, res.Attempts, res.Status, err)
+		return
+	}
+
+	if _, err := outbox.Deliver(o.repo, e.UUID); err != nil {
+		o.printf("outbox: %v\n", err)
+	}
+}
+
+func (o *Outbox) printf(format string, v ...any) {
+	if o.log != nil {
+		o.log.Printf(format, v...)
+	}
+}
@@ -0,0 +1,68 @@
+package report
+
+import (
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+)
+
+// Period is a half-open time interval, [From, To), over which a
+// report is produced.
+type Period struct {
+	From time.Time
+	To   time.Time
+}
+
+// Month returns the period of the month that contains t.
+func Month(t time.Time) Period {
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return Period{From: from, To: from.AddDate(0, 1, 0)}
+}
+
+// ParsePeriod parses a period either as a month, as in "2025-09", or
+// as a pair of dates, as in "2025-09-01" and "2025-09-15", in which
+// case the last day is included. Dates are taken in the given
+// location.
+func ParsePeriod(month, from, to string, loc *time.Location) (Period, error) {
+	if month != "" {
+		t, err := time.ParseInLocation("2006-01", month, loc)
+		if err != nil {
+			return Period{}, xerrors.ErrReportPeriod
+		}
+
+		return Month(t), nil
+	}
+
+	f, err := time.ParseInLocation(time.DateOnly, from, loc)
+	if err != nil {
+		return Period{}, xerrors.ErrReportPeriod
+	}
+
+	t, err := time.ParseInLocation(time.DateOnly, to, loc)
+	if err != nil {
+		return Period{}, xerrors.ErrReportPeriod
+	}
+
+	p := Period{From: f, To: t.AddDate(0, 0, 1)}
+	if !p.From.Before(p.To) {
+		return Period{}, xerrors.ErrReportPeriod
+	}
+
+	return p, nil
+}
+
+// Contains returns whether t is within the period.
+func (p Period) Contains(t time.Time) bool {
+	return !t.Before(p.From) && t.Before(p.To)
+}
+
+// String returns the period as it would be parsed, a month, as in
+// "2025-09", if it is one, or else its first and last days, as in
+// "2025-09-01_2025-09-15", suitable for file names.
+func (p Period) String() string {
+	if m := Month(p.From); m.From.Equal(p.From) && m.To.Equal(p.To) {
+		return p.From.Format("2006-01")
+	}
+
+	return p.From.Format(time.DateOnly) + "_" + p.To.AddDate(0, 0, -1).Format(time.DateOnly)
+}
@@ -0,0 +1,83 @@
+package report_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/alan-b-lima/almodon/internal/support/report"
+)
+
+func TestParsePeriod(t *testing.T) {
+	loc := time.FixedZone("BRT", -3*60*60)
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, loc) }
+
+	tests := []struct {
+		month, from, to string
+		want            Period
+		ok              bool
+	}{
+		{"2025-09", "", "", Period{date(2025, 9, 1), date(2025, 10, 1)}, true},
+		{"2025-12", "", "", Period{date(2025, 12, 1), date(2026, 1, 1)}, true},
+		{"2025-09", "2025-01-01", "2025-01-31", Period{date(2025, 9, 1), date(2025, 10, 1)}, true},
+		{"", "2025-09-01", "2025-09-15", Period{date(2025, 9, 1), date(2025, 9, 16)}, true},
+		{"", "2025-09-15", "2025-09-15", Period{date(2025, 9, 15), date(2025, 9, 16)}, true},
+		{"", "2025-09-16", "2025-09-15", Period{}, false},
+		{"2025-13", "", "", Period{}, false},
+		{"09/2025", "", "", Period{}, false},
+		{"", "2025-09-01", "", Period{}, false},
+		{"", "", "2025-09-15", Period{}, false},
+		{"", "", "", Period{}, false},
+	}
+
+	for i, test := range tests {
+		got, err := ParsePeriod(test.month, test.from, test.to, loc)
+		if (err == nil) != test.ok {
+			t.Errorf("test %d: ParsePeriod() error = %v, expected ok %v", i, err, test.ok)
+			continue
+		}
+
+		if !got.From.Equal(test.want.From) || !got.To.Equal(test.want.To) {
+			t.Errorf("test %d: ParsePeriod() = [%v, %v), expected [%v, %v)", i, got.From, got.To, test.want.From, test.want.To)
+		}
+	}
+}
+
+func TestPeriodString(t *testing.T) {
+	tests := map[[3]string]string{
+		{"2025-09", "", ""}:              "2025-09",
+		{"", "2025-09-01", "2025-09-30"}: "2025-09",
+		{"", "2025-09-01", "2025-09-15"}: "2025-09-01_2025-09-15",
+		{"", "2025-12-31", "2025-12-31"}: "2025-12-31_2025-12-31",
+	}
+
+	for in, want := range tests {
+		p, err := ParsePeriod(in[0], in[1], in[2], time.UTC)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := p.String(); got != want {
+			t.Errorf("String() of %v = %q, expected %q", in, got, want)
+		}
+	}
+}
+
+func TestPeriodContains(t *testing.T) {
+	p := Month(time.Date(2025, time.September, 17, 10, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		t    time.Time
+		want bool
+	}{
+		{time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC), true},
+		{time.Date(2025, time.September, 30, 23, 59, 59, 0, time.UTC), true},
+		{time.Date(2025, time.August, 31, 23, 59, 59, 0, time.UTC), false},
+		{time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, test := range tests {
+		if got := p.Contains(test.t); got != test.want {
+			t.Errorf("Contains(%v) = %v, expected %v", test.t, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+// Package report implements the encoding of tabular reports, which
+// are written row by row, so that reports over large periods never
+// have to be loaded in memory all at once.
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+	"time"
+
+	"github.com/alan-b-lima/almodon/internal/xerrors"
+	"github.com/alan-b-lima/almodon/pkg/xlsx"
+)
+
+// Format is a file format a report can be exported as.
+type Format uint8
+
+const (
+	CSV Format = iota
+	XLSX
+)
+
+// FormatFromString returns the Format of the given name.
+func FormatFromString(str string) (Format, error) {
+	switch str {
+	case "", "csv":
+		return CSV, nil
+	case "xlsx":
+		return XLSX, nil
+	}
+
+	return 0, xerrors.ErrReportFormat.New(str)
+}
+
+// ContentType returns the media type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case XLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+
+	return "text/csv; charset=utf-8"
+}
+
+// Extension returns the file extension of the format, with the dot.
+func (f Format) Extension() string {
+	switch f {
+	case XLSX:
+		return ".xlsx"
+	}
+
+	return ".csv"
+}
+
+// Encoder writes the rows of a report in a certain format.
+type Encoder interface {
+	WriteRow(cells ...any) error
+	Close() error
+}
+
+// NewEncoder creates an encoder of the given format that writes to
+// w. The name is used where the format supports it, as the sheet
+// name of a spreadsheet.
+func NewEncoder(format Format, name string, w io.Writer) (Encoder, error) {
+	switch format {
+	case CSV:
+		return &csvEncoder{w: csv.NewWriter(w)}, nil
+
+	case XLSX:
+		enc, err := xlsx.NewWriter(w, name)
+		if err != nil {
+			return nil, err
+		}
+
+		return &xlsxEncoder{w: enc}, nil
+	}
+
+	return nil, xerrors.ErrReportFormat.New(format)
+}
+
+// Rows is a sequence of report rows, a non-nil error stops the
+// encoding.
+type Rows = iter.Seq2[[]any, error]
+
+// Write encodes the header and then every row of the sequence, the
+// encoder is closed afterwards.
+func Write(enc Encoder, header []string, rows Rows) error {
+	cells := make([]any, len(header))
+	for i, h := range header {
+		cells[i] = h
+	}
+
+	if err := enc.WriteRow(cells...); err != nil {
+		return err
+	}
+
+	for row, err := range rows {
+		if err != nil {
+			return err
+		}
+
+		if err := enc.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+
+	return enc.Close()
+}
+
+type csvEncoder struct {
+	w   *csv.Writer
+	buf []string
+}
+
+func (e *csvEncoder) WriteRow(cells ...any) error {
+	e.buf = e.buf[:0]
+	for _, cell := range cells {
+		e.buf = append(e.buf, csvCell(cell))
+	}
+
+	return e.w.Write(e.buf)
+}
+
+func (e *csvEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func csvCell(cell any) string {
+	switch v := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		return escape(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case fmt.Stringer:
+		return escape(v.String())
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+
+	return fmt.Sprint(cell)
+}
+
+type xlsxEncoder struct {
+	w   *xlsx.Writer
+	buf []any
+}
+
+func (e *xlsxEncoder) WriteRow(cells ...any) error {
+	e.buf = e.buf[:0]
+	for _, cell := range cells {
+		e.buf = append(e.buf, xlsxCell(cell))
+	}
+
+	return e.w.WriteRow(e.buf...)
+}
+
+func (e *xlsxEncoder) Close() error {
+	return e.w.Close()
+}
+
+func xlsxCell(cell any) any {
+	switch v := cell.(type) {
+	case string:
+		return escape(v)
+	case time.Time:
+		return v
+	case fmt.Stringer:
+		return escape(v.String())
+	}
+
+	return cell
+}
+
+// escape keeps text from being taken as a formula by spreadsheets, by
+// prefixing an apostrophe to text that starts with a character
+// formulas may start with. Numbers are not text and are written as
+// they are, negative or not.
+func escape(text string) string {
+	if text == "" {
+		return text
+	}
+
+	switch text[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + text
+	}
+
+	return text
+}
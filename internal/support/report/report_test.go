@@ -0,0 +1,150 @@
+package report_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	. "github.com/alan-b-lima/almodon/internal/support/report"
+)
+
+// label is a text value that is not a string.
+type label string
+
+func (l label) String() string { return string(l) }
+
+func rows(rows ...[]any) Rows {
+	return func(yield func([]any, error) bool) {
+		for _, row := range rows {
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+}
+
+var (
+	header = []string{"item", "quantity", "note", "date"}
+	day    = time.Date(2025, time.September, 1, 12, 0, 0, 0, time.UTC)
+	body   = rows(
+		[]any{"Luva", 12, "", day},
+		[]any{"=HYPERLINK(\"http://x\")", -3, "+55 38", day},
+		[]any{label("@SUM(A1)"), 2.5, "-1", nil},
+		[]any{"a=b", -0.5, "\tcmd", nil},
+	)
+)
+
+func TestCSV(t *testing.T) {
+	var buf bytes.Buffer
+
+	enc, err := NewEncoder(CSV, "consumo", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Write(enc, header, body); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		header,
+		{"Luva", "12", "", "2025-09-01T12:00:00Z"},
+		{"'=HYPERLINK(\"http://x\")", "-3", "'+55 38", "2025-09-01T12:00:00Z"},
+		{"'@SUM(A1)", "2.5", "'-1", ""},
+		{"a=b", "-0.5", "'\tcmd", ""},
+	}
+
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("CSV = %q, expected %q", got, want)
+	}
+}
+
+type sheet struct {
+	Rows []struct {
+		Cells []struct {
+			T      string `xml:"t,attr"`
+			V      string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func TestXLSX(t *testing.T) {
+	var buf bytes.Buffer
+
+	enc, err := NewEncoder(XLSX, "consumo", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Write(enc, header, body); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := zr.Open("xl/worksheets/sheet1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s sheet
+	if err := xml.Unmarshal(content, &s); err != nil {
+		t.Fatal(err)
+	}
+
+	var got [][]string
+	for _, row := range s.Rows {
+		var cells []string
+		for _, c := range row.Cells {
+			if c.T == "inlineStr" {
+				cells = append(cells, c.Inline)
+			} else {
+				cells = append(cells, c.V)
+			}
+		}
+		got = append(got, cells)
+	}
+
+	want := [][]string{
+		header,
+		{"Luva", "12", "", "45901.5"},
+		{"'=HYPERLINK(\"http://x\")", "-3", "'+55 38", "45901.5"},
+		{"'@SUM(A1)", "2.5", "'-1"},
+		{"a=b", "-0.5", "'\tcmd"},
+	}
+
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("XLSX = %q, expected %q", got, want)
+	}
+}
+
+func TestFormatFromString(t *testing.T) {
+	for _, name := range []string{"", "csv", "xlsx"} {
+		if _, err := FormatFromString(name); err != nil {
+			t.Errorf("FormatFromString(%q) = %v, expected a format", name, err)
+		}
+	}
+
+	if _, err := FormatFromString("pdf"); err == nil {
+		t.Error("FormatFromString(\"pdf\") should fail")
+	}
+}
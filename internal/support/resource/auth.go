@@ -1,9 +1,14 @@
 package resource
 
 import (
+	"context"
+	"net"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/alan-b-lima/almodon/internal/auth"
+	"github.com/alan-b-lima/almodon/internal/domain/apitoken"
 	"github.com/alan-b-lima/almodon/internal/domain/user"
 	"github.com/alan-b-lima/almodon/internal/xerrors"
 	"github.com/alan-b-lima/almodon/pkg/errors"
@@ -26,23 +31,137 @@ func SessionCookie(cookie string, r *http.Request) (uuidpkg.UUID, error) {
 	return uuid, nil
 }
 
+// SetSessionCookie sets the session cookie, to expire along with the
+// session.
+func SetSessionCookie(w http.ResponseWriter, session uuidpkg.UUID, expires time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    session.String(),
+		Expires:  expires,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// ClearSessionCookie instructs the client to discard the session
+// cookie.
+func ClearSessionCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookieName,
+		Value:    "",
+		MaxAge:   -1,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// BearerToken returns the token of the Authorization header, if it
+// uses the Bearer scheme.
+func BearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// IsSafeMethod returns whether the method is meant not to change
+// anything on the server.
+func IsSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+
+	return false
+}
+
+// ClientAddress returns the IP address of the client, without the
+// port. Forwarding headers are not trusted.
+func ClientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of the request carrying its ID, as the
+// RequestID middleware gives it.
+func WithRequestID(r *http.Request, id string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id))
+}
+
+// RequestID returns the ID of the request, or the empty string if it
+// was not given one.
+func RequestID(r *http.Request) string {
+	id, _ := r.Context().Value(requestIDKey{}).(string)
+	return id
+}
+
+// Origin returns where the request comes from, as recorded along the
+// changes it makes.
+func Origin(r *http.Request) auth.Origin {
+	return auth.Origin{Addr: ClientAddress(r), Request: RequestID(r)}
+}
+
 type actoer interface {
 	Actor(user.ActorRequest) (auth.Actor, error)
 }
 
+// Session resolves the actor behind the request. API tokens, given as
+// bearer tokens, take precedence over the session cookie, and unlike
+// it, an invalid token is an error rather than an unlogged actor, so
+// scripts fail loudly.
 func Session(rc actoer, r *http.Request) (auth.Actor, error) {
+	if secret, ok := BearerToken(r); ok {
+		scope := apitoken.Write
+		if IsSafeMethod(r.Method) {
+			scope = apitoken.Read
+		}
+
+		act, err := rc.Actor(user.ActorRequest{Token: secret, Scope: scope})
+		return act.From(Origin(r)), err
+	}
+
+	return cookieSession(rc, r)
+}
+
+// CookieSession is like [Session], for operations that must not be
+// performed with API tokens: managing the tokens themselves, lest a
+// leaked token renews itself, changing credentials and sessions, and
+// administering users, as tokens skip two-factor authentication.
+// Requests bearing a token are refused.
+func CookieSession(rc actoer, r *http.Request) (auth.Actor, error) {
+	if _, ok := BearerToken(r); ok {
+		return auth.NewUnlogged(), xerrors.ErrTokenSession
+	}
+
+	return cookieSession(rc, r)
+}
+
+func cookieSession(rc actoer, r *http.Request) (auth.Actor, error) {
 	session, err := SessionCookie(SessionCookieName, r)
 	if err != nil {
-		return auth.NewUnlogged(), nil
+		return auth.NewUnlogged().From(Origin(r)), nil
 	}
 
 	act, err := rc.Actor(user.ActorRequest{Session: session})
 	if err, ok := errors.AsType[*errors.Error](err); ok && err.Kind.IsClient() {
-		return auth.NewUnlogged(), nil
+		return auth.NewUnlogged().From(Origin(r)), nil
 	}
 	if err != nil {
 		return auth.NewUnlogged(), err
 	}
 
-	return act, err
+	return act.From(Origin(r)), nil
 }
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"regexp"
+	"strconv"
 	"sync"
 
 	"github.com/alan-b-lima/almodon/internal/xerrors"
@@ -17,6 +18,10 @@ func WriteJsonError(w http.ResponseWriter, err error) {
 		return
 	}
 
+	if ra, ok := errors.AsType[xerrors.RetryAfter](err); ok {
+		w.Header().Set("Retry-After", strconv.Itoa(ra.Seconds()))
+	}
+
 	if err, ok := errors.AsType[*errors.Error](err); ok {
 		writeJsonError(w, err, toHTTPStatus(err.Kind))
 		return
@@ -45,6 +50,7 @@ var statusCodes = map[errors.Kind]int{
 	errors.NotFound:           http.StatusNotFound,
 	errors.Conflict:           http.StatusConflict,
 	errors.Timeout:            http.StatusRequestTimeout,
+	errors.TooManyRequests:    http.StatusTooManyRequests,
 
 	errors.Internal:    http.StatusInternalServerError,
 	errors.Unavailable: http.StatusServiceUnavailable,
@@ -90,6 +96,25 @@ func DecodeJSON(req any, r *http.Request) error {
 	return nil
 }
 
+var reContentTypeTextCSV = regexp.MustCompile(`^\s*text/csv\s*(;.*)?\s*$`)
+
+const _MaxCSVBodySize = 32 << 20
+
+// CSVBody returns the body of the request, given it is a CSV file,
+// limited to a sensible size.
+func CSVBody(w http.ResponseWriter, r *http.Request) (io.Reader, error) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return nil, xerrors.ErrNoContentType
+	}
+
+	if !reContentTypeTextCSV.MatchString(contentType) {
+		return nil, xerrors.ErrUnsupportedContentTypeCSV
+	}
+
+	return http.MaxBytesReader(w, r.Body, _MaxCSVBodySize), nil
+}
+
 var bufPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
 
 func EncodeJSON(res any, status int, w http.ResponseWriter, r *http.Request) error {
@@ -45,6 +45,14 @@ func queryParams(q url.Values, v any) error {
 		case reflect.String:
 			rv.Field(i).SetString(val)
 
+		case reflect.Bool:
+			b, err := strconv.ParseBool(val)
+			if err != nil {
+				return fmt.Errorf("query: not convertible to a bool: %w", err)
+			}
+
+			rv.Field(i).SetBool(b)
+
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 			num, err := strconv.ParseInt(val, 10, int(t.Size())*8)
 			if err != nil {
@@ -0,0 +1,29 @@
+package resource
+
+import (
+	"mime"
+	"net/http"
+
+	"github.com/alan-b-lima/almodon/internal/support/report"
+)
+
+// WriteReport streams a report as a file download, named after name
+// and the format extension. The encoder is created before any header
+// is written, so that a report that cannot be encoded is still
+// reported as an error. Since the rows are written as they are
+// produced, errors found after the first row cannot be reported to
+// the client and result in a truncated file.
+func WriteReport(w http.ResponseWriter, format report.Format, name string, header []string, rows report.Rows) error {
+	enc, err := report.NewEncoder(format, name, w)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": name + format.Extension(),
+	}))
+	w.WriteHeader(http.StatusOK)
+
+	return report.Write(enc, header, rows)
+}
@@ -0,0 +1,28 @@
+package resource_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alan-b-lima/almodon/internal/support/report"
+	. "github.com/alan-b-lima/almodon/internal/support/resource"
+)
+
+func TestWriteReportUnknownFormat(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	err := WriteReport(w, report.Format(255), "consumption", []string{"item"}, nil)
+	if err == nil {
+		t.Fatal("unknown formats should fail")
+	}
+
+	if w.Header().Get("Content-Disposition") != "" || w.Body.Len() != 0 {
+		t.Errorf("nothing should be written before the encoder is created, got %v %q", w.Header(), w.Body)
+	}
+
+	WriteJsonError(w, err)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected the error to be written as 400, got %d", w.Code)
+	}
+}
@@ -12,3 +12,19 @@ func Authorize(auth auth.Permission, act auth.Actor) error {
 
 	return nil
 }
+
+// Allow checks whether the actor may perform the action under the
+// policy.
+func Allow(policy *auth.Policy, action auth.Action, act auth.Actor) error {
+	return Authorize(policy.Permission(action), act)
+}
+
+// AllowOn checks whether the actor may perform the action on the
+// resource under the policy.
+func AllowOn(policy *auth.Policy, action auth.Action, act auth.Actor, res auth.Resource) error {
+	if !policy.Check(action, act, res) {
+		return xerrors.ErrUnauthorizedUser.New(act.Role(), policy.Permission(action))
+	}
+
+	return nil
+}
@@ -4,30 +4,183 @@ import "github.com/alan-b-lima/almodon/pkg/errors"
 
 var (
 	ErrSessionTooLong = errors.Fmt(errors.InvalidInput, "session-too-long", "session must not last longer than %v")
+	ErrSessionPolicy  = errors.Fmt(errors.InvalidInput, "session-policy", "invalid session policy: %s")
 
 	ErrSessionNotFound = errors.New(errors.NotFound, "session-not-found", "session not found", nil)
 )
 
+var (
+	ErrCrossSiteRequest = errors.New(errors.Forbidden, "cross-site-request", "request was issued from another site", nil)
+	ErrCSRFToken        = errors.New(errors.Forbidden, "csrf-token", "CSRF token is missing or does not match the session", nil)
+)
+
+var (
+	ErrRecoveryTokenTooLong = errors.Fmt(errors.InvalidInput, "recovery-token-too-long", "recovery token must not last longer than %v")
+	ErrRecoveryTokenInvalid = errors.New(errors.Unauthorized, "recovery-token-invalid", "recovery token is invalid, expired or was already used", nil)
+	ErrRecoveryNotify       = errors.Imp(errors.Unavailable, "recovery-notify", "recovery token could not be delivered")
+)
+
+var (
+	ErrTwoFactorNotEnrolled = errors.New(errors.NotFound, "two-factor-not-enrolled", "user is not enrolled in two-factor authentication", nil)
+	ErrTwoFactorEnrolled    = errors.New(errors.Conflict, "two-factor-enrolled", "user is already enrolled in two-factor authentication", nil)
+	ErrTwoFactorRequired    = errors.New(errors.Forbidden, "two-factor-required", "two-factor authentication is required for the role of the user", nil)
+	ErrTwoFactorCodeInvalid = errors.New(errors.Unauthorized, "two-factor-code-invalid", "two-factor code is invalid or was already used", nil)
+
+	ErrAuthChallengeInvalid = errors.New(errors.Unauthorized, "auth-challenge-invalid", "login challenge is invalid or expired, log in again", nil)
+	ErrAuthChallengeEnroll  = errors.New(errors.Conflict, "auth-challenge-enroll", "login challenge does not allow enrolling in two-factor authentication", nil)
+)
+
+var (
+	ErrTokenCreation = errors.Imp(errors.InvalidInput, "token-creation", "given data does not satisfy the API token type")
+
+	ErrTokenNameEmpty    = errors.New(errors.InvalidInput, "token-name-empty", "token name cannot be empty", nil)
+	ErrTokenNameTooLong  = errors.Fmt(errors.InvalidInput, "token-name-too-long", "token name must not be longer than %d characters")
+	ErrTokenScopeEmpty   = errors.New(errors.InvalidInput, "token-scope-empty", "token must have at least one scope", nil)
+	ErrTokenScopeInvalid = errors.Fmt(errors.InvalidInput, "token-scope-invalid", "token scope %q does not exist, use read or write")
+	ErrTokenLifetime     = errors.Fmt(errors.InvalidInput, "token-lifetime", "token must expire in the future, and within %v")
+
+	ErrTokenInvalid  = errors.New(errors.Unauthorized, "token-invalid", "API token is invalid, expired or was revoked", nil)
+	ErrTokenScope    = errors.New(errors.Forbidden, "token-scope", "API token scope does not allow this operation", nil)
+	ErrTokenSession  = errors.New(errors.Forbidden, "token-session", "operation requires a logged-in session, API tokens are not accepted", nil)
+	ErrTokenNotFound = errors.New(errors.NotFound, "token-not-found", "API token not found", nil)
+)
+
+var (
+	ErrDirectoryNoAccount   = errors.New(errors.NotFound, "directory-no-account", "directory has no account for the login", nil)
+	ErrDirectoryUnavailable = errors.Imp(errors.Unavailable, "directory-unavailable", "directory could not be reached")
+	ErrDirectoryProvision   = errors.Imp(errors.BadGateway, "directory-provision", "directory account could not be provisioned as a user")
+	ErrDirectoryConfig      = errors.Fmt(errors.InvalidInput, "directory-config", "invalid directory configuration: %s")
+
+	ErrSSODisabled     = errors.New(errors.NotFound, "sso-disabled", "single sign-on is not configured", nil)
+	ErrSSOStateInvalid = errors.New(errors.Unauthorized, "sso-state-invalid", "single sign-on login is invalid or expired, log in again", nil)
+	ErrSSODenied       = errors.New(errors.Unauthorized, "sso-denied", "identity provider did not authenticate the user", nil)
+	ErrSSOBusy         = errors.New(errors.TooManyRequests, "sso-busy", "too many single sign-on logins in progress, try again later", nil)
+	ErrSSOUnavailable  = errors.Imp(errors.Unavailable, "sso-unavailable", "identity provider could not be reached")
+	ErrSSOTokenInvalid = errors.Imp(errors.BadGateway, "sso-token-invalid", "identity provider issued an invalid ID token")
+	ErrSSONoUser       = errors.New(errors.Forbidden, "sso-no-user", "no user matches the institutional account", nil)
+)
+
 var (
 	ErrUserCreation = errors.Imp(errors.InvalidInput, "user-creation", "given data does not satisfy the user type")
 
 	ErrNameEmpty                     = errors.New(errors.InvalidInput, "name-empty", "name cannot be empty", nil)
 	ErrEmailInvalid                  = errors.New(errors.InvalidInput, "email-invalid", "email must be valid", nil)
-	ErrPasswordTooShort              = errors.New(errors.InvalidInput, "password-too-short", "password must be at least 8 characters long", nil)
-	ErrPasswordTooLong               = errors.New(errors.InvalidInput, "password-too-long", "password must be a maximum of 64 characters long", nil)
+	ErrPasswordEmpty                 = errors.New(errors.InvalidInput, "password-empty", "password cannot be empty", nil)
+	ErrPasswordTooShort              = errors.Fmt(errors.InvalidInput, "password-too-short", "password must be at least %d characters long")
+	ErrPasswordTooLong               = errors.Fmt(errors.InvalidInput, "password-too-long", "password must be a maximum of %d characters long")
+	ErrPasswordClasses               = errors.Fmt(errors.InvalidInput, "password-classes", "password must mix at least %d of lowercase letters, uppercase letters, digits and symbols")
+	ErrPasswordPersonal              = errors.Fmt(errors.InvalidInput, "password-personal", "password must not contain the %s of the user")
+	ErrPasswordBreached              = errors.New(errors.InvalidInput, "password-breached", "password is known to have leaked in data breaches, choose another", nil)
 	ErrPasswordLeadOrTrailWhitespace = errors.New(errors.InvalidInput, "password-edge-whitespace", "password must not begin or end with whitespaces", nil)
 	ErrPasswordIllegalCharacters     = errors.New(errors.InvalidInput, "password-illegal-chars", "password must not contain unprintable or invalid uft-8 characters", nil)
 
 	ErrIncorrectPassword    = errors.New(errors.Unauthorized, "incorrect-password", "given password is incorrect", nil)
+	ErrLoginLocked          = errors.Imp(errors.TooManyRequests, "login-locked", "too many failed login attempts, wait before trying again")
 	ErrFailedToHashPassword = errors.Imp(errors.Internal, "hash-failure", "failed to hash the password")
+	ErrBreachedUnavailable  = errors.Imp(errors.Internal, "breached-list-failure", "failed to check the password against the list of breached passwords")
+	ErrPasswordRejected     = errors.Imp(errors.InvalidInput, "password-rejected", "password does not satisfy the password policy")
+	ErrPasswordPolicy       = errors.Fmt(errors.InvalidInput, "password-policy", "invalid password policy: %s")
+
+	ErrRoleInvalid   = errors.New(errors.InvalidInput, "role-invalid", "role must be one of chief, promoted-admin, admin or user", nil)
+	ErrActiveInvalid = errors.New(errors.InvalidInput, "active-invalid", "active must be true or false", nil)
+	ErrSortInvalid   = errors.New(errors.InvalidInput, "sort-invalid", "sort must be one of name, siape or created, optionally prefixed by a minus", nil)
 
 	ErrUnpriviledUserPromotion = errors.Fmt(errors.Forbidden, "unpriviled-user", "auth role %v cannot upgrade an user to %v")
+	ErrChangeOwnRole           = errors.New(errors.Forbidden, "own-role-change", "users cannot change their own role", nil)
+	ErrChangeOthersPassword    = errors.New(errors.Forbidden, "others-password-change", "users can only change their own password, others must be reset", nil)
+	ErrResetOwnPassword        = errors.New(errors.Forbidden, "own-password-reset", "own password must be changed with the current password", nil)
+	ErrResetOwnTwoFactor       = errors.New(errors.Forbidden, "own-two-factor-reset", "own two-factor authentication must be disabled with a valid code", nil)
 	ErrUnauthenticatedUser     = errors.Imp(errors.Unauthorized, "unauthenticated-user", "user is not logged in")
 	ErrUnauthorizedUser        = errors.Fmt(errors.Forbidden, "unauthorized-user", "auth role %v does not match any criteria in %v")
 
-	ErrUserNotFound = errors.New(errors.NotFound, "user-not-found", "user not found", nil)
+	ErrUserNotFound    = errors.New(errors.NotFound, "user-not-found", "user not found", nil)
+	ErrUserDeactivated = errors.New(errors.Forbidden, "user-deactivated", "user is deactivated, a chief must reactivate them", nil)
+	ErrUserActive      = errors.New(errors.Conflict, "user-active", "user must be deactivated before being purged", nil)
+
+	ErrSiapeInvalid     = errors.New(errors.InvalidInput, "siape-invalid", "SIAPE must be a number of up to 7 digits", nil)
+	ErrMatriculaInvalid = errors.New(errors.InvalidInput, "matricula-invalid", "matrícula must be a number of 8 to 10 digits", nil)
+	ErrCPFInvalid       = errors.New(errors.InvalidInput, "cpf-invalid", "CPF must have 11 digits, the last two being valid check digits", nil)
+	ErrIdentityMissing  = errors.New(errors.InvalidInput, "identity-missing", "user must have a SIAPE, matrícula or CPF", nil)
+	ErrLoginInvalid     = errors.New(errors.InvalidInput, "login-invalid", "login must be a SIAPE, matrícula or CPF", nil)
+
+	ErrIdentityTaken  = errors.Fmt(errors.Conflict, "identity-taken", "%v is already in use")
+	ErrEmailAmbiguous = errors.New(errors.Conflict, "email-ambiguous", "email is shared by many users", nil)
+
+	ErrUserImport               = errors.Imp(errors.InvalidInput, "user-import", "users could not be imported")
+	ErrUserImportRow            = errors.Fmt(errors.InvalidInput, "user-import-row", "line %d: %v")
+	ErrUserImportRepeated       = errors.Fmt(errors.InvalidInput, "user-import-repeated", "%v repeats the one of line %d")
+	ErrUserImportEmpty          = errors.New(errors.InvalidInput, "user-import-empty", "users file is empty", nil)
+	ErrUserImportMalformed      = errors.Imp(errors.InvalidInput, "user-import-malformed", "users file is not a well-formed CSV")
+	ErrUserImportMissingColumns = errors.New(errors.InvalidInput, "user-import-missing-columns", "users file must have the columns name, email and one of siape, matricula or cpf", nil)
+	ErrImportCredentialsInvalid = errors.New(errors.InvalidInput, "import-credentials-invalid", "credentials must be either password or reset", nil)
+)
+
+var (
+	ErrEnvelopeCreation    = errors.Imp(errors.InvalidInput, "envelope-creation", "given data does not satisfy the envelope type")
+	ErrEnvelopeNoRecipient = errors.New(errors.InvalidInput, "envelope-no-recipient", "message must have a recipient", nil)
+	ErrEnvelopeNotFound    = errors.New(errors.NotFound, "envelope-not-found", "message not found in the outbox", nil)
+)
+
+var (
+	ErrMaterialCreation = errors.Imp(errors.InvalidInput, "material-creation", "given data does not satisfy the material type")
 
-	ErrSiapeTaken = errors.New(errors.NotFound, "siape-in-use", "siape is already in use", nil)
+	ErrMaterialCodeInvalid      = errors.New(errors.InvalidInput, "catmat-code-invalid", "CATMAT code must be a number between 1 and 999999", nil)
+	ErrMaterialDescriptionEmpty = errors.New(errors.InvalidInput, "catmat-description-empty", "CATMAT description cannot be empty", nil)
+	ErrMaterialClassInvalid     = errors.New(errors.InvalidInput, "catmat-class-invalid", "CATMAT class must be a four digit number", nil)
+
+	ErrMaterialNotFound = errors.New(errors.NotFound, "catmat-not-found", "CATMAT code not found in the reference table", nil)
+
+	ErrCatmatImport         = errors.Imp(errors.InvalidInput, "catmat-import", "CATMAT reference table could not be imported")
+	ErrCatmatRow            = errors.Fmt(errors.InvalidInput, "catmat-row", "line %d: %v")
+	ErrCatmatEmpty          = errors.New(errors.InvalidInput, "catmat-empty", "CATMAT file is empty", nil)
+	ErrCatmatMalformed      = errors.Imp(errors.InvalidInput, "catmat-malformed", "CATMAT file is not a well-formed CSV")
+	ErrCatmatMissingColumns = errors.New(errors.InvalidInput, "catmat-missing-columns", "CATMAT file must have the columns code, description and class", nil)
+)
+
+var (
+	ErrItemCreation = errors.Imp(errors.InvalidInput, "item-creation", "given data does not satisfy the item type")
+
+	ErrItemNameEmpty        = errors.New(errors.InvalidInput, "item-name-empty", "item name cannot be empty", nil)
+	ErrItemNameTooLong      = errors.Fmt(errors.InvalidInput, "item-name-too-long", "item name must not be longer than %d characters")
+	ErrItemUnitEmpty        = errors.New(errors.InvalidInput, "item-unit-empty", "item unit cannot be empty", nil)
+	ErrItemUnitTooLong      = errors.Fmt(errors.InvalidInput, "item-unit-too-long", "item unit must not be longer than %d characters")
+	ErrItemMinStockNegative = errors.New(errors.InvalidInput, "item-min-stock-negative", "item minimum stock cannot be negative", nil)
+	ErrItemLowInvalid       = errors.New(errors.InvalidInput, "item-low-invalid", "low must be true or false", nil)
+
+	ErrStockInsufficient = errors.Fmt(errors.Conflict, "stock-insufficient", "item has only %d in stock")
+
+	ErrItemNotFound = errors.New(errors.NotFound, "item-not-found", "item not found", nil)
+)
+
+var (
+	ErrMovementCreation = errors.Imp(errors.InvalidInput, "movement-creation", "given data does not satisfy the movement type")
+
+	ErrMovementKindInvalid       = errors.New(errors.InvalidInput, "movement-kind-invalid", "movement kind must be either entry or exit", nil)
+	ErrMovementQuantityInvalid   = errors.Fmt(errors.InvalidInput, "movement-quantity-invalid", "movement quantity must be a number between 1 and %d")
+	ErrMovementLocationEmpty     = errors.New(errors.InvalidInput, "movement-location-empty", "movement location cannot be empty", nil)
+	ErrMovementLocationTooLong   = errors.Fmt(errors.InvalidInput, "movement-location-too-long", "movement location must not be longer than %d characters")
+	ErrMovementCostCenterEmpty   = errors.New(errors.InvalidInput, "movement-cost-center-empty", "exits must be charged to a cost center", nil)
+	ErrMovementCostCenterTooLong = errors.Fmt(errors.InvalidInput, "movement-cost-center-too-long", "movement cost center must not be longer than %d characters")
+
+	ErrConsumptionGroupInvalid = errors.New(errors.InvalidInput, "consumption-group-invalid", "consumption must be grouped by item, location, cost-center or user", nil)
+)
+
+var (
+	ErrAuditRecordCreation = errors.New(errors.Internal, "audit-record-creation", "audit record must have an action and a target", nil)
+	ErrAuditDiff           = errors.Imp(errors.Internal, "audit-diff", "failed to compare the target before and after the action")
+	ErrAuditRecord         = errors.Imp(errors.Internal, "audit-record", "action was taken but could not be recorded in the audit log")
+	ErrAuditCheckpoint     = errors.Imp(errors.Internal, "audit-checkpoint", "failed to sign a checkpoint of the audit log")
+
+	ErrAuditCheckpointInterval = errors.New(errors.InvalidInput, "audit-checkpoint-interval", "audit checkpoints must be signed every one or more records", nil)
+	ErrAuditKey                = errors.New(errors.InvalidInput, "audit-key", "audit signing key must be an Ed25519 private key in PKCS #8 PEM", nil)
+
+	ErrAuditActorInvalid = errors.New(errors.InvalidInput, "audit-actor-invalid", "actor must be the UUID of a user", nil)
+	ErrAuditTimeInvalid  = errors.New(errors.InvalidInput, "audit-time-invalid", "from and to must be dates (YYYY-MM-DD) or times (RFC 3339)", nil)
+)
+
+var (
+	ErrReportFormat = errors.Fmt(errors.InvalidInput, "report-format", "report format %v is not supported, use csv or xlsx")
+	ErrReportPeriod = errors.New(errors.InvalidInput, "report-period", "report period must be a month (YYYY-MM) or a range of dates (YYYY-MM-DD)", nil)
 )
 
 var (
@@ -37,6 +190,7 @@ var (
 
 	ErrNoContentType              = errors.New(errors.PreconditionFailed, "no-content-type", "content type must be informed", nil)
 	ErrUnsupportedContentTypeJson = errors.New(errors.PreconditionFailed, "unsupported-content-type", "content type must be application/json", nil)
+	ErrUnsupportedContentTypeCSV  = errors.New(errors.PreconditionFailed, "unsupported-content-type", "content type must be text/csv", nil)
 	ErrJsonSyntax                 = errors.Fmt(errors.InvalidInput, "json-syntax-error", "JSON syntax error at %d")
 	ErrJsonType                   = errors.Fmt(errors.InvalidInput, "json-type-error", "JSON type error at %d, expected %v but got %v")
 	ErrNotAcceptableJson          = errors.New(errors.PreconditionFailed, "not-acceptable-type", "client does not accept application/json", nil)
@@ -0,0 +1,27 @@
+package xerrors
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// RetryAfter is the cause of errors after which the client must wait
+// before trying again, it is reported as the Retry-After header.
+type RetryAfter time.Duration
+
+// Error implements the [error] interface.
+func (ra RetryAfter) Error() string {
+	return "retry after " + (time.Duration(ra.Seconds()) * time.Second).String()
+}
+
+// Seconds returns the time to wait in whole seconds, rounded up.
+func (ra RetryAfter) Seconds() int {
+	return int((time.Duration(ra) + time.Second - 1) / time.Second)
+}
+
+// MarshalJSON implements the [json.Marshaler] interface on the type.
+func (ra RetryAfter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		RetryAfter int `json:"retry_after"`
+	}{ra.Seconds()})
+}
@@ -0,0 +1,329 @@
+// Copyright (C) 2025 Alan Barbosa Lima.
+//
+// Almodon is licensed under the GNU General Public License
+// version 3. You should have received a copy of the
+// license, located in LICENSE, at the root of the source
+// tree. If not, see <https://www.gnu.org/licenses/>.
+
+// Package ber implements the subset of the Basic Encoding Rules of
+// ASN.1 needed by LDAP: definite lengths and tag numbers below 31.
+package ber
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Class is the class of a tag.
+type Class byte
+
+const (
+	Universal   Class = 0x00
+	Application Class = 0x40
+	Context     Class = 0x80
+)
+
+// Universal tag numbers.
+const (
+	TagBoolean     = 0x01
+	TagInteger     = 0x02
+	TagOctetString = 0x04
+	TagNull        = 0x05
+	TagEnumerated  = 0x0a
+	TagSequence    = 0x10
+	TagSet         = 0x11
+)
+
+// MaxLength is the largest element Read accepts, so a peer cannot make
+// it allocate without bound.
+const MaxLength = 1 << 20
+
+var (
+	ErrTooLong    = errors.New("ber: element too long")
+	ErrMalformed  = errors.New("ber: malformed element")
+	ErrUnexpected = errors.New("ber: unexpected element")
+)
+
+// Packet is a BER element, either primitive, holding its contents in
+// Value, or constructed, holding them in Children.
+type Packet struct {
+	Class       Class
+	Constructed bool
+	Tag         byte
+	Value       []byte
+	Children    []*Packet
+}
+
+// Primitive creates a primitive element.
+func Primitive(class Class, tag byte, value []byte) *Packet {
+	return &Packet{Class: class, Tag: tag, Value: value}
+}
+
+// Constructed creates a constructed element.
+func Constructed(class Class, tag byte, children ...*Packet) *Packet {
+	return &Packet{Class: class, Constructed: true, Tag: tag, Children: children}
+}
+
+// Sequence creates a universal SEQUENCE.
+func Sequence(children ...*Packet) *Packet {
+	return Constructed(Universal, TagSequence, children...)
+}
+
+// Set creates a universal SET.
+func Set(children ...*Packet) *Packet {
+	return Constructed(Universal, TagSet, children...)
+}
+
+// OctetString creates a universal OCTET STRING.
+func OctetString(s string) *Packet {
+	return Primitive(Universal, TagOctetString, []byte(s))
+}
+
+// Int creates a universal INTEGER.
+func Int(n int64) *Packet {
+	return Primitive(Universal, TagInteger, encodeInt(n))
+}
+
+// Enum creates a universal ENUMERATED.
+func Enum(n int64) *Packet {
+	return Primitive(Universal, TagEnumerated, encodeInt(n))
+}
+
+// Bool creates a universal BOOLEAN.
+func Bool(b bool) *Packet {
+	if b {
+		return Primitive(Universal, TagBoolean, []byte{0xff})
+	}
+
+	return Primitive(Universal, TagBoolean, []byte{0x00})
+}
+
+// Is returns whether the element has the given class and tag.
+func (p *Packet) Is(class Class, tag byte) bool {
+	return p.Class == class && p.Tag == tag
+}
+
+// Int decodes the contents of the element as an integer.
+func (p *Packet) Int() (int64, error) {
+	if p.Constructed || len(p.Value) == 0 || len(p.Value) > 8 {
+		return 0, ErrMalformed
+	}
+
+	n := int64(int8(p.Value[0]))
+	for _, b := range p.Value[1:] {
+		n = n<<8 | int64(b)
+	}
+
+	return n, nil
+}
+
+// String returns the contents of the element as a string.
+func (p *Packet) String() string {
+	return string(p.Value)
+}
+
+// Child returns the i-th child of the element, checking that it has
+// the given class and tag.
+func (p *Packet) Child(i int, class Class, tag byte) (*Packet, error) {
+	if i >= len(p.Children) || !p.Children[i].Is(class, tag) {
+		return nil, fmt.Errorf("%w: expected child %d to be %#x", ErrUnexpected, i, byte(class)|tag)
+	}
+
+	return p.Children[i], nil
+}
+
+// Bytes encodes the element.
+func (p *Packet) Bytes() []byte {
+	contents := p.Value
+	if p.Constructed {
+		contents = nil
+		for _, child := range p.Children {
+			contents = append(contents, child.Bytes()...)
+		}
+	}
+
+	ident := byte(p.Class) | p.Tag&0x1f
+	if p.Constructed {
+		ident |= 0x20
+	}
+
+	buf := append([]byte{ident}, encodeLength(len(contents))...)
+	return append(buf, contents...)
+}
+
+// Reader is what elements are read from, such as a [bufio.Reader].
+type Reader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// Read decodes the next element from r.
+func Read(r Reader) (*Packet, error) {
+	ident, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	if ident&0x1f == 0x1f {
+		return nil, fmt.Errorf("%w: long tag numbers are not supported", ErrMalformed)
+	}
+
+	length, err := readLength(r)
+	if err != nil {
+		return nil, err
+	}
+
+	contents := make([]byte, length)
+	if _, err := io.ReadFull(r, contents); err != nil {
+		return nil, noEOF(err)
+	}
+
+	return decode(ident, contents)
+}
+
+// Parse decodes the element encoded in buf, which must hold nothing
+// else.
+func Parse(buf []byte) (*Packet, error) {
+	p, rest, err := parse(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: trailing data", ErrMalformed)
+	}
+
+	return p, nil
+}
+
+func parse(buf []byte) (*Packet, []byte, error) {
+	if len(buf) < 2 {
+		return nil, nil, ErrMalformed
+	}
+
+	ident := buf[0]
+	if ident&0x1f == 0x1f {
+		return nil, nil, fmt.Errorf("%w: long tag numbers are not supported", ErrMalformed)
+	}
+
+	r := &sliceReader{buf: buf[1:]}
+	length, err := readLength(r)
+	if err != nil {
+		return nil, nil, noEOF(err)
+	}
+
+	if length > len(r.buf) {
+		return nil, nil, ErrMalformed
+	}
+
+	p, err := decode(ident, r.buf[:length])
+	return p, r.buf[length:], err
+}
+
+func decode(ident byte, contents []byte) (*Packet, error) {
+	p := &Packet{
+		Class:       Class(ident & 0xc0),
+		Constructed: ident&0x20 != 0,
+		Tag:         ident & 0x1f,
+	}
+
+	if !p.Constructed {
+		p.Value = contents
+		return p, nil
+	}
+
+	for len(contents) > 0 {
+		child, rest, err := parse(contents)
+		if err != nil {
+			return nil, err
+		}
+
+		p.Children = append(p.Children, child)
+		contents = rest
+	}
+
+	return p, nil
+}
+
+func readLength(r io.ByteReader) (int, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, noEOF(err)
+	}
+
+	if b < 0x80 {
+		return int(b), nil
+	}
+
+	n := int(b & 0x7f)
+	if n == 0 {
+		return 0, fmt.Errorf("%w: indefinite lengths are not supported", ErrMalformed)
+	}
+	if n > 4 {
+		return 0, ErrTooLong
+	}
+
+	length := 0
+	for range n {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, noEOF(err)
+		}
+
+		length = length<<8 | int(b)
+	}
+
+	if length > MaxLength {
+		return 0, ErrTooLong
+	}
+
+	return length, nil
+}
+
+func encodeLength(n int) []byte {
+	if n < 0x80 {
+		return []byte{byte(n)}
+	}
+
+	var buf []byte
+	for ; n > 0; n >>= 8 {
+		buf = append([]byte{byte(n)}, buf...)
+	}
+
+	return append([]byte{0x80 | byte(len(buf))}, buf...)
+}
+
+// encodeInt encodes n in the least number of bytes of two's
+// complement.
+func encodeInt(n int64) []byte {
+	buf := []byte{byte(n)}
+	for n > 0x7f || n < -0x80 {
+		n >>= 8
+		buf = append([]byte{byte(n)}, buf...)
+	}
+
+	return buf
+}
+
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+type sliceReader struct {
+	buf []byte
+}
+
+func (r *sliceReader) ReadByte() (byte, error) {
+	if len(r.buf) == 0 {
+		return 0, io.EOF
+	}
+
+	b := r.buf[0]
+	r.buf = r.buf[1:]
+	return b, nil
+}
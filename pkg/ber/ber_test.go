@@ -0,0 +1,92 @@
+package ber_test
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/alan-b-lima/almodon/pkg/ber"
+)
+
+func TestInt(t *testing.T) {
+	tests := []struct {
+		n       int64
+		encoded []byte
+	}{
+		{0, []byte{0x02, 0x01, 0x00}},
+		{127, []byte{0x02, 0x01, 0x7f}},
+		{128, []byte{0x02, 0x02, 0x00, 0x80}},
+		{-128, []byte{0x02, 0x01, 0x80}},
+		{-129, []byte{0x02, 0x02, 0xff, 0x7f}},
+		{1 << 20, []byte{0x02, 0x03, 0x10, 0x00, 0x00}},
+	}
+
+	for _, test := range tests {
+		p := Int(test.n)
+		if got := p.Bytes(); !bytes.Equal(got, test.encoded) {
+			t.Errorf("%d: expected % x, got % x", test.n, test.encoded, got)
+		}
+
+		n, err := p.Int()
+		if err != nil || n != test.n {
+			t.Errorf("%d: decoded %d, %v", test.n, n, err)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	long := strings.Repeat("x", 300)
+	p := Sequence(
+		Int(7),
+		Constructed(Application, 3,
+			OctetString(long),
+			Bool(true),
+			Primitive(Context, 7, []byte("cn")),
+		),
+		Set(),
+	)
+
+	buf := p.Bytes()
+
+	for _, read := range []func([]byte) (*Packet, error){
+		Parse,
+		func(b []byte) (*Packet, error) { return Read(bufio.NewReader(bytes.NewReader(b))) },
+	} {
+		got, err := read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(got.Bytes(), buf) {
+			t.Error("decoded element does not encode back to the same bytes")
+		}
+
+		op, err := got.Child(1, Application, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if op.Children[0].String() != long || !op.Children[2].Is(Context, 7) {
+			t.Error("children were not decoded")
+		}
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	bad := [][]byte{
+		{},
+		{0x30},
+		{0x30, 0x05, 0x02, 0x01},
+		{0x04, 0x80},
+		{0x04, 0x85, 0x01, 0x00, 0x00, 0x00, 0x00},
+		{0x30, 0x03, 0x02, 0x05, 0x00},
+		{0x02, 0x01, 0x00, 0x00},
+	}
+
+	for _, buf := range bad {
+		if _, err := Parse(buf); err == nil {
+			t.Errorf("% x: expected an error", buf)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (C) 2025 Alan Barbosa Lima.
+//
+// Almodon is licensed under the GNU General Public License
+// version 3. You should have received a copy of the
+// license, located in LICENSE, at the root of the source
+// tree. If not, see <https://www.gnu.org/licenses/>.
+
+// Package breach checks passwords against a local list of breached
+// passwords, without reaching any service.
+//
+// The list is in the format of the downloadable Pwned Passwords, the
+// hexadecimal SHA-1 of each password, optionally followed by a colon
+// and how many times it was seen, one per line, ordered by hash:
+//
+//	000000005AD76BD555C1D6D771DE417A4B87E4B4:10
+//	00000000A8DAE4228F821FB418F59826079BF368:4
+//
+// Lists of plain passwords are converted by hashing each line and
+// sorting the result. Like the k-anonymity API of the service, lookups
+// go by the first 5 digits of the hash: the list is indexed by them
+// once opened, and only the lines of the prefix are read from disk.
+package breach
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// prefixes is how many prefixes of 5 hexadecimal digits there are.
+const prefixes = 1 << 20
+
+var ErrUnordered = errors.New("breach: list is not ordered by hash")
+
+// List is a list of breached passwords, safe for concurrent use.
+type List struct {
+	r io.ReaderAt
+	c io.Closer
+
+	// index holds where the lines of each prefix begin, the lines of
+	// prefix p lie between index[p] and index[p+1]
+	index []int64
+}
+
+// Open opens and indexes the list in the named file, which is kept
+// open until the list is closed.
+func Open(name string) (*List, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := New(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	l.c = f
+	return l, nil
+}
+
+// New indexes the list read from r, which must be readable from the
+// start and at any offset while the list is used.
+func New(r io.ReaderAt) (*List, error) {
+	l := &List{r: r, index: make([]int64, prefixes+1)}
+
+	br := bufio.NewReader(io.NewSectionReader(r, 0, 1<<63-1))
+
+	var offset int64
+	var next, lineno int
+	for {
+		line, err := br.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			return nil, fmt.Errorf("breach: line %d is too long", lineno+1)
+		}
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if len(line) == 0 {
+			break
+		}
+		lineno++
+
+		if hash := trim(line); len(hash) > 0 {
+			p, ok := prefix(hash)
+			if !ok {
+				return nil, fmt.Errorf("breach: line %d is not a SHA-1 hash", lineno)
+			}
+
+			if p+1 < next {
+				return nil, ErrUnordered
+			}
+
+			for ; next <= p; next++ {
+				l.index[next] = offset
+			}
+		}
+
+		offset += int64(len(line))
+		if err == io.EOF {
+			break
+		}
+	}
+
+	for ; next <= prefixes; next++ {
+		l.index[next] = offset
+	}
+
+	return l, nil
+}
+
+// Contains returns whether the password is on the list.
+func (l *List) Contains(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+
+	var digest [40]byte
+	hex.Encode(digest[:], sum[:])
+
+	p, _ := prefix(digest[:])
+	lo, hi := l.index[p], l.index[p+1]
+
+	buf := make([]byte, hi-lo)
+	if _, err := l.r.ReadAt(buf, lo); err != nil && err != io.EOF {
+		return false, err
+	}
+
+	for line := range bytes.Lines(buf) {
+		if bytes.EqualFold(trim(line), digest[:]) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Close closes the file of the list, if it was opened by [Open].
+func (l *List) Close() error {
+	if l.c == nil {
+		return nil
+	}
+
+	return l.c.Close()
+}
+
+// trim drops the count and the line ending of a line, if any.
+func trim(line []byte) []byte {
+	if i := bytes.IndexByte(line, ':'); i >= 0 {
+		line = line[:i]
+	}
+
+	return bytes.TrimRight(line, "\r\n")
+}
+
+// prefix parses the first 5 digits of the hash.
+func prefix(hash []byte) (int, bool) {
+	if len(hash) != 40 {
+		return 0, false
+	}
+
+	var p int
+	for _, c := range hash[:5] {
+		var d byte
+		switch {
+		case '0' <= c && c <= '9':
+			d = c - '0'
+		case 'a' <= c && c <= 'f':
+			d = c - 'a' + 10
+		case 'A' <= c && c <= 'F':
+			d = c - 'A' + 10
+		default:
+			return 0, false
+		}
+
+		p = p<<4 | int(d)
+	}
+
+	return p, true
+}
@@ -0,0 +1,86 @@
+package breach_test
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/alan-b-lima/almodon/pkg/breach"
+)
+
+func list(passwords ...string) string {
+	var lines []string
+	for i, password := range passwords {
+		sum := sha1.Sum([]byte(password))
+		line := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+		// counts are optional, and so is the case of the digits
+		switch i % 3 {
+		case 0:
+			line += ":42"
+		case 1:
+			line = strings.ToLower(line)
+		}
+
+		lines = append(lines, line)
+	}
+
+	slices.SortFunc(lines, func(a, b string) int {
+		return strings.Compare(strings.ToUpper(a), strings.ToUpper(b))
+	})
+
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+func TestContains(t *testing.T) {
+	breached := []string{"12345678", "password", "senha123", "qwertyui", "iloveyou", "admin", "almodon"}
+
+	name := filepath.Join(t.TempDir(), "pwned.txt")
+	if err := os.WriteFile(name, []byte(list(breached...)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	for _, password := range breached {
+		if in, err := l.Contains(password); err != nil || !in {
+			t.Errorf("%q should be on the list, got %v, %v", password, in, err)
+		}
+	}
+
+	for _, password := range []string{"", "12345679", "Password", "correct horse battery staple"} {
+		if in, err := l.Contains(password); err != nil || in {
+			t.Errorf("%q should not be on the list, got %v, %v", password, in, err)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(strings.NewReader("")); err != nil {
+		t.Errorf("empty lists should be accepted, got %v", err)
+	}
+
+	tests := []struct {
+		name string
+		list string
+	}{
+		{"not a hash", "12345678\n"},
+		{"short hash", "7C222FB2927D828AF22F59213:1\n"},
+		{"unordered", "FFFFF" + strings.Repeat("0", 35) + "\n" + strings.Repeat("0", 40) + "\n"},
+		{"unordered prefixes", "00001" + strings.Repeat("0", 35) + "\n" + strings.Repeat("0", 40) + "\n"},
+	}
+
+	for _, test := range tests {
+		if _, err := New(strings.NewReader(test.list)); err == nil {
+			t.Errorf("%s: list should have been rejected", test.name)
+		}
+	}
+}
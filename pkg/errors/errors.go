@@ -151,6 +151,13 @@ const (
 	// Rough HTTP equivalent: 408 Request Timeout.
 	Timeout
 
+	// TooManyRequests indicates that the client sent too many requests,
+	// or failed too many times, and must wait before trying again. Use
+	// this for rate limiting and lockouts.
+	//
+	// Rough HTTP equivalent: 429 Too Many Requests.
+	TooManyRequests
+
 	client_errors_end     // This exists only for grouping.
 	internal_errors_start // This exists only for grouping.
 
@@ -184,6 +191,7 @@ var kindStrings = map[Kind]string{
 	NotFound:           "not found",
 	Conflict:           "conflict",
 	Timeout:            "timeout",
+	TooManyRequests:    "too many requests",
 
 	Internal:    "internal error",
 	Unavailable: "unavailable",
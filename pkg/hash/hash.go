@@ -5,49 +5,157 @@
 // license, located in LICENSE, at the root of the source
 // tree. If not, see <https://www.gnu.org/licenses/>.
 
-// Package hash wraps functionality from the standard
-// [golang.org/x/crypto/bcrypt] package.
+// Package hash hashes passwords with Argon2id, from the
+// [golang.org/x/crypto/argon2] package, encoding the hashes in the PHC
+// string format, and verifies the BCrypt hashes of earlier versions,
+// so that they can be replaced as their users log in.
 package hash
 
 import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"strings"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// Hash takes an arbitrarily long password and hashes it using the
-// BCrypt algorithm.
+// Params are the costs of Argon2id, memory in KiB, and the lengths of
+// the salt and of the key derived.
+type Params struct {
+	Memory  uint32
+	Time    uint32
+	Threads uint8
+	SaltLen uint32
+	KeyLen  uint32
+}
+
+// DefaultParams are the minimum recommended by OWASP, hashes of other
+// parameters are reported by [NeedsRehash].
+var DefaultParams = Params{
+	Memory:  19 * 1024,
+	Time:    2,
+	Threads: 1,
+	SaltLen: 16,
+	KeyLen:  32,
+}
+
+// maxMemory, maxTime and maxLen cap the memory, the passes and the
+// lengths of the salt and of the key hashes being verified may claim,
+// so that a tampered hash cannot exhaust the memory nor pin the CPU of
+// the server.
+const (
+	maxMemory = 1 << 20
+	maxTime   = 10
+	maxLen    = 64
+)
+
+var ErrMalformedHash = errors.New("hash: malformed hash")
+
+// Hash takes a password and hashes it using Argon2id with the
+// [DefaultParams], returning the hash encoded in the PHC string format,
+// as in "$argon2id$v=19$m=19456,t=2,p=1$<salt>$<key>".
 //
 // To compare a hash to its password, you MUST use the [Compare]
 // function, hashing the password again and using == should yield a
-// different hash, failing the equality test.
-//
-// It handles the passwords that are larger than 72 bytes, which
-// BCrypt wouldn't accept, by XORing the content over itself in
-// 72-byte chunks. This introduces chance for matching a passwords
-// in a simplistic manner, because XOR is trivially reversible.
-func Hash(password []byte) ([60]byte, error) {
-	ingest := atMax72Bytes(password)
-
-	digest, err := bcrypt.GenerateFromPassword(ingest, bcrypt.DefaultCost)
+// different hash, as the salt is random.
+func Hash(password []byte) ([]byte, error) {
+	return HashWith(DefaultParams, password)
+}
+
+// HashWith is like [Hash], with the given parameters.
+func HashWith(p Params, password []byte) ([]byte, error) {
+	salt := make([]byte, p.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	key := argon2.IDKey(password, salt, p.Time, p.Memory, p.Threads, p.KeyLen)
+
+	return fmt.Appendf(nil, "$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.Memory, p.Time, p.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Compare compares a hash, generated through [Hash] or a BCrypt one,
+// with a password, it returns true for a match, and false for not a
+// match or an error.
+func Compare(hash, password []byte) bool {
+	if isBcrypt(hash) {
+		err := bcrypt.CompareHashAndPassword(hash, atMax72Bytes(password))
+		return err == nil
+	}
+
+	p, salt, key, err := decode(hash)
 	if err != nil {
-		return [60]byte{}, err
+		return false
 	}
-	if len(digest) != 60 {
-		return [60]byte{}, errors.New("hash: digest is not of length 60")
+
+	other := argon2.IDKey(password, salt, p.Time, p.Memory, p.Threads, p.KeyLen)
+	return subtle.ConstantTimeCompare(key, other) == 1
+}
+
+// NeedsRehash returns whether the hash is not an Argon2id one of the
+// [DefaultParams], so that it should be replaced by one that is, once
+// the password is known to match it.
+func NeedsRehash(hash []byte) bool {
+	p, _, _, err := decode(hash)
+	return err != nil || p != DefaultParams
+}
+
+func decode(hash []byte) (p Params, salt, key []byte, err error) {
+	parts := strings.Split(string(hash), "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return Params{}, nil, nil, ErrMalformedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return Params{}, nil, nil, ErrMalformedHash
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Time, &p.Threads); err != nil {
+		return Params{}, nil, nil, ErrMalformedHash
+	}
+
+	if p.Memory > maxMemory || p.Time == 0 || p.Time > maxTime || p.Threads == 0 {
+		return Params{}, nil, nil, ErrMalformedHash
+	}
+
+	enc := base64.RawStdEncoding
+	if enc.DecodedLen(len(parts[4])) > maxLen || enc.DecodedLen(len(parts[5])) > maxLen {
+		return Params{}, nil, nil, ErrMalformedHash
 	}
 
-	return [60]byte(digest), nil
+	salt, err = enc.DecodeString(parts[4])
+	if err != nil {
+		return Params{}, nil, nil, ErrMalformedHash
+	}
+
+	key, err = enc.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return Params{}, nil, nil, ErrMalformedHash
+	}
+
+	p.SaltLen, p.KeyLen = uint32(len(salt)), uint32(len(key))
+	return p, salt, key, nil
 }
 
-// Compare compares a hash generated through [Hash] with a password,
-// it returns true for a match, and false for not a match or an
-// error.
-func Compare(hash, password []byte) bool {
-	err := bcrypt.CompareHashAndPassword(hash, atMax72Bytes(password))
-	return err == nil
+func isBcrypt(hash []byte) bool {
+	return bytes.HasPrefix(hash, []byte("$2a$")) ||
+		bytes.HasPrefix(hash, []byte("$2b$")) ||
+		bytes.HasPrefix(hash, []byte("$2y$"))
 }
 
+// atMax72Bytes folds the passwords larger than 72 bytes, which BCrypt
+// wouldn't accept, by XORing the content over itself in 72-byte
+// chunks, as the BCrypt hashes of earlier versions were made.
 func atMax72Bytes(data []byte) []byte {
 	const size = 72
 
@@ -3,8 +3,11 @@ package hash_test
 import (
 	crand "crypto/rand"
 	"math/rand/v2"
+	"strings"
 	"testing"
 
+	"golang.org/x/crypto/bcrypt"
+
 	. "github.com/alan-b-lima/almodon/pkg/hash"
 )
 
@@ -26,8 +29,99 @@ func TestHashComparison(t *testing.T) {
 			continue
 		}
 
-		if !Compare(hash[:], password) {
+		if !Compare(hash, password) {
 			t.Errorf("%x should have compared to true with its hash", password)
 		}
 	}
 }
+
+func TestBcryptCompatibility(t *testing.T) {
+	passwords := []string{"12345678", strings.Repeat("long password ", 8)}
+
+	for _, password := range passwords {
+		// earlier versions folded passwords over 72 bytes before hashing
+		ingest := []byte(password)
+		if len(ingest) > 72 {
+			folded := make([]byte, 72)
+			for i, b := range ingest {
+				folded[i%72] ^= b
+			}
+			ingest = folded
+		}
+
+		hash, err := bcrypt.GenerateFromPassword(ingest, bcrypt.MinCost)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !Compare(hash, []byte(password)) {
+			t.Errorf("%q should have compared to true with its BCrypt hash", password)
+		}
+
+		if Compare(hash, []byte(password+"!")) {
+			t.Errorf("%q should have compared to false with the BCrypt hash of %q", password+"!", password)
+		}
+
+		if !NeedsRehash(hash) {
+			t.Error("BCrypt hashes should need rehashing")
+		}
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	hash, err := Hash([]byte("12345678"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(string(hash), "$argon2id$v=19$m=19456,t=2,p=1$") {
+		t.Errorf("unexpected encoding %s", hash)
+	}
+
+	if NeedsRehash(hash) {
+		t.Errorf("%s is of the default parameters", hash)
+	}
+
+	weaker := DefaultParams
+	weaker.Time = 1
+
+	hash, err = HashWith(weaker, []byte("12345678"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !Compare(hash, []byte("12345678")) {
+		t.Errorf("%s should have compared to true with its password", hash)
+	}
+
+	if !NeedsRehash(hash) {
+		t.Errorf("%s is not of the default parameters", hash)
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	hashes := []string{
+		"",
+		"12345678",
+		"$argon2i$v=19$m=19456,t=2,p=1$c2FsdHNhbHQ$a2V5a2V5",
+		"$argon2id$v=16$m=19456,t=2,p=1$c2FsdHNhbHQ$a2V5a2V5",
+		"$argon2id$v=19$m=19456,t=0,p=1$c2FsdHNhbHQ$a2V5a2V5",
+		"$argon2id$v=19$m=4294967295,t=2,p=1$c2FsdHNhbHQ$a2V5a2V5",
+		"$argon2id$v=19$m=19456,t=4294967295,p=1$c2FsdHNhbHQ$a2V5a2V5",
+		"$argon2id$v=19$m=19456,t=11,p=1$c2FsdHNhbHQ$a2V5a2V5",
+		"$argon2id$v=19$m=19456,t=2,p=1$c2FsdHNhbHQ$" + strings.Repeat("a2V5", 22),
+		"$argon2id$v=19$m=19456,t=2,p=1$" + strings.Repeat("c2Fs", 22) + "$a2V5a2V5",
+		"$argon2id$v=19$m=19456,t=2,p=1$!!!$a2V5a2V5",
+		"$argon2id$v=19$m=19456,t=2,p=1$c2FsdHNhbHQ$",
+	}
+
+	for _, hash := range hashes {
+		if Compare([]byte(hash), []byte("12345678")) {
+			t.Errorf("%q should not compare to true with anything", hash)
+		}
+
+		if !NeedsRehash([]byte(hash)) {
+			t.Errorf("%q should need rehashing", hash)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (C) 2025 Alan Barbosa Lima.
+//
+// Almodon is licensed under the GNU General Public License
+// version 3. You should have received a copy of the
+// license, located in LICENSE, at the root of the source
+// tree. If not, see <https://www.gnu.org/licenses/>.
+
+package ldap
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/alan-b-lima/almodon/pkg/ber"
+)
+
+// Context tags of the filter choices.
+const (
+	filterAnd      = 0
+	filterOr       = 1
+	filterNot      = 2
+	filterEquality = 3
+	filterPresent  = 7
+)
+
+// Filter selects the entries of a search. Filters are built from
+// their parts, rather than parsed from the string representation, so
+// values need no escaping.
+type Filter struct {
+	choice byte
+	attr   string
+	value  string
+	subs   []Filter
+}
+
+// Equal matches entries whose attribute has the value.
+func Equal(attr, value string) Filter {
+	return Filter{choice: filterEquality, attr: attr, value: value}
+}
+
+// Present matches entries that have the attribute.
+func Present(attr string) Filter {
+	return Filter{choice: filterPresent, attr: attr}
+}
+
+// And matches entries matched by all of the filters.
+func And(filters ...Filter) Filter {
+	return Filter{choice: filterAnd, subs: filters}
+}
+
+// Or matches entries matched by any of the filters.
+func Or(filters ...Filter) Filter {
+	return Filter{choice: filterOr, subs: filters}
+}
+
+// Not matches entries not matched by the filter.
+func Not(filter Filter) Filter {
+	return Filter{choice: filterNot, subs: []Filter{filter}}
+}
+
+// Packet encodes the filter.
+func (f Filter) Packet() *ber.Packet {
+	switch f.choice {
+	case filterAnd, filterOr, filterNot:
+		p := ber.Constructed(ber.Context, f.choice)
+		for _, sub := range f.subs {
+			p.Children = append(p.Children, sub.Packet())
+		}
+
+		return p
+
+	case filterEquality:
+		return ber.Constructed(ber.Context, filterEquality, ber.OctetString(f.attr), ber.OctetString(f.value))
+	}
+
+	return ber.Primitive(ber.Context, filterPresent, []byte(f.attr))
+}
+
+// ParseFilter decodes a filter, of the choices supported by [Filter].
+func ParseFilter(p *ber.Packet) (Filter, error) {
+	if p.Class != ber.Context {
+		return Filter{}, ber.ErrUnexpected
+	}
+
+	switch p.Tag {
+	case filterAnd, filterOr, filterNot:
+		f := Filter{choice: p.Tag}
+		for _, child := range p.Children {
+			sub, err := ParseFilter(child)
+			if err != nil {
+				return Filter{}, err
+			}
+
+			f.subs = append(f.subs, sub)
+		}
+
+		if p.Tag == filterNot && len(f.subs) != 1 {
+			return Filter{}, ber.ErrMalformed
+		}
+
+		return f, nil
+
+	case filterEquality:
+		if len(p.Children) != 2 {
+			return Filter{}, ber.ErrMalformed
+		}
+
+		return Equal(p.Children[0].String(), p.Children[1].String()), nil
+
+	case filterPresent:
+		return Present(p.String()), nil
+	}
+
+	return Filter{}, ber.ErrUnexpected
+}
+
+// Match returns whether the filter matches the entry. Attribute names
+// are compared case insensitively, and values exactly.
+func (f Filter) Match(e *Entry) bool {
+	switch f.choice {
+	case filterAnd:
+		for _, sub := range f.subs {
+			if !sub.Match(e) {
+				return false
+			}
+		}
+
+		return true
+
+	case filterOr:
+		for _, sub := range f.subs {
+			if sub.Match(e) {
+				return true
+			}
+		}
+
+		return false
+
+	case filterNot:
+		return !f.subs[0].Match(e)
+
+	case filterEquality:
+		return slices.Contains(e.Values(f.attr), f.value)
+	}
+
+	return strings.EqualFold(f.attr, "objectClass") || e.Values(f.attr) != nil
+}
@@ -0,0 +1,427 @@
+// Copyright (C) 2025 Alan Barbosa Lima.
+//
+// Almodon is licensed under the GNU General Public License
+// version 3. You should have received a copy of the
+// license, located in LICENSE, at the root of the source
+// tree. If not, see <https://www.gnu.org/licenses/>.
+
+// Package ldap implements a minimal LDAPv3 client, as of RFC 4511,
+// with only what authenticating against a directory takes: simple
+// binds, searches and StartTLS.
+package ldap
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alan-b-lima/almodon/pkg/ber"
+)
+
+// Application tags of the protocol operations.
+const (
+	AppBindRequest           = 0
+	AppBindResponse          = 1
+	AppUnbindRequest         = 2
+	AppSearchRequest         = 3
+	AppSearchResultEntry     = 4
+	AppSearchResultDone      = 5
+	AppSearchResultReference = 19
+	AppExtendedRequest       = 23
+	AppExtendedResponse      = 24
+)
+
+// OIDStartTLS names the extended operation upgrading the connection to
+// TLS, as of RFC 4511, section 4.14.
+const OIDStartTLS = "1.3.6.1.4.1.1466.20037"
+
+// Result codes, those the client tells apart.
+const (
+	ResultSuccess            = 0
+	ResultProtocolError      = 2
+	ResultSizeLimitExceeded  = 4
+	ResultNoSuchObject       = 32
+	ResultInvalidCredentials = 49
+)
+
+// Scope is how deep below the base a search goes.
+type Scope int64
+
+const (
+	ScopeBase Scope = iota
+	ScopeOne
+	ScopeSub
+)
+
+var (
+	// ErrEmptyPassword is returned for binds without a password,
+	// which servers take as unauthenticated binds and accept.
+	ErrEmptyPassword = errors.New("ldap: empty password")
+
+	ErrClosed = errors.New("ldap: connection closed")
+)
+
+// Error is a result other than success returned by the server.
+type Error struct {
+	Code    int
+	Message string
+}
+
+func (err *Error) Error() string {
+	if err.Message == "" {
+		return fmt.Sprintf("ldap: result code %d", err.Code)
+	}
+
+	return fmt.Sprintf("ldap: result code %d: %s", err.Code, err.Message)
+}
+
+// IsCode returns whether err is an [Error] of the given result code.
+func IsCode(err error, code int) bool {
+	var lerr *Error
+	return errors.As(err, &lerr) && lerr.Code == code
+}
+
+// Entry is an object of the directory.
+type Entry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+// Values returns the values of the attribute, whose name is case
+// insensitive.
+func (e *Entry) Values(attr string) []string {
+	for name, values := range e.Attributes {
+		if strings.EqualFold(name, attr) {
+			return values
+		}
+	}
+
+	return nil
+}
+
+// Value returns the first value of the attribute, or the empty string
+// if it has none.
+func (e *Entry) Value(attr string) string {
+	if values := e.Values(attr); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// SearchRequest describes a search.
+type SearchRequest struct {
+	BaseDN     string
+	Scope      Scope
+	Filter     Filter
+	Attributes []string
+	SizeLimit  int
+}
+
+// Conn is a connection to an LDAP server. Operations are serialized,
+// each waiting for the response of the previous.
+type Conn struct {
+	conn    net.Conn
+	r       *bufio.Reader
+	timeout time.Duration
+
+	mu     sync.Mutex
+	id     int64
+	closed bool
+}
+
+// Dial connects to the server at the URL, of scheme ldap or ldaps,
+// the latter over TLS configured by config, which may be nil. Every
+// operation, including the dial, must complete within timeout.
+func Dial(rawURL string, config *tls.Config, timeout time.Duration) (*Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+
+	var conn net.Conn
+	switch u.Scheme {
+	case "ldap":
+		conn, err = dialer.Dial("tcp", hostPort(u, "389"))
+
+	case "ldaps":
+		if config == nil {
+			config = &tls.Config{ServerName: u.Hostname()}
+		}
+
+		conn, err = tls.DialWithDialer(dialer, "tcp", hostPort(u, "636"), config)
+
+	default:
+		return nil, fmt.Errorf("ldap: unsupported scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return NewConn(conn, timeout), nil
+}
+
+// NewConn creates a client over an established connection.
+func NewConn(conn net.Conn, timeout time.Duration) *Conn {
+	return &Conn{conn: conn, r: bufio.NewReader(conn), timeout: timeout}
+}
+
+func hostPort(u *url.URL, port string) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// Bind authenticates the connection as dn with a simple bind. Empty
+// passwords are refused without asking the server.
+func (c *Conn) Bind(dn, password string) error {
+	if password == "" {
+		return ErrEmptyPassword
+	}
+
+	op := ber.Constructed(ber.Application, AppBindRequest,
+		ber.Int(3),
+		ber.OctetString(dn),
+		ber.Primitive(ber.Context, 0, []byte(password)),
+	)
+
+	defer c.mu.Unlock()
+	c.mu.Lock()
+
+	id, err := c.send(op)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.receive(id)
+	if err != nil {
+		return err
+	}
+
+	if !res.Is(ber.Application, AppBindResponse) {
+		return ber.ErrUnexpected
+	}
+
+	return result(res)
+}
+
+// Search returns the entries matching the request.
+func (c *Conn) Search(req SearchRequest) ([]Entry, error) {
+	attrs := ber.Sequence()
+	for _, attr := range req.Attributes {
+		attrs.Children = append(attrs.Children, ber.OctetString(attr))
+	}
+
+	op := ber.Constructed(ber.Application, AppSearchRequest,
+		ber.OctetString(req.BaseDN),
+		ber.Enum(int64(req.Scope)),
+		ber.Enum(0), // never dereference aliases
+		ber.Int(int64(req.SizeLimit)),
+		ber.Int(int64(c.timeout/time.Second)),
+		ber.Bool(false),
+		req.Filter.Packet(),
+		attrs,
+	)
+
+	defer c.mu.Unlock()
+	c.mu.Lock()
+
+	id, err := c.send(op)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for {
+		res, err := c.receive(id)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case res.Is(ber.Application, AppSearchResultEntry):
+			entry, err := parseEntry(res)
+			if err != nil {
+				return nil, err
+			}
+
+			entries = append(entries, entry)
+
+		case res.Is(ber.Application, AppSearchResultReference):
+			// referrals to other servers are not followed
+
+		case res.Is(ber.Application, AppSearchResultDone):
+			if err := result(res); err != nil {
+				return nil, err
+			}
+
+			return entries, nil
+
+		default:
+			return nil, ber.ErrUnexpected
+		}
+	}
+}
+
+// StartTLS upgrades the connection to TLS, configured by config, which
+// must name the server. It must be done before any bind, so passwords
+// are never sent in the clear.
+func (c *Conn) StartTLS(config *tls.Config) error {
+	op := ber.Constructed(ber.Application, AppExtendedRequest,
+		ber.Primitive(ber.Context, 0, []byte(OIDStartTLS)),
+	)
+
+	defer c.mu.Unlock()
+	c.mu.Lock()
+
+	id, err := c.send(op)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.receive(id)
+	if err != nil {
+		return err
+	}
+
+	if !res.Is(ber.Application, AppExtendedResponse) {
+		return ber.ErrUnexpected
+	}
+
+	if err := result(res); err != nil {
+		return err
+	}
+
+	// the server sends nothing else until the handshake, so nothing
+	// is left buffered in the clear
+	conn := tls.Client(c.conn, config)
+	if c.timeout > 0 {
+		conn.SetDeadline(time.Now().Add(c.timeout))
+	}
+
+	if err := conn.Handshake(); err != nil {
+		return err
+	}
+
+	c.conn, c.r = conn, bufio.NewReader(conn)
+	return nil
+}
+
+// Close unbinds and closes the connection.
+func (c *Conn) Close() error {
+	defer c.mu.Unlock()
+	c.mu.Lock()
+
+	if c.closed {
+		return nil
+	}
+
+	c.send(ber.Primitive(ber.Application, AppUnbindRequest, nil))
+	c.closed = true
+	return c.conn.Close()
+}
+
+func (c *Conn) send(op *ber.Packet) (int64, error) {
+	if c.closed {
+		return 0, ErrClosed
+	}
+
+	c.id++
+	msg := ber.Sequence(ber.Int(c.id), op)
+
+	if c.timeout > 0 {
+		c.conn.SetDeadline(time.Now().Add(c.timeout))
+	}
+
+	if _, err := c.conn.Write(msg.Bytes()); err != nil {
+		return 0, err
+	}
+
+	return c.id, nil
+}
+
+// receive reads the next message, of the given ID, returning its
+// protocol operation.
+func (c *Conn) receive(id int64) (*ber.Packet, error) {
+	msg, err := ber.Read(c.r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !msg.Is(ber.Universal, ber.TagSequence) || len(msg.Children) < 2 {
+		return nil, ber.ErrMalformed
+	}
+
+	mid, err := msg.Children[0].Int()
+	if err != nil {
+		return nil, err
+	}
+
+	if mid != id {
+		return nil, fmt.Errorf("ldap: response to message %d, expected %d", mid, id)
+	}
+
+	return msg.Children[1], nil
+}
+
+// result returns the error of an LDAPResult, if any.
+func result(op *ber.Packet) error {
+	if len(op.Children) < 3 {
+		return ber.ErrMalformed
+	}
+
+	code, err := op.Children[0].Int()
+	if err != nil {
+		return err
+	}
+
+	if code != ResultSuccess {
+		return &Error{Code: int(code), Message: op.Children[2].String()}
+	}
+
+	return nil
+}
+
+func parseEntry(op *ber.Packet) (Entry, error) {
+	dn, err := op.Child(0, ber.Universal, ber.TagOctetString)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	attrs, err := op.Child(1, ber.Universal, ber.TagSequence)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry := Entry{DN: dn.String(), Attributes: make(map[string][]string, len(attrs.Children))}
+	for _, attr := range attrs.Children {
+		name, err := attr.Child(0, ber.Universal, ber.TagOctetString)
+		if err != nil {
+			return Entry{}, err
+		}
+
+		vals, err := attr.Child(1, ber.Universal, ber.TagSet)
+		if err != nil {
+			return Entry{}, err
+		}
+
+		values := make([]string, len(vals.Children))
+		for i, val := range vals.Children {
+			values[i] = val.String()
+		}
+
+		entry.Attributes[name.String()] = values
+	}
+
+	return entry, nil
+}
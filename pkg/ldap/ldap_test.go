@@ -0,0 +1,130 @@
+package ldap_test
+
+import (
+	"crypto/tls"
+	"testing"
+	"time"
+
+	. "github.com/alan-b-lima/almodon/pkg/ldap"
+	"github.com/alan-b-lima/almodon/pkg/ldap/ldaptest"
+)
+
+const base = "ou=people,dc=ufvjm,dc=edu,dc=br"
+
+func newServer() *ldaptest.Server {
+	srv := ldaptest.NewServer()
+	srv.Add(Entry{
+		DN: "uid=alan," + base,
+		Attributes: map[string][]string{
+			"uid":            {"alan"},
+			"cn":             {"Alan Barbosa Lima"},
+			"employeeNumber": {"1234567"},
+			"mail":           {"alan@ufvjm.edu.br"},
+		},
+	}, "secret")
+	srv.Add(Entry{
+		DN: "uid=breno," + base,
+		Attributes: map[string][]string{
+			"uid": {"breno"},
+			"cn":  {"Breno Oliveira"},
+		},
+	}, "other")
+
+	return srv
+}
+
+func TestBind(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+
+	conn, err := Dial(srv.URL, nil, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := conn.Bind("uid=alan,"+base, "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	err = conn.Bind("uid=alan,"+base, "wrong")
+	if !IsCode(err, ResultInvalidCredentials) {
+		t.Errorf("expected invalid credentials, got %v", err)
+	}
+
+	if err := conn.Bind("uid=alan,"+base, ""); err != ErrEmptyPassword {
+		t.Errorf("empty password should be refused, got %v", err)
+	}
+
+	if srv.Binds() != 1 {
+		t.Errorf("expected 1 bind to reach the server, got %d", srv.Binds())
+	}
+}
+
+func TestSearch(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+
+	conn, err := Dial(srv.URL, nil, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	entries, err := conn.Search(SearchRequest{
+		BaseDN:     base,
+		Scope:      ScopeSub,
+		Filter:     And(Equal("employeeNumber", "1234567"), Present("mail")),
+		Attributes: []string{"cn", "mail"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+
+	e := entries[0]
+	if e.DN != "uid=alan,"+base || e.Value("CN") != "Alan Barbosa Lima" || e.Value("uid") != "" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+
+	entries, err = conn.Search(SearchRequest{BaseDN: base, Scope: ScopeOne, Filter: Not(Equal("uid", "alan"))})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 || entries[0].Value("uid") != "breno" {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+}
+
+func TestStartTLS(t *testing.T) {
+	srv := newServer()
+	defer srv.Close()
+
+	conn, err := Dial(srv.URL, nil, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	config := &tls.Config{ServerName: "127.0.0.1"}
+	if err := conn.StartTLS(config); !IsCode(err, ResultProtocolError) {
+		t.Fatalf("StartTLS should be refused before it is enabled, got %v", err)
+	}
+
+	config.RootCAs = srv.StartTLS()
+	if err := conn.StartTLS(config); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.Bind("uid=alan,"+base, "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	if srv.Binds() != 1 {
+		t.Errorf("expected 1 bind to reach the server, got %d", srv.Binds())
+	}
+}
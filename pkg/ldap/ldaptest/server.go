@@ -0,0 +1,359 @@
+// Copyright (C) 2025 Alan Barbosa Lima.
+//
+// Almodon is licensed under the GNU General Public License
+// version 3. You should have received a copy of the
+// license, located in LICENSE, at the root of the source
+// tree. If not, see <https://www.gnu.org/licenses/>.
+
+// Package ldaptest provides an in-memory LDAP server, standing in for
+// a directory such as OpenLDAP in tests.
+package ldaptest
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alan-b-lima/almodon/pkg/ber"
+	"github.com/alan-b-lima/almodon/pkg/ldap"
+)
+
+// Server answers simple binds, searches and, once enabled, StartTLS,
+// over the entries added to it. Anonymous binds and searches are
+// allowed, as in a default OpenLDAP installation.
+type Server struct {
+	// URL is the address of the server, as in "ldap://127.0.0.1:389".
+	URL string
+
+	ln     net.Listener
+	wg     sync.WaitGroup
+	conns  map[net.Conn]struct{}
+	closed bool
+
+	mu        sync.Mutex
+	entries   []ldap.Entry
+	passwords map[string]string
+	binds     int
+	tls       *tls.Config
+}
+
+// NewServer starts a server on a random port of the loopback
+// interface.
+func NewServer() *Server {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic("ldaptest: " + err.Error())
+	}
+
+	s := &Server{
+		URL:       "ldap://" + ln.Addr().String(),
+		ln:        ln,
+		passwords: make(map[string]string),
+		conns:     make(map[net.Conn]struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s
+}
+
+// Add adds an entry, which binds with the password, if not empty.
+func (s *Server) Add(entry ldap.Entry, password string) {
+	defer s.mu.Unlock()
+	s.mu.Lock()
+
+	s.entries = append(s.entries, entry)
+	if password != "" {
+		s.passwords[normalize(entry.DN)] = password
+	}
+}
+
+// Binds returns how many binds succeeded, anonymous ones excluded.
+func (s *Server) Binds() int {
+	defer s.mu.Unlock()
+	s.mu.Lock()
+
+	return s.binds
+}
+
+// StartTLS enables the StartTLS operation, with a self-signed
+// certificate for the loopback address, returning the pool clients
+// must trust to verify it.
+func (s *Server) StartTLS() *x509.CertPool {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic("ldaptest: " + err.Error())
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		panic("ldaptest: " + err.Error())
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		panic("ldaptest: " + err.Error())
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	defer s.mu.Unlock()
+	s.mu.Lock()
+
+	s.tls = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	return pool
+}
+
+// Close stops the server, closing the connections still open.
+func (s *Server) Close() {
+	s.ln.Close()
+
+	s.mu.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+
+			s.handle(conn)
+
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+
+			conn.Close()
+		}()
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	r := bufio.NewReader(conn)
+
+	for {
+		msg, err := ber.Read(r)
+		if err != nil || len(msg.Children) < 2 {
+			return
+		}
+
+		id, err := msg.Children[0].Int()
+		if err != nil {
+			return
+		}
+
+		op := msg.Children[1]
+		switch {
+		case op.Is(ber.Application, ldap.AppBindRequest):
+			code := s.bind(op)
+			reply(conn, id, ldap.AppBindResponse, code)
+
+		case op.Is(ber.Application, ldap.AppSearchRequest):
+			entries, code := s.search(op)
+			for _, entry := range entries {
+				write(conn, id, entryPacket(&entry))
+			}
+			reply(conn, id, ldap.AppSearchResultDone, code)
+
+		case op.Is(ber.Application, ldap.AppExtendedRequest):
+			config := s.startTLS(op)
+			if config == nil {
+				reply(conn, id, ldap.AppExtendedResponse, ldap.ResultProtocolError)
+				continue
+			}
+
+			reply(conn, id, ldap.AppExtendedResponse, ldap.ResultSuccess)
+
+			tconn := tls.Server(conn, config)
+			if err := tconn.Handshake(); err != nil {
+				return
+			}
+
+			conn, r = tconn, bufio.NewReader(tconn)
+
+		default:
+			return
+		}
+	}
+}
+
+func (s *Server) bind(op *ber.Packet) int {
+	if len(op.Children) != 3 || !op.Children[2].Is(ber.Context, 0) {
+		return ldap.ResultInvalidCredentials
+	}
+
+	dn, password := op.Children[1].String(), op.Children[2].String()
+	if dn == "" && password == "" {
+		return ldap.ResultSuccess
+	}
+
+	defer s.mu.Unlock()
+	s.mu.Lock()
+
+	expected, in := s.passwords[normalize(dn)]
+	if !in || password == "" || expected != password {
+		return ldap.ResultInvalidCredentials
+	}
+
+	s.binds++
+	return ldap.ResultSuccess
+}
+
+// startTLS returns the configuration of TLS, if the operation is
+// StartTLS and it is enabled, nil otherwise.
+func (s *Server) startTLS(op *ber.Packet) *tls.Config {
+	if len(op.Children) < 1 || !op.Children[0].Is(ber.Context, 0) || op.Children[0].String() != ldap.OIDStartTLS {
+		return nil
+	}
+
+	defer s.mu.Unlock()
+	s.mu.Lock()
+
+	return s.tls
+}
+
+func (s *Server) search(op *ber.Packet) ([]ldap.Entry, int) {
+	if len(op.Children) != 8 {
+		return nil, ldap.ResultNoSuchObject
+	}
+
+	base := normalize(op.Children[0].String())
+	scope, err := op.Children[1].Int()
+	if err != nil {
+		return nil, ldap.ResultNoSuchObject
+	}
+
+	filter, err := ldap.ParseFilter(op.Children[6])
+	if err != nil {
+		return nil, ldap.ResultNoSuchObject
+	}
+
+	var attrs []string
+	for _, attr := range op.Children[7].Children {
+		attrs = append(attrs, attr.String())
+	}
+
+	defer s.mu.Unlock()
+	s.mu.Lock()
+
+	var entries []ldap.Entry
+	for _, entry := range s.entries {
+		if !inScope(normalize(entry.DN), base, ldap.Scope(scope)) || !filter.Match(&entry) {
+			continue
+		}
+
+		entries = append(entries, project(entry, attrs))
+	}
+
+	return entries, ldap.ResultSuccess
+}
+
+func inScope(dn, base string, scope ldap.Scope) bool {
+	switch scope {
+	case ldap.ScopeBase:
+		return dn == base
+	case ldap.ScopeOne:
+		_, parent, _ := strings.Cut(dn, ",")
+		return parent == base
+	}
+
+	return dn == base || strings.HasSuffix(dn, ","+base)
+}
+
+// project keeps only the requested attributes, all of them if none
+// is requested.
+func project(entry ldap.Entry, attrs []string) ldap.Entry {
+	if len(attrs) == 0 {
+		return entry
+	}
+
+	res := ldap.Entry{DN: entry.DN, Attributes: make(map[string][]string)}
+	for _, attr := range attrs {
+		if values := entry.Values(attr); values != nil {
+			res.Attributes[attr] = values
+		}
+	}
+
+	return res
+}
+
+func entryPacket(entry *ldap.Entry) *ber.Packet {
+	attrs := ber.Sequence()
+	for name, values := range entry.Attributes {
+		vals := ber.Set()
+		for _, value := range values {
+			vals.Children = append(vals.Children, ber.OctetString(value))
+		}
+
+		attrs.Children = append(attrs.Children, ber.Sequence(ber.OctetString(name), vals))
+	}
+
+	return ber.Constructed(ber.Application, ldap.AppSearchResultEntry, ber.OctetString(entry.DN), attrs)
+}
+
+func reply(conn net.Conn, id int64, tag byte, code int) {
+	write(conn, id, ber.Constructed(ber.Application, tag,
+		ber.Enum(int64(code)),
+		ber.OctetString(""),
+		ber.OctetString(""),
+	))
+}
+
+func write(conn net.Conn, id int64, op *ber.Packet) {
+	conn.Write(ber.Sequence(ber.Int(id), op).Bytes())
+}
+
+// normalize lowers the DN and drops spaces around its components,
+// which is enough for the DNs of tests.
+func normalize(dn string) string {
+	parts := strings.Split(strings.ToLower(dn), ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,189 @@
+// Copyright (C) 2025 Alan Barbosa Lima.
+//
+// Almodon is licensed under the GNU General Public License
+// version 3. You should have received a copy of the
+// license, located in LICENSE, at the root of the source
+// tree. If not, see <https://www.gnu.org/licenses/>.
+
+// Package oidc implements the relying party side of the OpenID Connect
+// authorization code flow, with PKCE, as of OpenID Connect Core 1.0
+// and RFC 7636: discovery, the exchange of codes for ID tokens and
+// their verification against the keys of the provider.
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrIssuerMismatch = errors.New("oidc: issuer does not match the discovered one")
+	ErrNoIDToken      = errors.New("oidc: token response has no ID token")
+)
+
+// Config identifies the application to the provider.
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+
+	// Scopes requested besides openid.
+	Scopes []string
+}
+
+// Provider is an OpenID provider, as described by its discovery
+// document.
+type Provider struct {
+	Issuer   string `json:"issuer"`
+	AuthURL  string `json:"authorization_endpoint"`
+	TokenURL string `json:"token_endpoint"`
+	JWKSURL  string `json:"jwks_uri"`
+
+	client *http.Client
+
+	mu      sync.Mutex
+	keys    map[string]any
+	fetched time.Time
+}
+
+// Discover fetches the discovery document of the issuer.
+func Discover(client *http.Client, issuer string) (*Provider, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Get(strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration")
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var p Provider
+	if err := decode(res, &p); err != nil {
+		return nil, err
+	}
+
+	if p.Issuer != issuer {
+		return nil, ErrIssuerMismatch
+	}
+
+	if p.AuthURL == "" || p.TokenURL == "" || p.JWKSURL == "" {
+		return nil, errors.New("oidc: discovery document lacks endpoints")
+	}
+
+	p.client = client
+	return &p, nil
+}
+
+// NewPKCE generates a code verifier and its S256 challenge.
+func NewPKCE() (verifier, challenge string) {
+	verifier = random()
+	sum := sha256.Sum256([]byte(verifier))
+
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// NewState generates a random value fit for states and nonces.
+func NewState() string {
+	return random()
+}
+
+func random() string {
+	var buf [32]byte
+	rand.Read(buf[:])
+
+	return base64.RawURLEncoding.EncodeToString(buf[:])
+}
+
+// AuthCodeURL returns the URL users are redirected to in order to log
+// in with the provider.
+func (p *Provider) AuthCodeURL(cfg Config, state, nonce, challenge string) string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", cfg.ClientID)
+	query.Set("redirect_uri", cfg.RedirectURL)
+	query.Set("scope", strings.Join(append([]string{"openid"}, cfg.Scopes...), " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", challenge)
+	query.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(p.AuthURL, "?") {
+		sep = "&"
+	}
+
+	return p.AuthURL + sep + query.Encode()
+}
+
+// Exchange trades the code the provider redirected back with for the
+// ID token, proving with the verifier that it was this client who
+// started the flow.
+func (p *Provider) Exchange(cfg Config, code, verifier string) (string, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", cfg.RedirectURL)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequest(http.MethodPost, p.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(cfg.ClientID), url.QueryEscape(cfg.ClientSecret))
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	var tok struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := decode(res, &tok); err != nil {
+		return "", err
+	}
+
+	if tok.IDToken == "" {
+		return "", ErrNoIDToken
+	}
+
+	return tok.IDToken, nil
+}
+
+// decode decodes the JSON body of a successful response, or returns
+// the error the provider reported.
+func decode(res *http.Response, v any) error {
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		var perr struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		if json.Unmarshal(body, &perr) == nil && perr.Error != "" {
+			return fmt.Errorf("oidc: %s: %s", perr.Error, perr.Description)
+		}
+
+		return fmt.Errorf("oidc: %s responded %s", res.Request.URL, res.Status)
+	}
+
+	return json.Unmarshal(body, v)
+}
@@ -0,0 +1,178 @@
+package oidc_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/alan-b-lima/almodon/pkg/oidc"
+	"github.com/alan-b-lima/almodon/pkg/oidc/oidctest"
+)
+
+func setup(t *testing.T) (*oidctest.Server, *Provider, Config) {
+	srv := oidctest.NewServer("almodon", "secret")
+	t.Cleanup(srv.Close)
+
+	p, err := Discover(nil, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return srv, p, Config{
+		ClientID:     "almodon",
+		ClientSecret: "secret",
+		RedirectURL:  "http://app.test/callback",
+		Scopes:       []string{"email", "profile"},
+	}
+}
+
+// login runs the flow up to the redirect back to the application,
+// returning the code and the state it came with.
+func login(t *testing.T, srv *oidctest.Server, p *Provider, cfg Config, state, nonce, challenge string) (string, string) {
+	res, err := srv.Client().Get(p.AuthCodeURL(cfg, state, nonce, challenge))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	loc, err := url.Parse(res.Header.Get("Location"))
+	if err != nil || !strings.HasPrefix(loc.String(), cfg.RedirectURL) {
+		t.Fatalf("expected a redirect to the application, got %d %q", res.StatusCode, res.Header.Get("Location"))
+	}
+
+	return loc.Query().Get("code"), loc.Query().Get("state")
+}
+
+func TestFlow(t *testing.T) {
+	srv, p, cfg := setup(t)
+	srv.SetClaims(map[string]any{
+		"sub":            "alan",
+		"email":          "alan@ufvjm.edu.br",
+		"email_verified": true,
+		"siape":          1234567,
+	})
+
+	state, nonce := NewState(), NewState()
+	verifier, challenge := NewPKCE()
+
+	code, back := login(t, srv, p, cfg, state, nonce, challenge)
+	if back != state {
+		t.Fatalf("expected state %q back, got %q", state, back)
+	}
+
+	token, err := p.Exchange(cfg, code, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := p.Verify(cfg, token, nonce, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.Subject != "alan" || claims.Email != "alan@ufvjm.edu.br" || claims.EmailVerified == nil || !*claims.EmailVerified {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+
+	if claims.String("siape") != "1234567" {
+		t.Errorf("expected siape claim, got %q", claims.String("siape"))
+	}
+
+	if _, err := p.Exchange(cfg, code, verifier); err == nil {
+		t.Error("codes should not be exchanged twice")
+	}
+}
+
+func TestExchangeVerifier(t *testing.T) {
+	srv, p, cfg := setup(t)
+	srv.SetClaims(map[string]any{"sub": "alan"})
+
+	_, challenge := NewPKCE()
+	other, _ := NewPKCE()
+
+	code, _ := login(t, srv, p, cfg, NewState(), NewState(), challenge)
+	if _, err := p.Exchange(cfg, code, other); err == nil {
+		t.Error("exchange with another verifier should fail")
+	}
+
+	cfg.ClientSecret = "wrong"
+	verifier, challenge := NewPKCE()
+	code, _ = login(t, srv, p, cfg, NewState(), NewState(), challenge)
+	if _, err := p.Exchange(cfg, code, verifier); err == nil {
+		t.Error("exchange with a wrong secret should fail")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	srv, p, cfg := setup(t)
+	now := time.Now()
+
+	claims := func(change func(map[string]any)) map[string]any {
+		c := map[string]any{
+			"iss":   srv.URL,
+			"aud":   "almodon",
+			"sub":   "alan",
+			"iat":   now.Unix(),
+			"exp":   now.Add(5 * time.Minute).Unix(),
+			"nonce": "nonce",
+		}
+		if change != nil {
+			change(c)
+		}
+
+		return c
+	}
+
+	if _, err := p.Verify(cfg, srv.Sign(claims(nil)), "nonce", now); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(map[string]any)
+	}{
+		{"issuer", func(c map[string]any) { c["iss"] = "https://evil.test" }},
+		{"audience", func(c map[string]any) { c["aud"] = "other" }},
+		{"authorized party", func(c map[string]any) { c["aud"] = []string{"almodon", "other"} }},
+		{"expired", func(c map[string]any) { c["exp"] = now.Add(-2 * time.Minute).Unix() }},
+		{"future", func(c map[string]any) { c["iat"] = now.Add(2 * time.Minute).Unix() }},
+		{"nonce", func(c map[string]any) { c["nonce"] = "other" }},
+		{"subject", func(c map[string]any) { delete(c, "sub") }},
+	}
+
+	for _, test := range tests {
+		_, err := p.Verify(cfg, srv.Sign(claims(test.change)), "nonce", now)
+		if !errors.Is(err, ErrClaims) {
+			t.Errorf("%s: expected claims error, got %v", test.name, err)
+		}
+	}
+
+	many := claims(func(c map[string]any) {
+		c["aud"] = []string{"almodon", "other"}
+		c["azp"] = "almodon"
+	})
+	if _, err := p.Verify(cfg, srv.Sign(many), "nonce", now); err != nil {
+		t.Errorf("many audiences with authorized party should pass, got %v", err)
+	}
+
+	token := srv.Sign(claims(nil))
+	parts := strings.Split(token, ".")
+
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"x"}`))
+	if _, err := p.Verify(cfg, parts[0]+"."+forged+"."+parts[2], "nonce", now); err != ErrSignature {
+		t.Errorf("expected signature error, got %v", err)
+	}
+
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"oidctest"}`))
+	if _, err := p.Verify(cfg, none+"."+parts[1]+".", "nonce", now); err != ErrAlgorithm {
+		t.Errorf("expected algorithm error, got %v", err)
+	}
+
+	unknown := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","kid":"other"}`))
+	if _, err := p.Verify(cfg, unknown+"."+parts[1]+"."+parts[2], "nonce", now); err != ErrUnknownKey {
+		t.Errorf("expected unknown key error, got %v", err)
+	}
+}
@@ -0,0 +1,328 @@
+// Copyright (C) 2025 Alan Barbosa Lima.
+//
+// Almodon is licensed under the GNU General Public License
+// version 3. You should have received a copy of the
+// license, located in LICENSE, at the root of the source
+// tree. If not, see <https://www.gnu.org/licenses/>.
+
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Skew is the clock difference to the provider tolerated when checking
+// the times of tokens.
+const Skew = time.Minute
+
+// refetchAfter is how long the keys are kept before a token signed by
+// an unknown key makes them be fetched again.
+const refetchAfter = 10 * time.Second
+
+var (
+	ErrMalformedToken = errors.New("oidc: malformed ID token")
+	ErrAlgorithm      = errors.New("oidc: ID token signed with an unsupported algorithm")
+	ErrUnknownKey     = errors.New("oidc: ID token signed by an unknown key")
+	ErrSignature      = errors.New("oidc: ID token signature is invalid")
+	ErrClaims         = errors.New("oidc: ID token claims are invalid")
+)
+
+// Claims are the claims of an ID token the package checks or that are
+// commonly mapped to users, the others are kept in Raw.
+type Claims struct {
+	Issuer          string   `json:"iss"`
+	Subject         string   `json:"sub"`
+	Audience        Audience `json:"aud"`
+	AuthorizedParty string   `json:"azp"`
+	Expiry          int64    `json:"exp"`
+	IssuedAt        int64    `json:"iat"`
+	Nonce           string   `json:"nonce"`
+
+	// EmailVerified is nil if the provider does not state whether the
+	// email was verified.
+	Email         string `json:"email"`
+	EmailVerified *bool  `json:"email_verified"`
+	Name          string `json:"name"`
+
+	Raw map[string]any `json:"-"`
+}
+
+// String returns the claim as a string, numbers included, or the
+// empty string if it is missing or of another type.
+func (c *Claims) String(claim string) string {
+	switch v := c.Raw[claim].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	return ""
+}
+
+// Audience is the aud claim, either a single string or an array.
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(buf []byte) error {
+	var single string
+	if err := json.Unmarshal(buf, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(buf, &many); err != nil {
+		return err
+	}
+
+	*a = many
+	return nil
+}
+
+// Verify checks the signature of the ID token against the keys of the
+// provider and its claims against the configuration and the nonce the
+// flow was started with, returning them.
+func (p *Provider) Verify(cfg Config, token, nonce string, now time.Time) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, ErrMalformedToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return Claims{}, err
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, ErrMalformedToken
+	}
+
+	key, err := p.key(header.Kid, now)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := verify(header.Alg, key, digest[:], sig); err != nil {
+		return Claims{}, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Claims{}, err
+	}
+	if err := decodeSegment(parts[1], &claims.Raw); err != nil {
+		return Claims{}, err
+	}
+
+	if err := p.check(cfg, &claims, nonce, now); err != nil {
+		return Claims{}, err
+	}
+
+	return claims, nil
+}
+
+func (p *Provider) check(cfg Config, c *Claims, nonce string, now time.Time) error {
+	switch {
+	case c.Issuer != p.Issuer:
+		return fmt.Errorf("%w: issuer %q", ErrClaims, c.Issuer)
+	case !slices.Contains(c.Audience, cfg.ClientID):
+		return fmt.Errorf("%w: audience %v", ErrClaims, c.Audience)
+	case len(c.Audience) > 1 && c.AuthorizedParty != cfg.ClientID:
+		return fmt.Errorf("%w: authorized party %q", ErrClaims, c.AuthorizedParty)
+	case c.Subject == "":
+		return fmt.Errorf("%w: no subject", ErrClaims)
+	case now.After(time.Unix(c.Expiry, 0).Add(Skew)):
+		return fmt.Errorf("%w: expired", ErrClaims)
+	case now.Add(Skew).Before(time.Unix(c.IssuedAt, 0)):
+		return fmt.Errorf("%w: issued in the future", ErrClaims)
+	case c.Nonce != nonce:
+		return fmt.Errorf("%w: nonce", ErrClaims)
+	}
+
+	return nil
+}
+
+func verify(alg string, key any, digest, sig []byte) error {
+	switch alg {
+	case "RS256":
+		key, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return ErrAlgorithm
+		}
+
+		if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, sig) != nil {
+			return ErrSignature
+		}
+
+	case "ES256":
+		key, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(sig) != 64 {
+			return ErrAlgorithm
+		}
+
+		r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+		if !ecdsa.Verify(key, digest, r, s) {
+			return ErrSignature
+		}
+
+	default:
+		return ErrAlgorithm
+	}
+
+	return nil
+}
+
+// key returns the key of the given ID, fetching the keys of the
+// provider if they were not yet, or if the key is unknown and they
+// were not fetched too recently, as providers rotate keys.
+func (p *Provider) key(kid string, now time.Time) (any, error) {
+	defer p.mu.Unlock()
+	p.mu.Lock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	if p.keys != nil && now.Sub(p.fetched) < refetchAfter {
+		return nil, ErrUnknownKey
+	}
+
+	keys, err := p.fetchKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	p.keys, p.fetched = keys, now
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	return nil, ErrUnknownKey
+}
+
+// JWK is a JSON web key, as of RFC 7517, of the types the package
+// verifies signatures with.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+func (p *Provider) fetchKeys() (map[string]any, error) {
+	res, err := p.client.Get(p.JWKSURL)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var set struct {
+		Keys []JWK `json:"keys"`
+	}
+	if err := decode(res, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		// keys of unsupported types are skipped, tokens signed with
+		// them fail as signed by unknown keys
+		if key, err := jwk.PublicKey(); err == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+
+	return keys, nil
+}
+
+// PublicKey decodes the key, RSA or P-256.
+func (k *JWK) PublicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) > 4 {
+			return nil, errors.New("oidc: malformed RSA exponent")
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("oidc: unsupported curve %q", k.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		key := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("oidc: EC point is not on the curve")
+		}
+
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("oidc: unsupported key type %q", k.Kty)
+}
+
+func decodeSegment(seg string, v any) error {
+	buf, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return ErrMalformedToken
+	}
+
+	if err := json.Unmarshal(buf, v); err != nil {
+		return ErrMalformedToken
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright (C) 2025 Alan Barbosa Lima.
+//
+// Almodon is licensed under the GNU General Public License
+// version 3. You should have received a copy of the
+// license, located in LICENSE, at the root of the source
+// tree. If not, see <https://www.gnu.org/licenses/>.
+
+// Package throttle implements a failure counter that locks keys out
+// for exponentially growing windows, as used against brute-force
+// attacks.
+package throttle
+
+import (
+	"sync"
+	"time"
+)
+
+// Policy defines when and for how long a key is locked out.
+//
+// After Threshold consecutive failures, each new failure locks the
+// key for Base, doubling at every further failure, up to Max. A key
+// with no failures for Forget is forgotten altogether.
+type Policy struct {
+	Threshold int
+	Base      time.Duration
+	Max       time.Duration
+	Forget    time.Duration
+}
+
+// Throttle counts failures per key and locks keys out according to
+// its policy. The zero value is not usable, use [New].
+//
+// Throttle is safe for concurrent access by multiple goroutines.
+type Throttle[K comparable] struct {
+	policy  Policy
+	entries map[K]entry
+
+	sweep time.Time
+	mu    sync.Mutex
+}
+
+type entry struct {
+	failures int
+	last     time.Time
+	until    time.Time
+}
+
+// New creates a new throttle with the given policy.
+func New[K comparable](policy Policy) *Throttle[K] {
+	return &Throttle[K]{
+		policy:  policy,
+		entries: make(map[K]entry),
+	}
+}
+
+// Check returns until when the key is locked out, if it is locked
+// out at time now.
+func (t *Throttle[K]) Check(key K, now time.Time) (time.Time, bool) {
+	defer t.mu.Unlock()
+	t.mu.Lock()
+
+	e, in := t.entries[key]
+	if !in || !now.Before(e.until) {
+		return time.Time{}, false
+	}
+
+	return e.until, true
+}
+
+// Fail records a failure of the key at time now, and returns until
+// when the key got locked out, if it did.
+func (t *Throttle[K]) Fail(key K, now time.Time) (time.Time, bool) {
+	defer t.mu.Unlock()
+	t.mu.Lock()
+
+	t.forget(now)
+
+	e := t.entries[key]
+	if !e.last.IsZero() && now.Sub(e.last) >= t.policy.Forget {
+		e = entry{}
+	}
+
+	e.failures++
+	e.last = now
+
+	if over := e.failures - t.policy.Threshold; over >= 0 {
+		e.until = now.Add(t.window(over))
+	}
+
+	t.entries[key] = e
+
+	if now.Before(e.until) {
+		return e.until, true
+	}
+
+	return time.Time{}, false
+}
+
+// Reset forgets every failure of the key, unlocking it.
+func (t *Throttle[K]) Reset(key K) {
+	defer t.mu.Unlock()
+	t.mu.Lock()
+
+	delete(t.entries, key)
+}
+
+func (t *Throttle[K]) window(over int) time.Duration {
+	window := t.policy.Base
+	for range over {
+		window *= 2
+		if window >= t.policy.Max {
+			return t.policy.Max
+		}
+	}
+
+	return min(window, t.policy.Max)
+}
+
+// forget drops entries that are no longer relevant, at most once per
+// forget period, so that the map does not grow unbounded.
+func (t *Throttle[K]) forget(now time.Time) {
+	if now.Sub(t.sweep) < t.policy.Forget {
+		return
+	}
+	t.sweep = now
+
+	for key, e := range t.entries {
+		if now.Sub(e.last) >= t.policy.Forget && !now.Before(e.until) {
+			delete(t.entries, key)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package throttle_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/alan-b-lima/almodon/pkg/throttle"
+)
+
+var policy = Policy{
+	Threshold: 3,
+	Base:      time.Minute,
+	Max:       10 * time.Minute,
+	Forget:    time.Hour,
+}
+
+func TestLockoutGrowsExponentially(t *testing.T) {
+	th := New[string](policy)
+	now := time.Date(2025, time.September, 1, 8, 0, 0, 0, time.UTC)
+
+	for i := range policy.Threshold - 1 {
+		if _, locked := th.Fail("key", now); locked {
+			t.Fatalf("key should not be locked after %d failures", i+1)
+		}
+	}
+
+	expected := []time.Duration{1, 2, 4, 8, 10, 10}
+	for _, minutes := range expected {
+		until, locked := th.Fail("key", now)
+		if !locked {
+			t.Fatal("key should be locked")
+		}
+
+		window := until.Sub(now)
+		if window != minutes*time.Minute {
+			t.Errorf("expected a window of %v, got %v", minutes*time.Minute, window)
+		}
+
+		if _, locked := th.Check("key", now.Add(window-time.Second)); !locked {
+			t.Error("key should still be locked before the window ends")
+		}
+
+		now = until
+		if _, locked := th.Check("key", now); locked {
+			t.Error("key should be unlocked once the window ends")
+		}
+	}
+
+	if _, locked := th.Check("other", now); locked {
+		t.Error("other keys should not be affected")
+	}
+}
+
+func TestResetAndForget(t *testing.T) {
+	th := New[int](policy)
+	now := time.Date(2025, time.September, 1, 8, 0, 0, 0, time.UTC)
+
+	for range policy.Threshold {
+		th.Fail(1, now)
+	}
+
+	if _, locked := th.Check(1, now); !locked {
+		t.Fatal("key should be locked")
+	}
+
+	th.Reset(1)
+	if _, locked := th.Check(1, now); locked {
+		t.Error("key should be unlocked after a reset")
+	}
+
+	for range policy.Threshold - 1 {
+		th.Fail(1, now)
+	}
+
+	now = now.Add(policy.Forget)
+	if _, locked := th.Fail(1, now); locked {
+		t.Error("failures older than the forget period should not count")
+	}
+}
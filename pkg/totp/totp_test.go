@@ -0,0 +1,78 @@
+package totp_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/alan-b-lima/almodon/pkg/totp"
+)
+
+var secret = []byte("12345678901234567890")
+
+func TestHOTP(t *testing.T) {
+	// RFC 4226, Appendix D
+	expected := []string{
+		"755224", "287082", "359152", "969429", "338314",
+		"254676", "287922", "162583", "399871", "520489",
+	}
+
+	for counter, code := range expected {
+		if got := HOTP(secret, int64(counter)); got != code {
+			t.Errorf("counter %d: expected %s, got %s", counter, code, got)
+		}
+	}
+}
+
+func TestCode(t *testing.T) {
+	// RFC 6238, Appendix B, SHA1, truncated to 6 digits
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, test := range tests {
+		if got := Code(secret, time.Unix(test.unix, 0)); got != test.code {
+			t.Errorf("time %d: expected %s, got %s", test.unix, test.code, got)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	code := Code(secret, now)
+
+	step, ok := Verify(secret, code, now.Add(Period), 1)
+	if !ok {
+		t.Fatal("code of the previous step should be accepted")
+	}
+	if step != Step(now) {
+		t.Errorf("expected step %d, got %d", Step(now), step)
+	}
+
+	if _, ok := Verify(secret, code, now.Add(2*Period), 1); ok {
+		t.Error("code two steps old should be refused")
+	}
+
+	if _, ok := Verify(secret, "12345", now, 1); ok {
+		t.Error("code of the wrong length should be refused")
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Almodon", "user@example.com", secret)
+
+	if !strings.HasPrefix(uri, "otpauth://totp/Almodon:user@example.com?") {
+		t.Errorf("unexpected label in %s", uri)
+	}
+	if !strings.Contains(uri, "secret="+Encode(secret)) {
+		t.Errorf("secret missing from %s", uri)
+	}
+}
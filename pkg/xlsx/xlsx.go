@@ -0,0 +1,300 @@
+// Copyright (C) 2025 Alan Barbosa Lima.
+//
+// Almodon is licensed under the GNU General Public License
+// version 3. You should have received a copy of the
+// license, located in LICENSE, at the root of the source
+// tree. If not, see <https://www.gnu.org/licenses/>.
+
+// Package xlsx implements a streaming writer of single-sheet Office
+// Open XML spreadsheets (SpreadsheetML), built solely on top of the
+// [archive/zip] and [encoding/xml] packages.
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Writer writes a spreadsheet row by row, nothing but the current row
+// is kept in memory. Strings are written inline, so no shared string
+// table has to be built.
+//
+// Writer is NOT safe for concurrent access by multiple goroutines.
+type Writer struct {
+	zw  *zip.Writer
+	bw  *bufio.Writer
+	row int
+
+	closed bool
+}
+
+var (
+	ErrClosed          = errors.New("xlsx: writer is closed")
+	ErrUnsupportedType = errors.New("xlsx: unsupported cell type")
+)
+
+// NewWriter creates a new spreadsheet, with a single sheet of the
+// given name, that is written to w. The [Writer.Close] method must be
+// called to finish the file. Sheet names are limited to 31 characters
+// and some are not allowed in them, so the name is cut short and
+// those characters are replaced, see [SheetName].
+func NewWriter(w io.Writer, sheet string) (*Writer, error) {
+	zw := zip.NewWriter(w)
+
+	var name bytesWriter
+	if err := xml.EscapeText(&name, []byte(SheetName(sheet))); err != nil {
+		return nil, err
+	}
+
+	parts := [...]struct{ name, content string }{
+		{"[Content_Types].xml", _ContentTypes},
+		{"_rels/.rels", _Rels},
+		{"xl/workbook.xml", fmt.Sprintf(_Workbook, name)},
+		{"xl/_rels/workbook.xml.rels", _WorkbookRels},
+		{"xl/styles.xml", _Styles},
+	}
+
+	for _, part := range parts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	bw := bufio.NewWriter(f)
+	if _, err := io.WriteString(bw, _SheetStart); err != nil {
+		return nil, err
+	}
+
+	return &Writer{zw: zw, bw: bw}, nil
+}
+
+// SheetName returns the name made valid as a sheet name, replacing
+// the characters spreadsheets do not allow in them by underscores and
+// cutting it at 31 characters. An empty name becomes "Sheet1".
+func SheetName(name string) string {
+	const maxLen = 31
+
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '\\', '/', '?', '*', '[', ']':
+			return '_'
+		}
+		return r
+	}, name)
+
+	if runes := []rune(name); len(runes) > maxLen {
+		name = string(runes[:maxLen])
+	}
+
+	// names may not start or end with an apostrophe either
+	name = strings.Trim(name, "'")
+
+	if name == "" {
+		return "Sheet1"
+	}
+
+	return name
+}
+
+// WriteRow appends a row to the sheet. Accepted cell values are
+// strings, booleans, integers, floats, [time.Time] and nil, which
+// leaves the cell empty.
+func (w *Writer) WriteRow(cells ...any) error {
+	if w.closed {
+		return ErrClosed
+	}
+
+	w.row++
+	fmt.Fprintf(w.bw, `<row r="%d">`, w.row)
+
+	for i, cell := range cells {
+		if err := w.writeCell(reference(i, w.row), cell); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w.bw, `</row>`)
+	return err
+}
+
+// Close finishes the sheet and the underlying zip file. It does not
+// close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return ErrClosed
+	}
+	w.closed = true
+
+	if _, err := io.WriteString(w.bw, _SheetEnd); err != nil {
+		return err
+	}
+
+	if err := w.bw.Flush(); err != nil {
+		return err
+	}
+
+	return w.zw.Close()
+}
+
+func (w *Writer) writeCell(ref string, cell any) error {
+	switch v := cell.(type) {
+	case nil:
+		return nil
+
+	case string:
+		fmt.Fprintf(w.bw, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+		if err := xml.EscapeText(w.bw, []byte(v)); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w.bw, `</t></is></c>`)
+		return err
+
+	case time.Time:
+		return w.writeNumber(ref, strconv.FormatFloat(serial(v), 'f', -1, 64), _StyleDateTime)
+
+	case fmt.Stringer:
+		return w.writeCell(ref, v.String())
+
+	case bool:
+		b := 0
+		if v {
+			b = 1
+		}
+		_, err := fmt.Fprintf(w.bw, `<c r="%s" t="b"><v>%d</v></c>`, ref, b)
+		return err
+
+	case int:
+		return w.writeNumber(ref, strconv.FormatInt(int64(v), 10), 0)
+	case int8:
+		return w.writeNumber(ref, strconv.FormatInt(int64(v), 10), 0)
+	case int16:
+		return w.writeNumber(ref, strconv.FormatInt(int64(v), 10), 0)
+	case int32:
+		return w.writeNumber(ref, strconv.FormatInt(int64(v), 10), 0)
+	case int64:
+		return w.writeNumber(ref, strconv.FormatInt(v, 10), 0)
+	case uint:
+		return w.writeNumber(ref, strconv.FormatUint(uint64(v), 10), 0)
+	case uint8:
+		return w.writeNumber(ref, strconv.FormatUint(uint64(v), 10), 0)
+	case uint16:
+		return w.writeNumber(ref, strconv.FormatUint(uint64(v), 10), 0)
+	case uint32:
+		return w.writeNumber(ref, strconv.FormatUint(uint64(v), 10), 0)
+	case uint64:
+		return w.writeNumber(ref, strconv.FormatUint(v, 10), 0)
+
+	case float32:
+		return w.writeFloat(ref, float64(v))
+	case float64:
+		return w.writeFloat(ref, v)
+
+	}
+
+	return fmt.Errorf("%w: %T", ErrUnsupportedType, cell)
+}
+
+func (w *Writer) writeFloat(ref string, v float64) error {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return w.writeCell(ref, strconv.FormatFloat(v, 'g', -1, 64))
+	}
+
+	return w.writeNumber(ref, strconv.FormatFloat(v, 'f', -1, 64), 0)
+}
+
+func (w *Writer) writeNumber(ref, num string, style int) error {
+	var err error
+	if style == 0 {
+		_, err = fmt.Fprintf(w.bw, `<c r="%s"><v>%s</v></c>`, ref, num)
+	} else {
+		_, err = fmt.Fprintf(w.bw, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style, num)
+	}
+
+	return err
+}
+
+// reference returns the A1-style reference of a cell given its
+// zero-based column and one-based row.
+func reference(col, row int) string {
+	var buf [8]byte
+	i := len(buf)
+
+	for col++; col > 0; col = (col - 1) / 26 {
+		i--
+		buf[i] = byte('A' + (col-1)%26)
+	}
+
+	return string(buf[i:]) + strconv.Itoa(row)
+}
+
+var epoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+// serial converts a time to a spreadsheet date serial number, the
+// time is taken as is, in its own location.
+func serial(t time.Time) float64 {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return wall.Sub(epoch).Hours() / 24
+}
+
+type bytesWriter []byte
+
+func (b *bytesWriter) Write(p []byte) (int, error) {
+	*b = append(*b, p...)
+	return len(p), nil
+}
+
+func (b bytesWriter) String() string { return string(b) }
+
+const _StyleDateTime = 1
+
+const _ContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const _Rels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const _Workbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets>` +
+	`</workbook>`
+
+const _WorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+const _Styles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="dd/mm/yyyy hh:mm"/></numFmts>` +
+	`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/></cellXfs>` +
+	`</styleSheet>`
+
+const _SheetStart = xml.Header + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+const _SheetEnd = `</sheetData></worksheet>`
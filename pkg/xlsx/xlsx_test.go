@@ -0,0 +1,121 @@
+package xlsx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+	"time"
+
+	. "github.com/alan-b-lima/almodon/pkg/xlsx"
+)
+
+type sheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R      string `xml:"r,attr"`
+			T      string `xml:"t,attr"`
+			V      string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func TestWrittenSheetIsReadable(t *testing.T) {
+	var buf bytes.Buffer
+
+	w, err := NewWriter(&buf, "Consumo <Setembro>")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := [][]any{
+		{"item", "quantity", "price", "date"},
+		{"Luva & Máscara", 12, 3.5, time.Date(2025, time.September, 1, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, row := range rows {
+		if err := w.WriteRow(row...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := zr.Open("xl/worksheets/sheet1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s sheet
+	if err := xml.Unmarshal(content, &s); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(s.Rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(s.Rows))
+	}
+
+	cells := s.Rows[1].Cells
+	expected := []struct{ ref, value string }{
+		{"A2", "Luva & Máscara"},
+		{"B2", "12"},
+		{"C2", "3.5"},
+		{"D2", "45901.5"},
+	}
+
+	for i, e := range expected {
+		value := cells[i].V
+		if cells[i].T == "inlineStr" {
+			value = cells[i].Inline
+		}
+
+		if cells[i].R != e.ref || value != e.value {
+			t.Errorf("expected %s=%q, got %s=%q", e.ref, e.value, cells[i].R, value)
+		}
+	}
+}
+
+func TestWriteAfterClose(t *testing.T) {
+	w, err := NewWriter(io.Discard, "sheet")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.WriteRow("late"); err != ErrClosed {
+		t.Errorf("expected %v, got %v", ErrClosed, err)
+	}
+}
+
+func TestSheetName(t *testing.T) {
+	tests := map[string]string{
+		"Consumo <Setembro>":                            "Consumo <Setembro>",
+		"2025/09: consumo [item]?":                      "2025_09_ consumo _item__",
+		"consumption-cost-center-2025-09-01_2025-09-15": "consumption-cost-center-2025-09",
+		"'quoted'": "quoted",
+		"":         "Sheet1",
+	}
+
+	for in, want := range tests {
+		if got := SheetName(in); got != want {
+			t.Errorf("SheetName(%q) = %q, expected %q", in, got, want)
+		}
+	}
+}